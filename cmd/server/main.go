@@ -6,21 +6,39 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/nokka/d2-armory-api/internal/account"
+	"github.com/nokka/d2-armory-api/internal/broker"
 	"github.com/nokka/d2-armory-api/internal/character"
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2-armory-api/internal/graveyard"
+	"github.com/nokka/d2-armory-api/internal/history"
 	"github.com/nokka/d2-armory-api/internal/httpserver"
+	"github.com/nokka/d2-armory-api/internal/item"
+	"github.com/nokka/d2-armory-api/internal/ladder"
 	"github.com/nokka/d2-armory-api/internal/mgo"
 	"github.com/nokka/d2-armory-api/internal/parsing"
+	"github.com/nokka/d2-armory-api/internal/progress"
+	"github.com/nokka/d2-armory-api/internal/stash"
 	"github.com/nokka/d2-armory-api/internal/statistics"
+	"github.com/nokka/d2-armory-api/internal/stats"
+	"github.com/nokka/d2-armory-api/internal/watcher"
+	"github.com/nokka/d2-armory-api/internal/webhook"
 	"github.com/nokka/d2-armory-api/pkg/env"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+// realmRegexp restricts realm names to what can be used in a route.
+var realmRegexp = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
 func main() {
 	var (
 		httpAddress        = env.String("HTTP_ADDRESS", ":80")
@@ -29,12 +47,27 @@ func main() {
 		mongoUsername      = env.String("MONGO_USERNAME", "")
 		mongoPassword      = env.String("MONGO_PASSWORD", "")
 		d2sPath            = env.String("D2S_PATH", "")
+		defaultRealm       = env.String("DEFAULT_REALM", "default")
+		extraRealms        = env.String("REALMS", "")
 		cacheDuration      = env.String("CACHE_DURATION", "3m")
 		statisticsUser     = env.String("STATISTICS_USER", "")
 		statisticsPassword = env.String("STATISTICS_PASSWORD", "")
 		corsEnabled        = env.String("CORS_ENABLED", "false")
 		logRequests        = env.String("LOG_REQUESTS", "false")
 		metricsInterval    = env.String("METRICS_INTERVAL", "5m")
+		watchEnabled       = env.String("WATCH_ENABLED", "false")
+		watchPolling       = env.String("WATCH_POLLING", "false")
+		watchDebounce      = env.String("WATCH_DEBOUNCE", "2s")
+		watchPollInterval  = env.String("WATCH_POLL_INTERVAL", "10s")
+		serveStale         = env.String("SERVE_STALE", "false")
+		maxStaleness       = env.String("MAX_STALENESS", "1h")
+		accountsFile       = env.String("ACCOUNTS_FILE", "")
+		reconcileInterval  = env.String("RECONCILE_INTERVAL", "10m")
+		accountsPath       = env.String("ACCOUNTS_PATH", "")
+		webhooksFile       = env.String("WEBHOOKS_FILE", "")
+		webhookInterval    = env.String("WEBHOOK_POLL_INTERVAL", "5s")
+		webhookAttempts    = env.String("WEBHOOK_MAX_ATTEMPTS", "8")
+		progressWindows    = env.String("PROGRESS_WINDOWS", "1h,1d,7d")
 	)
 
 	if d2sPath == "" {
@@ -42,6 +75,12 @@ func main() {
 		os.Exit(0)
 	}
 
+	realms, err := parseRealms(defaultRealm, d2sPath, extraRealms)
+	if err != nil {
+		log.Printf("failed to parse realms, %s", err)
+		os.Exit(0)
+	}
+
 	if statisticsUser == "" {
 		log.Println("statistics credentials user is missing")
 		os.Exit(0)
@@ -76,6 +115,79 @@ func main() {
 		os.Exit(0)
 	}
 
+	watching, err := strconv.ParseBool(watchEnabled)
+	if err != nil {
+		log.Printf("failed to parse watch enabled, %s", err)
+		os.Exit(0)
+	}
+
+	polling, err := strconv.ParseBool(watchPolling)
+	if err != nil {
+		log.Printf("failed to parse watch polling, %s", err)
+		os.Exit(0)
+	}
+
+	wd, err := time.ParseDuration(watchDebounce)
+	if err != nil {
+		log.Printf("failed to parse watch debounce, %s", err)
+		os.Exit(0)
+	}
+
+	wpi, err := time.ParseDuration(watchPollInterval)
+	if err != nil {
+		log.Printf("failed to parse watch poll interval, %s", err)
+		os.Exit(0)
+	}
+
+	ri, err := time.ParseDuration(reconcileInterval)
+	if err != nil {
+		log.Printf("failed to parse reconcile interval, %s", err)
+		os.Exit(0)
+	}
+
+	whi, err := time.ParseDuration(webhookInterval)
+	if err != nil {
+		log.Printf("failed to parse webhook poll interval, %s", err)
+		os.Exit(0)
+	}
+
+	wha, err := strconv.Atoi(webhookAttempts)
+	if err != nil || wha < 1 {
+		log.Printf("failed to parse webhook max attempts, %s", webhookAttempts)
+		os.Exit(0)
+	}
+
+	var pw []time.Duration
+	for _, value := range strings.Split(progressWindows, ",") {
+		window, err := domain.ParseWindow(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("failed to parse progress windows, %s", err)
+			os.Exit(0)
+		}
+		pw = append(pw, window)
+	}
+
+	var webhookTargets []domain.WebhookTarget
+	if webhooksFile != "" {
+		webhookTargets, err = webhook.ReadTargets(webhooksFile)
+		if err != nil {
+			log.Printf("failed to read webhook targets, %s", err)
+			os.Exit(0)
+		}
+	}
+
+	stale, err := strconv.ParseBool(serveStale)
+	if err != nil {
+		log.Printf("failed to parse serve stale, %s", err)
+		os.Exit(0)
+	}
+
+	ms, err := time.ParseDuration(maxStaleness)
+	if err != nil {
+		log.Printf("failed to parse max staleness, %s", err)
+		os.Exit(0)
+	}
+
 	clientOptions := options.Client().ApplyURI("mongodb://" + mongoDBHost)
 
 	// If a username is supplied, auth with it.
@@ -110,28 +222,207 @@ func main() {
 
 	log.Println("connected to mongodb")
 
+	// Characters stored before realms existed belong to the default realm.
+	if err := mgo.AssignRealm(mgoCtx, databaseName, defaultRealm, client); err != nil {
+		log.Println("failed to assign characters to the default realm", err)
+		os.Exit(0)
+	}
+
+	// Channel to receive errors on.
+	errorChannel := make(chan error)
+
+	// Events are kept in the outbox until they're delivered, so they survive restarts.
+	outboxRepository := mgo.NewOutboxRepository(databaseName, client)
+	webhookService := webhook.NewService(outboxRepository, webhookTargets)
+
+	if len(webhookTargets) > 0 {
+		go func() {
+			d := webhook.NewDispatcher(outboxRepository, webhookTargets, whi, wha)
+			errorChannel <- d.Run(context.Background())
+		}()
+	}
+
+	// Every realm gets its own parser, repositories and services.
+	characterServices := make(map[string]*character.Service, len(realms))
+	historyServices := make(map[string]*history.Service, len(realms))
+	stashServices := make(map[string]*stash.Service, len(realms))
+	accountServices := make(map[string]*account.Service, len(realms))
+	graveyardServices := make(map[string]*graveyard.Service, len(realms))
+	eventBrokers := make(map[string]*broker.Broker, len(realms))
+	itemServices := make(map[string]*item.Service, len(realms))
+	ladderServices := make(map[string]*ladder.Service, len(realms))
+	progressServices := make(map[string]*progress.Service, len(realms))
+	statsServices := make(map[string]*stats.Service, len(realms))
+
+	for _, realm := range realms {
+		parser := parsing.NewParser(realm)
+		historyRepository := mgo.NewHistoryRepository(databaseName, realm.Name, client)
+		historyService := history.NewService(historyRepository)
+		progressService := progress.NewService(
+			historyRepository,
+			progress.WithRealm(realm.Name),
+			progress.WithWindows(pw...),
+		)
+		graveyardService := graveyard.NewService(mgo.NewGraveyardRepository(databaseName, realm.Name, client))
+		eventBroker := broker.NewBroker()
+		characterRepository := mgo.NewCharacterRepository(databaseName, realm.Name, client)
+		itemService := item.NewService(
+			mgo.NewItemRepository(databaseName, realm.Name, client),
+			item.WithCharacterRepository(characterRepository),
+		)
+
+		characterOptions := []character.Option{
+			character.WithRealm(realm.Name),
+			character.WithListener(historyService),
+			// Progress is computed from the history, so it listens after the snapshot is recorded.
+			character.WithListener(progressService),
+			character.WithListener(graveyardService),
+			character.WithListener(webhookService),
+			character.WithListener(eventBroker),
+			character.WithListener(itemService),
+			character.WithDeleteListener(itemService),
+		}
+		if watching {
+			characterOptions = append(characterOptions, character.WithWatcher())
+		}
+		if stale {
+			characterOptions = append(characterOptions, character.WithStaleWhileRevalidate(ms))
+		}
+
+		characterService := character.NewService(
+			parser,
+			characterRepository,
+			cd,
+			characterOptions...,
+		)
+
+		characterServices[realm.Name] = characterService
+		historyServices[realm.Name] = historyService
+		graveyardServices[realm.Name] = graveyardService
+		eventBrokers[realm.Name] = eventBroker
+		itemServices[realm.Name] = itemService
+		progressServices[realm.Name] = progressService
+		statsServices[realm.Name] = stats.NewService(characterService)
+		ladderServices[realm.Name] = ladder.NewService(mgo.NewLadderRepository(databaseName, realm.Name, client))
+		stashServices[realm.Name] = stash.NewService(parser)
+		accountServices[realm.Name] = account.NewService(
+			mgo.NewAccountRepository(databaseName, realm.Name, client),
+			characterService,
+		)
+
+		// Index the items of characters stored before they were indexed.
+		go func(realm domain.Realm) {
+			indexed, err := itemService.Backfill(context.Background())
+			if err != nil {
+				log.Printf("failed to backfill the item index of realm %s: %v", realm.Name, err)
+			}
+
+			if indexed > 0 {
+				log.Printf("indexed the items of %d characters of realm %s", indexed, realm.Name)
+			}
+		}(realm)
+
+		// Re-parse characters as soon as their binary is written.
+		if watching {
+			go func(realm domain.Realm) {
+				w := watcher.NewWatcher(realm.Path, characterService, wd, wpi, polling)
+
+				// The watcher stops without an error when the notifications are
+				// closed underneath it, so start it again instead of shutting down.
+				for {
+					if err := w.Run(context.Background()); err != nil {
+						errorChannel <- err
+						return
+					}
+
+					log.Printf("watcher of realm %s stopped, restarting in %s", realm.Name, wpi)
+					time.Sleep(wpi)
+				}
+			}(realm)
+		}
+	}
+
+	// Link the characters of the default realm to their accounts, statistics
+	// keep linking the characters they're posted for.
+	for _, source := range []struct {
+		path string
+		read func(string) (map[string][]string, error)
+	}{
+		{accountsFile, account.ReadMappingFile},
+		{accountsPath, account.ReadDirectory},
+	} {
+		if source.path == "" {
+			continue
+		}
+
+		mapping, err := source.read(source.path)
+		if err == nil {
+			err = accountServices[defaultRealm].Import(mgoCtx, mapping)
+		}
+		if err != nil {
+			log.Printf("failed to import accounts from %s, %s", source.path, err)
+			os.Exit(0)
+		}
+	}
+
 	// Repositories.
-	characterRepository := mgo.NewCharacterRepository(databaseName, client)
 	statisticsRepository := mgo.NewStatisticsRepository(databaseName, client)
 
 	// Business logic services.
-	parser := parsing.NewParser(d2sPath)
-	characterService := character.NewService(parser, characterRepository, cd)
-	statisticsService := statistics.NewService(statisticsRepository)
-
-	// Channel to receive errors on.
-	errorChannel := make(chan error)
+	statisticsService := statistics.NewService(
+		statisticsRepository,
+		statistics.WithAccountLinker(accountServices[defaultRealm]),
+		statistics.WithListener(webhookService),
+		statistics.WithListener(eventBrokers[defaultRealm]),
+	)
 
 	go func() {
 		log.Printf("starting metrics updater with interval %s", mi)
-			ticker := time.NewTicker(mi)
-			defer ticker.Stop()
+		ticker := time.NewTicker(mi)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			for _, realm := range realms {
+				if err := updateAllCharacterMetrics(context.Background(), realm.Path, characterServices[realm.Name], progressServices[realm.Name]); err != nil {
+					log.Printf("error updating metrics of realm %s: %v", realm.Name, err)
+				}
+			}
+		}
+	}()
 
-			for range ticker.C {
-				if err := updateAllCharacterMetrics(context.Background(), d2sPath, characterService); err != nil {
-					log.Printf("error updating metrics: %v", err)
+	// Mark characters whose binary has been deleted.
+	go func() {
+		ticker := time.NewTicker(ri)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			for _, realm := range realms {
+				deleted, err := characterServices[realm.Name].Reconcile(context.Background())
+				if err != nil {
+					log.Printf("failed to reconcile characters of realm %s: %v", realm.Name, err)
+				}
+
+				for _, name := range deleted {
+					log.Printf("character %s of realm %s was deleted", name, realm.Name)
+				}
+			}
+		}
+	}()
+
+	// Keep the standings of every day to tell how ranks changed since the day before.
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			for _, realm := range realms {
+				if err := ladderServices[realm.Name].Snapshot(context.Background()); err != nil {
+					log.Printf("failed to snapshot the ladder of realm %s: %v", realm.Name, err)
 				}
 			}
+
+			<-ticker.C
+		}
 	}()
 
 	// Credentials for posting statistics map.
@@ -140,13 +431,41 @@ func main() {
 	}
 	// HTTP server.
 	go func() {
+		serverOptions := []httpserver.Option{
+			httpserver.WithHistoryService(historyServices[defaultRealm]),
+			httpserver.WithStashService(stashServices[defaultRealm]),
+			httpserver.WithAccountService(accountServices[defaultRealm]),
+			httpserver.WithGraveyardService(graveyardServices[defaultRealm]),
+			httpserver.WithEventBroker(eventBrokers[defaultRealm]),
+			httpserver.WithItemService(itemServices[defaultRealm]),
+			httpserver.WithLadderService(ladderServices[defaultRealm]),
+			httpserver.WithProgressService(progressServices[defaultRealm]),
+			httpserver.WithStatsService(statsServices[defaultRealm]),
+			httpserver.WithUploadService(characterServices[defaultRealm]),
+		}
+		for _, realm := range realms {
+			serverOptions = append(serverOptions, httpserver.WithRealm(realm.Name, httpserver.Realm{
+				CharacterService: characterServices[realm.Name],
+				HistoryService:   historyServices[realm.Name],
+				StashService:     stashServices[realm.Name],
+				AccountService:   accountServices[realm.Name],
+				GraveyardService: graveyardServices[realm.Name],
+				EventBroker:      eventBrokers[realm.Name],
+				ItemService:      itemServices[realm.Name],
+				LadderService:    ladderServices[realm.Name],
+				ProgressService:  progressServices[realm.Name],
+				StatsService:     statsServices[realm.Name],
+			}))
+		}
+
 		httpServer := httpserver.NewServer(
 			httpAddress,
-			characterService,
+			characterServices[defaultRealm],
 			statisticsService,
 			credentials,
 			cors,
 			logging,
+			serverOptions...,
 		)
 		errorChannel <- httpServer.Open()
 	}()
@@ -164,15 +483,47 @@ func main() {
 	}
 }
 
+// parseRealms returns the default realm followed by the extra realms, which are
+// configured as a comma separated list of name=path pairs.
+func parseRealms(defaultRealm string, d2sPath string, extraRealms string) ([]domain.Realm, error) {
+	realms := []domain.Realm{{Name: defaultRealm, Path: d2sPath}}
+
+	if extraRealms != "" {
+		for _, pair := range strings.Split(extraRealms, ",") {
+			name, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || name == "" || path == "" {
+				return nil, fmt.Errorf("invalid realm %q, expected name=path", pair)
+			}
+
+			realms = append(realms, domain.Realm{Name: name, Path: path})
+		}
+	}
+
+	seen := make(map[string]bool, len(realms))
+	for _, realm := range realms {
+		if !realmRegexp.MatchString(realm.Name) {
+			return nil, fmt.Errorf("invalid realm name %q", realm.Name)
+		}
+
+		if seen[realm.Name] {
+			return nil, fmt.Errorf("realm %q is configured more than once", realm.Name)
+		}
+		seen[realm.Name] = true
+	}
+
+	return realms, nil
+}
+
 // updateAllCharacterMetrics reads all character files and updates metrics
-func updateAllCharacterMetrics(ctx context.Context, d2sPath string, characterService *character.Service) error {
+func updateAllCharacterMetrics(ctx context.Context, d2sPath string, characterService *character.Service, progressService *progress.Service) error {
 	files, err := os.ReadDir(d2sPath)
 	if err != nil {
 		return fmt.Errorf("failed to read d2s directory: %w", err)
 	}
 
 	for _, file := range files {
-		if file.IsDir() {
+		// Character binaries have no extension, unlike the PlugY stashes next to them.
+		if file.IsDir() || filepath.Ext(file.Name()) != "" {
 			continue
 		}
 
@@ -183,6 +534,11 @@ func updateAllCharacterMetrics(ctx context.Context, d2sPath string, characterSer
 			log.Printf("failed to parse character %s for metrics: %v", charName, err)
 			continue
 		}
+
+		// Rates move as time passes, even when the character is unchanged.
+		if err := progressService.UpdateMetrics(ctx, charName); err != nil {
+			log.Printf("failed to update progress metrics of character %s: %v", charName, err)
+		}
 	}
 
 	log.Printf("updated metrics for %d characters", len(files))
@@ -10,9 +10,60 @@ db.createUser({
 
 db.createCollection("character");
 db.createCollection("statistics");
+db.createCollection("character_history");
+db.createCollection("account");
+db.createCollection("graveyard");
+db.createCollection("webhook_outbox");
+db.createCollection("items");
+db.createCollection("ladder_snapshot");
 
-// Index characters for name in ascending order.
-db.character.createIndex({ id: 1 });
+// Index characters for realm and name in ascending order.
+db.character.createIndex({ realm: 1, id: 1 }, { unique: true });
 
 // Index statistics for character name in ascending order.
 db.statistics.createIndex({ character: 1 });
+
+// Index character snapshots for listing them newest first.
+db.character_history.createIndex({ realm: 1, character: 1, _id: -1 });
+
+// Index character snapshots for finding the version current at a point in time.
+db.character_history.createIndex({ realm: 1, character: 1, parsed_at: -1 });
+
+// Index accounts for realm and name in ascending order.
+db.account.createIndex({ realm: 1, name: 1 }, { unique: true });
+
+// Index deaths for listing them most recent first.
+db.graveyard.createIndex({ realm: 1, _id: -1 });
+
+// Index deaths for filtering them by class and level.
+db.graveyard.createIndex({ realm: 1, class: 1, level: 1 });
+
+// Index webhook deliveries for finding the ones that are due.
+db.webhook_outbox.createIndex({ failed: 1, next_attempt: 1 });
+
+// Index webhook deliveries for id in ascending order.
+db.webhook_outbox.createIndex({ id: 1 }, { unique: true });
+
+// Index items for replacing the items of a character.
+db.items.createIndex({ realm: 1, character: 1 });
+
+// Index items for searching them by the lowercase name, base type, set, unique and runeword.
+db.items.createIndex({ realm: 1, "keys.name": 1 });
+db.items.createIndex({ realm: 1, "keys.type_name": 1 });
+db.items.createIndex({ realm: 1, "keys.set_name": 1 });
+db.items.createIndex({ realm: 1, "keys.unique_name": 1 });
+db.items.createIndex({ realm: 1, "keys.runeword": 1 });
+
+// Index items for searching them by attribute thresholds.
+db.items.createIndex({ realm: 1, "attributes.id": 1, "attributes.value": 1 });
+
+// Index characters for ranking them by level and experience.
+db.character.createIndex({ realm: 1, "d2s.attributes.level": -1, "d2s.attributes.experience": -1, id: 1 });
+
+// Index characters for ranking them within a class.
+db.character.createIndex({ realm: 1, "d2s.header.class": 1, "d2s.attributes.level": -1, "d2s.attributes.experience": -1, id: 1 });
+
+// Index the daily standings for finding the rank of a character, and expire them after a week.
+db.ladder_snapshot.createIndex({ realm: 1, day: 1, character: 1 }, { unique: true });
+db.ladder_snapshot.createIndex({ realm: 1, day: 1, level: -1, experience: -1 });
+db.ladder_snapshot.createIndex({ taken_at: 1 }, { expireAfterSeconds: 604800 });
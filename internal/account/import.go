@@ -0,0 +1,58 @@
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReadMappingFile reads a JSON file mapping account names to character names.
+//
+//	{"nokka": ["nokka", "nokkasorc"]}
+func ReadMappingFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account mapping file: %w", err)
+	}
+
+	var mapping map[string][]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode account mapping file: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// ReadDirectory reads an account directory layout, where every directory is
+// named after an account and holds a file named after each of its characters,
+// the way PvPGN keeps its charinfo.
+func ReadDirectory(path string) (map[string][]string, error) {
+	accounts, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account directory: %w", err)
+	}
+
+	mapping := make(map[string][]string, len(accounts))
+
+	for _, account := range accounts {
+		if !account.IsDir() {
+			continue
+		}
+
+		characters, err := os.ReadDir(filepath.Join(path, account.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read account directory: %w", err)
+		}
+
+		for _, character := range characters {
+			if character.IsDir() {
+				continue
+			}
+
+			mapping[account.Name()] = append(mapping[account.Name()], character.Name())
+		}
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,71 @@
+package account
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestReadDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, path := range []string{"nokka/nokka", "nokka/nokkasorc", "wheelz/wheelz"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Files next to the account directories aren't accounts.
+	if err := os.WriteFile(filepath.Join(dir, "README"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := ReadDirectory(dir)
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	for _, characters := range mapping {
+		sort.Strings(characters)
+	}
+
+	want := map[string][]string{
+		"nokka":  {"nokka", "nokkasorc"},
+		"wheelz": {"wheelz"},
+	}
+
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("expected mapping %v, got = %v", want, mapping)
+	}
+}
+
+func TestReadMappingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+
+	if err := os.WriteFile(path, []byte(`{"nokka": ["nokka", "nokkasorc"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := ReadMappingFile(path)
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	want := map[string][]string{"nokka": {"nokka", "nokkasorc"}}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("expected mapping %v, got = %v", want, mapping)
+	}
+
+	if err := os.WriteFile(path, []byte(`["nokka"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadMappingFile(path); err == nil {
+		t.Error("expected an error for a malformed mapping file")
+	}
+}
@@ -0,0 +1,113 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./service_mocks.go . accountRepository characterService
+
+// accountRepository is the interface representation of the data layer
+// the service depend on.
+type accountRepository interface {
+	Find(ctx context.Context, name string) (*domain.Account, error)
+	Link(ctx context.Context, name string, character string) error
+}
+
+// characterService is the interface representation of the character service
+// the summaries of the characters are made from.
+type characterService interface {
+	ParseBatch(ctx context.Context, names []string) ([]domain.CharacterResult, error)
+}
+
+// Service keeps track of which characters belong to which account.
+type Service struct {
+	accounts   accountRepository
+	characters characterService
+}
+
+// Link will add the character to the account.
+func (s Service) Link(ctx context.Context, account string, character string) error {
+	if account == "" || character == "" {
+		return fmt.Errorf("account and character are required: %w", domain.ErrRequest)
+	}
+
+	// Lower case the account to keep consistency with statistics, the character
+	// is kept as is since its binary is looked up by its exact name.
+	return s.accounts.Link(ctx, strings.ToLower(account), character)
+}
+
+// Import will link all characters of the mapping of account names to character names.
+func (s Service) Import(ctx context.Context, mapping map[string][]string) error {
+	for account, characters := range mapping {
+		for _, character := range characters {
+			if err := s.Link(ctx, account, character); err != nil {
+				return fmt.Errorf("failed to link character %s to account %s: %w", character, account, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// batchSize is the most characters looked up at once, the character
+// service refuses bigger batches.
+const batchSize = 50
+
+// Summary will return the account with a summary of each of its characters,
+// characters that no longer exist or have been deleted are left out and the
+// characters that failed to parse are reported with their error.
+func (s Service) Summary(ctx context.Context, name string) (*domain.AccountSummary, error) {
+	account, err := s.accounts.Find(ctx, strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.AccountSummary{
+		Name:       account.Name,
+		Characters: make([]domain.CharacterSummary, 0, len(account.Characters)),
+	}
+
+	for start := 0; start < len(account.Characters); start += batchSize {
+		names := account.Characters[start:min(start+batchSize, len(account.Characters))]
+
+		results, err := s.characters.ParseBatch(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			if result.Err != nil {
+				if errors.Is(result.Err, domain.ErrNotFound) || errors.Is(result.Err, domain.ErrInvalidArgument) {
+					continue
+				}
+
+				summary.Characters = append(summary.Characters, domain.CharacterSummary{
+					Name:  result.Name,
+					Error: result.Err.Error(),
+				})
+				continue
+			}
+
+			if result.Character.DeletedAt != nil {
+				continue
+			}
+
+			summary.Characters = append(summary.Characters, result.Character.Summary())
+		}
+	}
+
+	return summary, nil
+}
+
+// NewService constructs a new account service with all the dependencies.
+func NewService(accountRepository accountRepository, characterService characterService) *Service {
+	return &Service{
+		accounts:   accountRepository,
+		characters: characterService,
+	}
+}
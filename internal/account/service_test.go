@@ -0,0 +1,187 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestSummary(t *testing.T) {
+	parsed := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		account       *domain.Account
+		findErr       error
+		results       []domain.CharacterResult
+		want          []string
+		failed        []string
+		expectedError error
+	}{
+		{
+			name:    "characters that no longer exist are left out",
+			account: &domain.Account{Name: "nokka", Characters: []string{"nokka", "deleted"}},
+			results: []domain.CharacterResult{
+				{Name: "nokka", Character: &domain.Character{ID: "nokka", LastParsed: parsed, D2s: &d2s.Character{}}},
+				{Name: "deleted", Err: fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)},
+			},
+			want: []string{"nokka"},
+		},
+		{
+			name:    "account without characters",
+			account: &domain.Account{Name: "nokka"},
+			want:    []string{},
+		},
+		{
+			name:          "account not found",
+			findErr:       fmt.Errorf("%w", domain.ErrNotFound),
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:    "failing character is reported on its own",
+			account: &domain.Account{Name: "nokka", Characters: []string{"nokka", "broken"}},
+			results: []domain.CharacterResult{
+				{Name: "nokka", Character: &domain.Character{ID: "nokka", LastParsed: parsed, D2s: &d2s.Character{}}},
+				{Name: "broken", Err: fmt.Errorf("temporary error: %w", domain.ErrTemporary)},
+			},
+			want:   []string{"nokka", "broken"},
+			failed: []string{"broken"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := &accountRepositoryMock{
+				FindFunc: func(ctx context.Context, name string) (*domain.Account, error) {
+					return tt.account, tt.findErr
+				},
+			}
+
+			characters := &characterServiceMock{
+				ParseBatchFunc: func(ctx context.Context, names []string) ([]domain.CharacterResult, error) {
+					return tt.results, nil
+				},
+			}
+
+			s := NewService(accounts, characters)
+
+			summary, err := s.Summary(context.TODO(), "Nokka")
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if accounts.FindCalls()[0].Name != "nokka" {
+				t.Errorf("expected account name to be lower cased, got = %s", accounts.FindCalls()[0].Name)
+			}
+
+			if len(summary.Characters) != len(tt.want) {
+				t.Fatalf("expected %d characters, got = %d", len(tt.want), len(summary.Characters))
+			}
+
+			failed := make(map[string]bool, len(tt.failed))
+			for _, name := range tt.failed {
+				failed[name] = true
+			}
+
+			for i, c := range summary.Characters {
+				if c.Name != tt.want[i] {
+					t.Errorf("expected character %s, got = %s", tt.want[i], c.Name)
+				}
+
+				if failed[c.Name] {
+					if c.Error == "" {
+						t.Errorf("expected character %s to report its error", c.Name)
+					}
+					continue
+				}
+
+				if !c.LastParsed.Equal(parsed) {
+					t.Errorf("expected last parsed %s, got = %s", parsed, c.LastParsed)
+				}
+			}
+		})
+	}
+}
+
+func TestSummaryInBatches(t *testing.T) {
+	names := make([]string, 120)
+	for i := range names {
+		names[i] = fmt.Sprintf("char%d", i)
+	}
+
+	accounts := &accountRepositoryMock{
+		FindFunc: func(ctx context.Context, name string) (*domain.Account, error) {
+			return &domain.Account{Name: name, Characters: names}, nil
+		},
+	}
+
+	characters := &characterServiceMock{
+		ParseBatchFunc: func(ctx context.Context, names []string) ([]domain.CharacterResult, error) {
+			if len(names) > batchSize {
+				return nil, fmt.Errorf("at most %d names are allowed: %w", batchSize, domain.ErrRequest)
+			}
+
+			results := make([]domain.CharacterResult, 0, len(names))
+			for _, name := range names {
+				results = append(results, domain.CharacterResult{Name: name, Character: &domain.Character{ID: name}})
+			}
+			return results, nil
+		},
+	}
+
+	s := NewService(accounts, characters)
+
+	summary, err := s.Summary(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(characters.ParseBatchCalls()) != 3 {
+		t.Errorf("expected characterService.ParseBatch() to be called exactly 3 times but was called %d times", len(characters.ParseBatchCalls()))
+	}
+
+	if len(summary.Characters) != len(names) {
+		t.Fatalf("expected %d characters, got = %d", len(names), len(summary.Characters))
+	}
+
+	for i, c := range summary.Characters {
+		if c.Name != names[i] {
+			t.Errorf("expected character %s at %d, got = %s", names[i], i, c.Name)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	accounts := &accountRepositoryMock{
+		LinkFunc: func(ctx context.Context, name string, character string) error {
+			return nil
+		},
+	}
+
+	s := NewService(accounts, &characterServiceMock{})
+
+	err := s.Import(context.TODO(), map[string][]string{
+		"Nokka": {"Nokka", "nokkasorc"},
+	})
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	calls := accounts.LinkCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected accountRepository.Link() to be called exactly 2 times but was called %d times", len(calls))
+	}
+
+	if calls[0].Name != "nokka" || calls[0].Character != "Nokka" {
+		t.Errorf("expected only the account to be lower cased, got = %s %s", calls[0].Name, calls[0].Character)
+	}
+}
@@ -0,0 +1,125 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// defaultBufferSize is the number of events buffered for a subscriber that
+// hasn't caught up yet.
+const defaultBufferSize = 32
+
+// subscriber receives the events of a single character, or of all characters
+// when no character is set.
+type subscriber struct {
+	character string
+	events    chan domain.Event
+}
+
+// Broker publishes live character updates to its subscribers. Every
+// subscriber has a buffer of its own, a subscriber that falls so far behind
+// that its buffer is full is dropped instead of holding up the others.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	bufferSize  int
+	sequence    uint64
+}
+
+// CharacterParsed publishes an update when the character changed since it was last parsed.
+func (b *Broker) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if current == nil || current.D2s == nil {
+		return
+	}
+
+	if previous != nil && previous.Fingerprint.Hash == current.Fingerprint.Hash {
+		return
+	}
+
+	summary := current.Summary()
+
+	b.Publish(domain.Event{
+		Type:       domain.EventCharacterUpdated,
+		Realm:      current.Realm,
+		Character:  current.ID,
+		Message:    fmt.Sprintf("%s the level %d %s was updated", current.ID, summary.Level, summary.Class),
+		OccurredAt: current.LastParsed,
+		Data:       summary,
+	})
+}
+
+// StatisticsPosted publishes the statistics posted for a character.
+func (b *Broker) StatisticsPosted(ctx context.Context, stat domain.StatisticsRequest) {
+	b.Publish(domain.Event{
+		Type:       domain.EventStatisticsPosted,
+		Character:  stat.Character,
+		Message:    fmt.Sprintf("%s posted statistics for %s", stat.Character, stat.Difficulty),
+		OccurredAt: time.Now(),
+		Data:       stat,
+	})
+}
+
+// Publish sends the event to every subscriber interested in it, the event is
+// given an id that increases with every event.
+func (b *Broker) Publish(event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sequence++
+	event.ID = strconv.FormatUint(b.sequence, 10)
+
+	for sub := range b.subscribers {
+		if sub.character != "" && !strings.EqualFold(sub.character, event.Character) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			// The subscriber can't keep up, closing its channel tells it to go away.
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events of the character, or of
+// all characters when the character is empty. The channel is closed when the
+// subscriber falls behind or is cancelled, cancel must be called once the
+// subscriber is done.
+func (b *Broker) Subscribe(character string) (<-chan domain.Event, func()) {
+	sub := &subscriber{
+		character: character,
+		events:    make(chan domain.Event, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+
+	return sub.events, cancel
+}
+
+// NewBroker returns a broker without any subscribers.
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[*subscriber]struct{}),
+		bufferSize:  defaultBufferSize,
+	}
+}
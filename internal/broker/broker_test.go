@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestPublish(t *testing.T) {
+	b := NewBroker()
+
+	all, cancelAll := b.Subscribe("")
+	defer cancelAll()
+
+	nokka, cancelNokka := b.Subscribe("Nokka")
+	defer cancelNokka()
+
+	b.Publish(domain.Event{Type: domain.EventCharacterUpdated, Character: "wheelz"})
+	b.Publish(domain.Event{Type: domain.EventCharacterUpdated, Character: "nokka"})
+
+	if got := (<-all).Character; got != "wheelz" {
+		t.Errorf("expected the realm wide subscriber to get every event, got = %s", got)
+	}
+
+	if got := (<-all).Character; got != "nokka" {
+		t.Errorf("expected the realm wide subscriber to get every event, got = %s", got)
+	}
+
+	event := <-nokka
+	if event.Character != "nokka" || event.ID != "2" {
+		t.Errorf("expected only the events of the character, got = %+v", event)
+	}
+
+	if len(nokka) != 0 {
+		t.Errorf("expected no more events for the character, got = %d", len(nokka))
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	b := NewBroker()
+
+	events, cancel := b.Subscribe("")
+	defer cancel()
+
+	for i := 0; i <= defaultBufferSize; i++ {
+		b.Publish(domain.Event{Type: domain.EventCharacterUpdated, Character: "nokka"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+
+	if received != defaultBufferSize {
+		t.Errorf("expected the buffered events before the channel is closed, got = %d", received)
+	}
+
+	if len(b.subscribers) != 0 {
+		t.Errorf("expected the subscriber to be dropped, got = %d subscribers", len(b.subscribers))
+	}
+}
+
+func TestCancel(t *testing.T) {
+	b := NewBroker()
+
+	events, cancel := b.Subscribe("nokka")
+	cancel()
+	cancel()
+
+	if _, ok := <-events; ok {
+		t.Error("expected the channel to be closed")
+	}
+
+	if len(b.subscribers) != 0 {
+		t.Errorf("expected the subscriber to be removed, got = %d subscribers", len(b.subscribers))
+	}
+}
+
+func TestCharacterParsed(t *testing.T) {
+	character := func(hash string) *domain.Character {
+		return &domain.Character{
+			ID:          "nokka",
+			D2s:         &d2s.Character{},
+			Fingerprint: domain.Fingerprint{Hash: hash},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		previous *domain.Character
+		current  *domain.Character
+		expected int
+	}{
+		{"new character", nil, character("c0ffee"), 1},
+		{"changed character", character("c0ffee"), character("b4d1c0de"), 1},
+		{"unchanged character", character("c0ffee"), character("c0ffee"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker()
+
+			events, cancel := b.Subscribe("nokka")
+			defer cancel()
+
+			b.CharacterParsed(context.TODO(), tt.previous, tt.current)
+
+			if len(events) != tt.expected {
+				t.Errorf("expected %d events, got = %d", tt.expected, len(events))
+			}
+		})
+	}
+}
@@ -3,18 +3,26 @@ package character
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/nokka/d2-armory-api/internal/domain"
 	"github.com/nokka/d2-armory-api/internal/metrics"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
-//go:generate moq -out ./service_mocks.go . parser characterRepository
+//go:generate moq -out ./service_mocks.go . parser characterRepository listener deleteListener
 
 // parser is the interface representation of a d2 parser the service depend on.
 type parser interface {
 	Parse(name string) (*domain.Character, error)
+	ParseContent(data []byte) (*domain.Character, error)
+	Fingerprint(name string) (*domain.Fingerprint, error)
+	Names() ([]string, error)
 }
 
 // characterRepository is the interface representation of the data layer
@@ -23,89 +31,533 @@ type characterRepository interface {
 	Find(ctx context.Context, id string) (*domain.Character, error)
 	Update(ctx context.Context, character *domain.Character) error
 	Store(ctx context.Context, character *domain.Character) error
+	Names(ctx context.Context) ([]string, error)
+	MarkDeleted(ctx context.Context, id string, at time.Time) error
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+// listener is notified every time a character has been parsed and persisted,
+// previous is nil the first time we see the character.
+type listener interface {
+	CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character)
+}
+
+// deleteListener is notified every time a character has been marked as deleted.
+type deleteListener interface {
+	CharacterDeleted(ctx context.Context, name string)
 }
 
 // Service performs all operations on parsing characters.
 type Service struct {
+	realm         string
 	parser        parser
 	characters    characterRepository
 	cacheDuration time.Duration
+	watched       bool
+	maxStaleness  time.Duration
+	listeners     []listener
+	deletions     []deleteListener
+	inflight      *singleflight.Group
+
+	// undecodable holds the fingerprint of the binary of every character that
+	// failed to parse, so the same binary isn't parsed again on every request.
+	undecodable *sync.Map
+}
+
+// Option is used to configure optional behaviour of the service.
+type Option func(s *Service)
+
+// WithRealm sets the realm the characters belong to, their metrics are labelled with it.
+func WithRealm(realm string) Option {
+	return func(s *Service) {
+		s.realm = realm
+	}
+}
+
+// WithWatcher tells the service that a file watcher is responsible for
+// refreshing characters when their binary changes on disk, so the cache
+// duration is ignored and the stored character is served until then.
+func WithWatcher() Option {
+	return func(s *Service) {
+		s.watched = true
+	}
+}
+
+// WithStaleWhileRevalidate serves an expired character from the cache right away
+// and refreshes it in the background, unless it was parsed longer than
+// maxStaleness ago in which case it's parsed before responding as usual.
+func WithStaleWhileRevalidate(maxStaleness time.Duration) Option {
+	return func(s *Service) {
+		s.maxStaleness = maxStaleness
+	}
+}
+
+// WithListener registers a listener that is notified when a character has been parsed.
+func WithListener(l listener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, l)
+	}
+}
+
+// WithDeleteListener registers a listener that is notified when a character has been marked as deleted.
+func WithDeleteListener(l deleteListener) Option {
+	return func(s *Service) {
+		s.deletions = append(s.deletions, l)
+	}
 }
 
 // The name regexp required for character names, to enforce strict diablo rules
 // on the names to prevent missuse of the endpoint.
 const nameRegexp = "^[a-zA-Z]+[_-]?[a-zA-Z]+$"
 
+// sharedTimeout bounds the work shared by coalesced calls, since it's detached
+// from the context of the caller that happened to start it.
+const sharedTimeout = 10 * time.Second
+
+// Retries of a binary that failed to parse, the game server writes saves in
+// several passes so a read can catch the binary half written.
+const (
+	parseAttempts = 3
+	parseBackoff  = 100 * time.Millisecond
+)
+
+// maxDeletedShare is the largest share of the stored characters that can be
+// marked as deleted at once.
+const maxDeletedShare = 0.5
+
+// Limits for looking up several characters at once, a guild page shows
+// at most a few dozen characters.
+const (
+	maxBatchSize     = 50
+	batchConcurrency = 8
+)
+
 // Parse will perform the actual parsing of the character.
 func (s Service) Parse(ctx context.Context, name string) (*domain.Character, error) {
 	match, _ := regexp.MatchString(nameRegexp, name)
 	if !match {
-		metrics.CharacterParsesTotal.WithLabelValues(name, "invalid_name").Inc()
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "invalid_name").Inc()
 		return nil, domain.ErrInvalidArgument
 	}
 
+	return s.coalesce(ctx, "parse/"+name, name, s.parse)
+}
+
+// parse serves the character from the db cache, or parses it if the cache has expired.
+func (s Service) parse(ctx context.Context, name string) (*domain.Character, error) {
 	// Read character from db cache.
 	c, err := s.characters.Find(ctx, name)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			// Character didn't exist at all, so lets parse and store it.
-			parsed, err := s.parser.Parse(name)
-			if err != nil {
-				metrics.CharacterParsesTotal.WithLabelValues(name, "parse_error").Inc()
-				return nil, err
-			}
-
-			if err := s.characters.Store(ctx, parsed); err != nil {
-				metrics.CharacterParsesTotal.WithLabelValues(name, "store_error").Inc()
-				return nil, err
-			}
-
-			// Update metrics on successful parse
-			metrics.UpdateCharacterMetrics(parsed)
-			metrics.CharacterParsesTotal.WithLabelValues(name, "success").Inc()
-			return parsed, nil
+			return s.store(ctx, name)
 		}
 
 		// The error wasn't ErrNotFound, so just return it.
-		metrics.CharacterParsesTotal.WithLabelValues(name, "db_error").Inc()
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "db_error").Inc()
 		return nil, err
 	}
 
-	// Character already exists, let's check how long since we parsed it.
+	// The character was deleted, unless its binary has been created again.
+	if c.DeletedAt != nil {
+		return s.deleted(ctx, name, c)
+	}
+
+	// Character already exists, let's check how long since we parsed it, unless
+	// the watcher is keeping it up to date for us. Uploaded characters are only
+	// parsed again once a binary on disk has been written after the upload.
 	diff := time.Since(c.LastParsed)
 
-	if diff >= s.cacheDuration {
-		parsed, err := s.parser.Parse(name)
+	if !s.watched && diff >= s.cacheDuration && (!c.Uploaded || s.writtenSince(name, c.LastParsed)) {
+		if diff < s.maxStaleness {
+			return s.revalidate(name, c), nil
+		}
+
+		return s.update(ctx, name, c)
+	}
+
+	// We parsed this character less than cacheDuration ago so return the db version
+	// Still update metrics from cached data
+	metrics.UpdateCharacterMetrics(s.realm, c)
+	metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "cached").Inc()
+	return c, nil
+}
+
+// writtenSince tells if the binary of the character on disk was written after the given time.
+func (s Service) writtenSince(name string, t time.Time) bool {
+	fingerprint, err := s.parser.Fingerprint(name)
+	if err != nil {
+		return false
+	}
+
+	return fingerprint.Modified.After(t)
+}
+
+// revalidate refreshes the character in the background and returns the cached
+// version marked as stale in the meantime.
+func (s Service) revalidate(name string, c *domain.Character) *domain.Character {
+	go func() {
+		_, err := s.coalesce(context.Background(), "refresh/"+name, name, s.refresh)
 		if err != nil {
-			metrics.CharacterParsesTotal.WithLabelValues(name, "parse_error").Inc()
-			return nil, err
+			log.Printf("failed to revalidate character %s: %v", name, err)
 		}
+	}()
+
+	c.Stale = true
+
+	metrics.UpdateCharacterMetrics(s.realm, c)
+	metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "stale").Inc()
+	return c
+}
 
-		// Update the existing record in the db.
+// ParseUpload will parse an uploaded binary, the character is only persisted
+// when store is set, replacing the stored version of the character if any.
+func (s Service) ParseUpload(ctx context.Context, data []byte, store bool) (*domain.Character, error) {
+	parsed, err := s.parser.ParseContent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !store {
+		return parsed, nil
+	}
+
+	match, _ := regexp.MatchString(nameRegexp, parsed.ID)
+	if !match {
+		return nil, fmt.Errorf("invalid character name %q: %w", parsed.ID, domain.ErrRequest)
+	}
+
+	existing, err := s.characters.Find(ctx, parsed.ID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		existing = nil
+		err = s.characters.Store(ctx, parsed)
+	case err == nil:
 		err = s.characters.Update(ctx, parsed)
-		if err != nil {
-			metrics.CharacterParsesTotal.WithLabelValues(name, "update_error").Inc()
+	}
+	if err != nil {
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, parsed.ID, "store_error").Inc()
+		return nil, err
+	}
+
+	s.notify(ctx, existing, parsed)
+
+	metrics.UpdateCharacterMetrics(s.realm, parsed)
+	metrics.CharacterParsesTotal.WithLabelValues(s.realm, parsed.ID, "uploaded").Inc()
+	return parsed, nil
+}
+
+// ParseBatch will look up several characters in parallel, the results are in
+// the same order as the names and a failing character doesn't fail the others.
+func (s Service) ParseBatch(ctx context.Context, names []string) ([]domain.CharacterResult, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one name is required: %w", domain.ErrRequest)
+	}
+
+	if len(names) > maxBatchSize {
+		return nil, fmt.Errorf("at most %d names are allowed: %w", maxBatchSize, domain.ErrRequest)
+	}
+
+	results := make([]domain.CharacterResult, len(names))
+
+	var g errgroup.Group
+	g.SetLimit(batchConcurrency)
+
+	for i, name := range names {
+		i, name := i, name
+
+		g.Go(func() error {
+			c, err := s.Parse(ctx, name)
+			results[i] = domain.CharacterResult{
+				Name:      name,
+				Character: c,
+				Err:       err,
+			}
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return results, nil
+}
+
+// Refresh will parse the character binary regardless of the cache duration
+// and persist the result, it's used when the binary is known to have changed.
+func (s Service) Refresh(ctx context.Context, name string) (*domain.Character, error) {
+	match, _ := regexp.MatchString(nameRegexp, name)
+	if !match {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	return s.coalesce(ctx, "refresh/"+name, name, s.refresh)
+}
+
+// refresh parses the character and stores or updates the record.
+func (s Service) refresh(ctx context.Context, name string) (*domain.Character, error) {
+	c, err := s.characters.Find(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return s.store(ctx, name)
+		}
+
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "db_error").Inc()
+		return nil, err
+	}
+
+	return s.update(ctx, name, c)
+}
+
+// coalesce makes sure only one call with the given key is in flight at a time,
+// callers arriving while it is share its result instead of repeating the work.
+func (s Service) coalesce(ctx context.Context, key string, name string, fn func(context.Context, string) (*domain.Character, error)) (*domain.Character, error) {
+	var leader bool
+
+	v, err, shared := s.inflight.Do(key, func() (interface{}, error) {
+		leader = true
+
+		// Waiters shouldn't fail because the caller that started the work went away.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedTimeout)
+		defer cancel()
+
+		return fn(ctx, name)
+	})
+
+	if shared && !leader {
+		metrics.CharacterParsesCoalescedTotal.WithLabelValues(s.realm, name).Inc()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*domain.Character), nil
+}
+
+// store parses a character we haven't seen before and stores it.
+func (s Service) store(ctx context.Context, name string) (*domain.Character, error) {
+	parsed, err := s.parseBinary(ctx, name)
+	if err != nil {
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "parse_error").Inc()
+		return nil, err
+	}
+
+	if err := s.characters.Store(ctx, parsed); err != nil {
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "store_error").Inc()
+		return nil, err
+	}
+
+	s.notify(ctx, nil, parsed)
+
+	// Update metrics on successful parse
+	metrics.UpdateCharacterMetrics(s.realm, parsed)
+	metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "success").Inc()
+	return parsed, nil
+}
+
+// update re-parses an existing character and updates the stored record,
+// unless the binary is identical to the one the stored record was parsed from.
+func (s Service) update(ctx context.Context, name string, existing *domain.Character) (*domain.Character, error) {
+	fingerprint, err := s.parser.Fingerprint(name)
+	if err != nil {
+		// The binary of a character we know about is gone, so it was deleted.
+		if errors.Is(err, domain.ErrNotFound) && existing.DeletedAt == nil {
+			return s.markDeleted(ctx, name, existing)
+		}
+
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "parse_error").Inc()
+		return nil, err
+	}
+
+	if existing.DeletedAt == nil && existing.Fingerprint.Hash != "" && fingerprint.Hash == existing.Fingerprint.Hash {
+		// The stored record is still current, so it's as fresh as a parse.
+		now := time.Now()
+		if err := s.characters.Touch(ctx, name, now); err != nil {
+			metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "update_error").Inc()
 			return nil, err
 		}
+		existing.LastParsed = now
 
-		// Update metrics on successful parse
-		metrics.UpdateCharacterMetrics(parsed)
-		metrics.CharacterParsesTotal.WithLabelValues(name, "success").Inc()
-		return parsed, nil
+		metrics.UpdateCharacterMetrics(s.realm, existing)
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "unchanged").Inc()
+		return existing, nil
 	}
 
-	// We parsed this character less than cacheDuration ago so return the db version
-	// Still update metrics from cached data
-	metrics.UpdateCharacterMetrics(c)
-	metrics.CharacterParsesTotal.WithLabelValues(name, "cached").Inc()
+	// The binary already failed to decode, it's only parsed again once it changes.
+	if hash, ok := s.undecodable.Load(name); ok && hash == fingerprint.Hash {
+		existing.Degraded = true
+
+		metrics.UpdateCharacterMetrics(s.realm, existing)
+		return existing, nil
+	}
+
+	parsed, err := s.parseBinary(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "parse_error").Inc()
+			return nil, err
+		}
+
+		// The binary is there but won't decode, serve the last version we stored.
+		log.Printf("serving last stored version of character %s: %v", name, err)
+
+		s.undecodable.Store(name, fingerprint.Hash)
+		existing.Degraded = true
+
+		metrics.UpdateCharacterMetrics(s.realm, existing)
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "degraded").Inc()
+		return existing, nil
+	}
+
+	s.undecodable.Delete(name)
+
+	// Update the existing record in the db.
+	err = s.characters.Update(ctx, parsed)
+	if err != nil {
+		metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "update_error").Inc()
+		return nil, err
+	}
+
+	s.notify(ctx, existing, parsed)
+
+	// Update metrics on successful parse
+	metrics.UpdateCharacterMetrics(s.realm, parsed)
+	metrics.CharacterParsesTotal.WithLabelValues(s.realm, name, "success").Inc()
+	return parsed, nil
+}
+
+// deleted serves a deleted character, unless a binary with its name has been
+// created since in which case it's parsed.
+func (s Service) deleted(ctx context.Context, name string, c *domain.Character) (*domain.Character, error) {
+	if _, err := s.parser.Fingerprint(name); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return c, nil
+		}
+
+		return nil, err
+	}
+
+	return s.update(ctx, name, c)
+}
+
+// Reconcile will mark every stored character whose binary no longer exists as
+// deleted, and returns the names of the characters it marked. Nothing is marked
+// when the d2s directory is empty or most characters are missing at once, the
+// directory is more likely unmounted than all those characters deleted.
+func (s Service) Reconcile(ctx context.Context) ([]string, error) {
+	names, err := s.parser.Names()
+	if err != nil {
+		return nil, err
+	}
+
+	onDisk := make(map[string]bool, len(names))
+	for _, name := range names {
+		onDisk[name] = true
+	}
+
+	stored, err := s.characters.Names(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range stored {
+		if !onDisk[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 && (len(names) == 0 || len(missing) > 1 && float64(len(missing)) > maxDeletedShare*float64(len(stored))) {
+		return nil, fmt.Errorf("refusing to mark %d of %d characters as deleted: %w", len(missing), len(stored), domain.ErrUnavailable)
+	}
+
+	var deleted []string
+
+	for _, name := range missing {
+		if _, err := s.markDeleted(ctx, name, &domain.Character{ID: name}); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, name)
+	}
+
+	return deleted, nil
+}
+
+// markDeleted marks the character as deleted, tells the delete listeners and
+// stops reporting its metrics.
+func (s Service) markDeleted(ctx context.Context, name string, c *domain.Character) (*domain.Character, error) {
+	now := time.Now().UTC()
+
+	if err := s.characters.MarkDeleted(ctx, name, now); err != nil {
+		return nil, err
+	}
+
+	c.DeletedAt = &now
+
+	for _, l := range s.deletions {
+		l.CharacterDeleted(ctx, name)
+	}
+
+	metrics.RemoveCharacterMetrics(s.realm, name)
 	return c, nil
 }
 
+// parseBinary parses the character binary, retrying with a backoff when it
+// fails to decode since the binary might be in the middle of being written.
+func (s Service) parseBinary(ctx context.Context, name string) (*domain.Character, error) {
+	backoff := parseBackoff
+
+	for attempt := 1; ; attempt++ {
+		parsed, err := s.parser.Parse(name)
+		if err == nil {
+			metrics.CharacterConsecutiveParseFailures.WithLabelValues(s.realm, name).Set(0)
+			return parsed, nil
+		}
+
+		// There's no point in retrying a binary that doesn't exist.
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+
+		metrics.CharacterParseFailuresTotal.WithLabelValues(s.realm, name).Inc()
+		metrics.CharacterConsecutiveParseFailures.WithLabelValues(s.realm, name).Inc()
+
+		if attempt == parseAttempts {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// notify tells all listeners about the parsed character.
+func (s Service) notify(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	for _, l := range s.listeners {
+		l.CharacterParsed(ctx, previous, current)
+	}
+}
+
 // NewService constructs a new parsing service with all the dependencies.
-func NewService(parser parser, characterRepository characterRepository, cacheDuration time.Duration) *Service {
-	return &Service{
+func NewService(parser parser, characterRepository characterRepository, cacheDuration time.Duration, opts ...Option) *Service {
+	s := &Service{
 		parser:        parser,
 		characters:    characterRepository,
 		cacheDuration: cacheDuration,
+		inflight:      &singleflight.Group{},
+		undecodable:   &sync.Map{},
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
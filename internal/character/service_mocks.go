@@ -7,6 +7,7 @@ import (
 	"context"
 	"github.com/nokka/d2-armory-api/internal/domain"
 	"sync"
+	"time"
 )
 
 // Ensure, that parserMock does implement parser.
@@ -19,9 +20,18 @@ var _ parser = &parserMock{}
 //
 // 		// make and configure a mocked parser
 // 		mockedparser := &parserMock{
+// 			FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+// 				panic("mock out the Fingerprint method")
+// 			},
+// 			NamesFunc: func() ([]string, error) {
+// 				panic("mock out the Names method")
+// 			},
 // 			ParseFunc: func(name string) (*domain.Character, error) {
 // 				panic("mock out the Parse method")
 // 			},
+// 			ParseContentFunc: func(data []byte) (*domain.Character, error) {
+// 				panic("mock out the ParseContent method")
+// 			},
 // 		}
 //
 // 		// use mockedparser in code that requires parser
@@ -29,18 +39,100 @@ var _ parser = &parserMock{}
 //
 // 	}
 type parserMock struct {
+	// FingerprintFunc mocks the Fingerprint method.
+	FingerprintFunc func(name string) (*domain.Fingerprint, error)
+
+	// NamesFunc mocks the Names method.
+	NamesFunc func() ([]string, error)
+
 	// ParseFunc mocks the Parse method.
 	ParseFunc func(name string) (*domain.Character, error)
 
+	// ParseContentFunc mocks the ParseContent method.
+	ParseContentFunc func(data []byte) (*domain.Character, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// Fingerprint holds details about calls to the Fingerprint method.
+		Fingerprint []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// Names holds details about calls to the Names method.
+		Names []struct {
+		}
 		// Parse holds details about calls to the Parse method.
 		Parse []struct {
 			// Name is the name argument value.
 			Name string
 		}
+		// ParseContent holds details about calls to the ParseContent method.
+		ParseContent []struct {
+			// Data is the data argument value.
+			Data []byte
+		}
+	}
+	lockFingerprint  sync.RWMutex
+	lockNames        sync.RWMutex
+	lockParse        sync.RWMutex
+	lockParseContent sync.RWMutex
+}
+
+// Fingerprint calls FingerprintFunc.
+func (mock *parserMock) Fingerprint(name string) (*domain.Fingerprint, error) {
+	if mock.FingerprintFunc == nil {
+		panic("parserMock.FingerprintFunc: method is nil but parser.Fingerprint was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockFingerprint.Lock()
+	mock.calls.Fingerprint = append(mock.calls.Fingerprint, callInfo)
+	mock.lockFingerprint.Unlock()
+	return mock.FingerprintFunc(name)
+}
+
+// FingerprintCalls gets all the calls that were made to Fingerprint.
+// Check the length with:
+//     len(mockedparser.FingerprintCalls())
+func (mock *parserMock) FingerprintCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
 	}
-	lockParse sync.RWMutex
+	mock.lockFingerprint.RLock()
+	calls = mock.calls.Fingerprint
+	mock.lockFingerprint.RUnlock()
+	return calls
+}
+
+// Names calls NamesFunc.
+func (mock *parserMock) Names() ([]string, error) {
+	if mock.NamesFunc == nil {
+		panic("parserMock.NamesFunc: method is nil but parser.Names was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockNames.Lock()
+	mock.calls.Names = append(mock.calls.Names, callInfo)
+	mock.lockNames.Unlock()
+	return mock.NamesFunc()
+}
+
+// NamesCalls gets all the calls that were made to Names.
+// Check the length with:
+//     len(mockedparser.NamesCalls())
+func (mock *parserMock) NamesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockNames.RLock()
+	calls = mock.calls.Names
+	mock.lockNames.RUnlock()
+	return calls
 }
 
 // Parse calls ParseFunc.
@@ -74,6 +166,37 @@ func (mock *parserMock) ParseCalls() []struct {
 	return calls
 }
 
+// ParseContent calls ParseContentFunc.
+func (mock *parserMock) ParseContent(data []byte) (*domain.Character, error) {
+	if mock.ParseContentFunc == nil {
+		panic("parserMock.ParseContentFunc: method is nil but parser.ParseContent was just called")
+	}
+	callInfo := struct {
+		Data []byte
+	}{
+		Data: data,
+	}
+	mock.lockParseContent.Lock()
+	mock.calls.ParseContent = append(mock.calls.ParseContent, callInfo)
+	mock.lockParseContent.Unlock()
+	return mock.ParseContentFunc(data)
+}
+
+// ParseContentCalls gets all the calls that were made to ParseContent.
+// Check the length with:
+//     len(mockedparser.ParseContentCalls())
+func (mock *parserMock) ParseContentCalls() []struct {
+	Data []byte
+} {
+	var calls []struct {
+		Data []byte
+	}
+	mock.lockParseContent.RLock()
+	calls = mock.calls.ParseContent
+	mock.lockParseContent.RUnlock()
+	return calls
+}
+
 // Ensure, that characterRepositoryMock does implement characterRepository.
 // If this is not the case, regenerate this file with moq.
 var _ characterRepository = &characterRepositoryMock{}
@@ -87,9 +210,18 @@ var _ characterRepository = &characterRepositoryMock{}
 // 			FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
 // 				panic("mock out the Find method")
 // 			},
+// 			MarkDeletedFunc: func(ctx context.Context, id string, at time.Time) error {
+// 				panic("mock out the MarkDeleted method")
+// 			},
+// 			NamesFunc: func(ctx context.Context) ([]string, error) {
+// 				panic("mock out the Names method")
+// 			},
 // 			StoreFunc: func(ctx context.Context, character *domain.Character) error {
 // 				panic("mock out the Store method")
 // 			},
+// 			TouchFunc: func(ctx context.Context, id string, at time.Time) error {
+// 				panic("mock out the Touch method")
+// 			},
 // 			UpdateFunc: func(ctx context.Context, character *domain.Character) error {
 // 				panic("mock out the Update method")
 // 			},
@@ -103,9 +235,18 @@ type characterRepositoryMock struct {
 	// FindFunc mocks the Find method.
 	FindFunc func(ctx context.Context, id string) (*domain.Character, error)
 
+	// MarkDeletedFunc mocks the MarkDeleted method.
+	MarkDeletedFunc func(ctx context.Context, id string, at time.Time) error
+
+	// NamesFunc mocks the Names method.
+	NamesFunc func(ctx context.Context) ([]string, error)
+
 	// StoreFunc mocks the Store method.
 	StoreFunc func(ctx context.Context, character *domain.Character) error
 
+	// TouchFunc mocks the Touch method.
+	TouchFunc func(ctx context.Context, id string, at time.Time) error
+
 	// UpdateFunc mocks the Update method.
 	UpdateFunc func(ctx context.Context, character *domain.Character) error
 
@@ -118,6 +259,20 @@ type characterRepositoryMock struct {
 			// ID is the id argument value.
 			ID string
 		}
+		// MarkDeleted holds details about calls to the MarkDeleted method.
+		MarkDeleted []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// At is the at argument value.
+			At time.Time
+		}
+		// Names holds details about calls to the Names method.
+		Names []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// Store holds details about calls to the Store method.
 		Store []struct {
 			// Ctx is the ctx argument value.
@@ -125,6 +280,15 @@ type characterRepositoryMock struct {
 			// Character is the character argument value.
 			Character *domain.Character
 		}
+		// Touch holds details about calls to the Touch method.
+		Touch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// At is the at argument value.
+			At time.Time
+		}
 		// Update holds details about calls to the Update method.
 		Update []struct {
 			// Ctx is the ctx argument value.
@@ -133,9 +297,12 @@ type characterRepositoryMock struct {
 			Character *domain.Character
 		}
 	}
-	lockFind   sync.RWMutex
-	lockStore  sync.RWMutex
-	lockUpdate sync.RWMutex
+	lockFind        sync.RWMutex
+	lockMarkDeleted sync.RWMutex
+	lockNames       sync.RWMutex
+	lockStore       sync.RWMutex
+	lockTouch       sync.RWMutex
+	lockUpdate      sync.RWMutex
 }
 
 // Find calls FindFunc.
@@ -173,6 +340,76 @@ func (mock *characterRepositoryMock) FindCalls() []struct {
 	return calls
 }
 
+// MarkDeleted calls MarkDeletedFunc.
+func (mock *characterRepositoryMock) MarkDeleted(ctx context.Context, id string, at time.Time) error {
+	if mock.MarkDeletedFunc == nil {
+		panic("characterRepositoryMock.MarkDeletedFunc: method is nil but characterRepository.MarkDeleted was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+		At  time.Time
+	}{
+		Ctx: ctx,
+		ID:  id,
+		At:  at,
+	}
+	mock.lockMarkDeleted.Lock()
+	mock.calls.MarkDeleted = append(mock.calls.MarkDeleted, callInfo)
+	mock.lockMarkDeleted.Unlock()
+	return mock.MarkDeletedFunc(ctx, id, at)
+}
+
+// MarkDeletedCalls gets all the calls that were made to MarkDeleted.
+// Check the length with:
+//     len(mockedcharacterRepository.MarkDeletedCalls())
+func (mock *characterRepositoryMock) MarkDeletedCalls() []struct {
+	Ctx context.Context
+	ID  string
+	At  time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+		At  time.Time
+	}
+	mock.lockMarkDeleted.RLock()
+	calls = mock.calls.MarkDeleted
+	mock.lockMarkDeleted.RUnlock()
+	return calls
+}
+
+// Names calls NamesFunc.
+func (mock *characterRepositoryMock) Names(ctx context.Context) ([]string, error) {
+	if mock.NamesFunc == nil {
+		panic("characterRepositoryMock.NamesFunc: method is nil but characterRepository.Names was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockNames.Lock()
+	mock.calls.Names = append(mock.calls.Names, callInfo)
+	mock.lockNames.Unlock()
+	return mock.NamesFunc(ctx)
+}
+
+// NamesCalls gets all the calls that were made to Names.
+// Check the length with:
+//     len(mockedcharacterRepository.NamesCalls())
+func (mock *characterRepositoryMock) NamesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockNames.RLock()
+	calls = mock.calls.Names
+	mock.lockNames.RUnlock()
+	return calls
+}
+
 // Store calls StoreFunc.
 func (mock *characterRepositoryMock) Store(ctx context.Context, character *domain.Character) error {
 	if mock.StoreFunc == nil {
@@ -208,6 +445,45 @@ func (mock *characterRepositoryMock) StoreCalls() []struct {
 	return calls
 }
 
+// Touch calls TouchFunc.
+func (mock *characterRepositoryMock) Touch(ctx context.Context, id string, at time.Time) error {
+	if mock.TouchFunc == nil {
+		panic("characterRepositoryMock.TouchFunc: method is nil but characterRepository.Touch was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+		At  time.Time
+	}{
+		Ctx: ctx,
+		ID:  id,
+		At:  at,
+	}
+	mock.lockTouch.Lock()
+	mock.calls.Touch = append(mock.calls.Touch, callInfo)
+	mock.lockTouch.Unlock()
+	return mock.TouchFunc(ctx, id, at)
+}
+
+// TouchCalls gets all the calls that were made to Touch.
+// Check the length with:
+//     len(mockedcharacterRepository.TouchCalls())
+func (mock *characterRepositoryMock) TouchCalls() []struct {
+	Ctx context.Context
+	ID  string
+	At  time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+		At  time.Time
+	}
+	mock.lockTouch.RLock()
+	calls = mock.calls.Touch
+	mock.lockTouch.RUnlock()
+	return calls
+}
+
 // Update calls UpdateFunc.
 func (mock *characterRepositoryMock) Update(ctx context.Context, character *domain.Character) error {
 	if mock.UpdateFunc == nil {
@@ -242,3 +518,151 @@ func (mock *characterRepositoryMock) UpdateCalls() []struct {
 	mock.lockUpdate.RUnlock()
 	return calls
 }
+
+// Ensure, that listenerMock does implement listener.
+// If this is not the case, regenerate this file with moq.
+var _ listener = &listenerMock{}
+
+// listenerMock is a mock implementation of listener.
+//
+// 	func TestSomethingThatUseslistener(t *testing.T) {
+//
+// 		// make and configure a mocked listener
+// 		mockedlistener := &listenerMock{
+// 			CharacterParsedFunc: func(ctx context.Context, previous *domain.Character, current *domain.Character)  {
+// 				panic("mock out the CharacterParsed method")
+// 			},
+// 		}
+//
+// 		// use mockedlistener in code that requires listener
+// 		// and then make assertions.
+//
+// 	}
+type listenerMock struct {
+	// CharacterParsedFunc mocks the CharacterParsed method.
+	CharacterParsedFunc func(ctx context.Context, previous *domain.Character, current *domain.Character)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CharacterParsed holds details about calls to the CharacterParsed method.
+		CharacterParsed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Previous is the previous argument value.
+			Previous *domain.Character
+			// Current is the current argument value.
+			Current *domain.Character
+		}
+	}
+	lockCharacterParsed sync.RWMutex
+}
+
+// CharacterParsed calls CharacterParsedFunc.
+func (mock *listenerMock) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if mock.CharacterParsedFunc == nil {
+		panic("listenerMock.CharacterParsedFunc: method is nil but listener.CharacterParsed was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Previous *domain.Character
+		Current  *domain.Character
+	}{
+		Ctx:      ctx,
+		Previous: previous,
+		Current:  current,
+	}
+	mock.lockCharacterParsed.Lock()
+	mock.calls.CharacterParsed = append(mock.calls.CharacterParsed, callInfo)
+	mock.lockCharacterParsed.Unlock()
+	mock.CharacterParsedFunc(ctx, previous, current)
+}
+
+// CharacterParsedCalls gets all the calls that were made to CharacterParsed.
+// Check the length with:
+//     len(mockedlistener.CharacterParsedCalls())
+func (mock *listenerMock) CharacterParsedCalls() []struct {
+	Ctx      context.Context
+	Previous *domain.Character
+	Current  *domain.Character
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Previous *domain.Character
+		Current  *domain.Character
+	}
+	mock.lockCharacterParsed.RLock()
+	calls = mock.calls.CharacterParsed
+	mock.lockCharacterParsed.RUnlock()
+	return calls
+}
+
+// Ensure, that deleteListenerMock does implement deleteListener.
+// If this is not the case, regenerate this file with moq.
+var _ deleteListener = &deleteListenerMock{}
+
+// deleteListenerMock is a mock implementation of deleteListener.
+//
+// 	func TestSomethingThatUsesdeleteListener(t *testing.T) {
+//
+// 		// make and configure a mocked deleteListener
+// 		mockeddeleteListener := &deleteListenerMock{
+// 			CharacterDeletedFunc: func(ctx context.Context, name string)  {
+// 				panic("mock out the CharacterDeleted method")
+// 			},
+// 		}
+//
+// 		// use mockeddeleteListener in code that requires deleteListener
+// 		// and then make assertions.
+//
+// 	}
+type deleteListenerMock struct {
+	// CharacterDeletedFunc mocks the CharacterDeleted method.
+	CharacterDeletedFunc func(ctx context.Context, name string)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CharacterDeleted holds details about calls to the CharacterDeleted method.
+		CharacterDeleted []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+	}
+	lockCharacterDeleted sync.RWMutex
+}
+
+// CharacterDeleted calls CharacterDeletedFunc.
+func (mock *deleteListenerMock) CharacterDeleted(ctx context.Context, name string) {
+	if mock.CharacterDeletedFunc == nil {
+		panic("deleteListenerMock.CharacterDeletedFunc: method is nil but deleteListener.CharacterDeleted was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	mock.lockCharacterDeleted.Lock()
+	mock.calls.CharacterDeleted = append(mock.calls.CharacterDeleted, callInfo)
+	mock.lockCharacterDeleted.Unlock()
+	mock.CharacterDeletedFunc(ctx, name)
+}
+
+// CharacterDeletedCalls gets all the calls that were made to CharacterDeleted.
+// Check the length with:
+//     len(mockeddeleteListener.CharacterDeletedCalls())
+func (mock *deleteListenerMock) CharacterDeletedCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	mock.lockCharacterDeleted.RLock()
+	calls = mock.calls.CharacterDeleted
+	mock.lockCharacterDeleted.RUnlock()
+	return calls
+}
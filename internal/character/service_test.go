@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ func TestParseCharacter(t *testing.T) {
 		name          string
 		ctx           context.Context
 		cacheDuration time.Duration
+		options       []Option
 	}
 
 	type fields struct {
@@ -55,6 +57,9 @@ func TestParseCharacter(t *testing.T) {
 					ParseFunc: func(name string) (*domain.Character, error) {
 						return &domain.Character{}, nil
 					},
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
 				},
 			},
 			calls: calls{
@@ -83,6 +88,9 @@ func TestParseCharacter(t *testing.T) {
 					ParseFunc: func(name string) (*domain.Character, error) {
 						return &domain.Character{}, nil
 					},
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
 				},
 			},
 			calls: calls{
@@ -111,6 +119,9 @@ func TestParseCharacter(t *testing.T) {
 					ParseFunc: func(name string) (*domain.Character, error) {
 						return &domain.Character{}, nil
 					},
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
 				},
 			},
 			calls: calls{
@@ -120,11 +131,63 @@ func TestParseCharacter(t *testing.T) {
 			},
 			expectedError: domain.ErrTemporary,
 		},
+		{
+			name: "update skipped when binary is unchanged",
+			args: args{
+				name:          "nokka",
+				ctx:           context.TODO(),
+				cacheDuration: 1 * time.Minute,
+			},
+			fields: fields{
+				characterRepository: &characterRepositoryMock{
+					FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+						return &domain.Character{
+							Fingerprint: domain.Fingerprint{Hash: "b4d1c0de"},
+						}, nil
+					},
+					TouchFunc: func(ctx context.Context, id string, at time.Time) error {
+						return nil
+					},
+				},
+				parser: &parserMock{
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
+				},
+			},
+			calls: calls{
+				storeCalls:  0,
+				parseCalls:  0,
+				updateCalls: 0,
+			},
+		},
+		{
+			name: "cached while watched",
+			args: args{
+				name:          "nokka",
+				ctx:           context.TODO(),
+				cacheDuration: 1 * time.Minute,
+				options:       []Option{WithWatcher()},
+			},
+			fields: fields{
+				characterRepository: &characterRepositoryMock{
+					FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+						return &domain.Character{}, nil
+					},
+				},
+				parser: &parserMock{},
+			},
+			calls: calls{
+				storeCalls:  0,
+				parseCalls:  0,
+				updateCalls: 0,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := NewService(tt.fields.parser, tt.fields.characterRepository, tt.args.cacheDuration)
+			s := NewService(tt.fields.parser, tt.fields.characterRepository, tt.args.cacheDuration, tt.args.options...)
 
 			_, err := s.Parse(tt.args.ctx, tt.args.name)
 
@@ -159,3 +222,908 @@ func TestParseCharacter(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshCharacter(t *testing.T) {
+	type fields struct {
+		characterRepository *characterRepositoryMock
+		parser              *parserMock
+	}
+
+	type calls struct {
+		storeCalls  int
+		updateCalls int
+		parseCalls  int
+	}
+
+	tests := []struct {
+		name          string
+		character     string
+		fields        fields
+		calls         calls
+		expectedError error
+	}{
+		{
+			name:      "refresh recently parsed character",
+			character: "nokka",
+			fields: fields{
+				characterRepository: &characterRepositoryMock{
+					FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+						return &domain.Character{LastParsed: time.Now()}, nil
+					},
+					UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+						return nil
+					},
+				},
+				parser: &parserMock{
+					ParseFunc: func(name string) (*domain.Character, error) {
+						return &domain.Character{}, nil
+					},
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
+				},
+			},
+			calls: calls{
+				parseCalls:  1,
+				updateCalls: 1,
+			},
+		},
+		{
+			name:      "refresh new character",
+			character: "nokka",
+			fields: fields{
+				characterRepository: &characterRepositoryMock{
+					FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+						return nil, domain.ErrNotFound
+					},
+					StoreFunc: func(ctx context.Context, character *domain.Character) error {
+						return nil
+					},
+				},
+				parser: &parserMock{
+					ParseFunc: func(name string) (*domain.Character, error) {
+						return &domain.Character{}, nil
+					},
+					FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+						return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+					},
+				},
+			},
+			calls: calls{
+				parseCalls: 1,
+				storeCalls: 1,
+			},
+		},
+		{
+			name:      "refresh file that isn't a character",
+			character: "nokka.key",
+			fields: fields{
+				characterRepository: &characterRepositoryMock{},
+				parser:              &parserMock{},
+			},
+			expectedError: domain.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.fields.parser, tt.fields.characterRepository, time.Minute, WithWatcher())
+
+			_, err := s.Refresh(context.TODO(), tt.character)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error to be = %v, got = %v", tt.expectedError, err)
+			}
+
+			if len(tt.fields.characterRepository.StoreCalls()) != tt.calls.storeCalls {
+				t.Errorf("expected characterRepository.Store() to be called exactly %d times but was called %d times",
+					tt.calls.storeCalls,
+					len(tt.fields.characterRepository.StoreCalls()),
+				)
+			}
+
+			if len(tt.fields.parser.ParseCalls()) != tt.calls.parseCalls {
+				t.Errorf("expected parser.Parse() to be called exactly %d times but was called %d times",
+					tt.calls.parseCalls,
+					len(tt.fields.parser.ParseCalls()),
+				)
+			}
+
+			if len(tt.fields.characterRepository.UpdateCalls()) != tt.calls.updateCalls {
+				t.Errorf("expected characterRepository.Update() to be called exactly %d times but was called %d times",
+					tt.calls.updateCalls,
+					len(tt.fields.characterRepository.UpdateCalls()),
+				)
+			}
+		})
+	}
+}
+
+func TestParseCharacterNotifiesListeners(t *testing.T) {
+	previous := &domain.Character{ID: "nokka"}
+
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			return previous, nil
+		},
+		UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		ParseFunc: func(name string) (*domain.Character, error) {
+			return &domain.Character{ID: name}, nil
+		},
+		FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+			return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+		},
+	}
+
+	l := &listenerMock{
+		CharacterParsedFunc: func(ctx context.Context, previous *domain.Character, current *domain.Character) {},
+	}
+
+	s := NewService(parser, repository, 0, WithListener(l))
+
+	parsed, err := s.Parse(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	calls := l.CharacterParsedCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected listener.CharacterParsed() to be called exactly 1 time but was called %d times", len(calls))
+	}
+
+	if calls[0].Previous != previous || calls[0].Current != parsed {
+		t.Errorf("expected listener to be called with the previous and parsed character")
+	}
+}
+
+func TestParseCharacterCoalescesConcurrentCalls(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		once    sync.Once
+	)
+
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			once.Do(func() { close(started) })
+			<-release
+			return nil, domain.ErrNotFound
+		},
+		StoreFunc: func(ctx context.Context, character *domain.Character) error {
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		ParseFunc: func(name string) (*domain.Character, error) {
+			return &domain.Character{ID: name}, nil
+		},
+	}
+
+	s := NewService(parser, repository, time.Minute)
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+	results := make(chan *domain.Character, callers)
+
+	call := func() {
+		defer wg.Done()
+
+		c, err := s.Parse(context.TODO(), "nokka")
+		if err != nil {
+			t.Errorf("didn't expect an error, got = %v", err)
+		}
+		results <- c
+	}
+
+	wg.Add(1)
+	go call()
+	<-started
+
+	wg.Add(callers - 1)
+	for i := 1; i < callers; i++ {
+		go call()
+	}
+
+	// Give the waiters time to join the call in flight before letting it finish.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if len(parser.ParseCalls()) != 1 {
+		t.Errorf("expected parser.Parse() to be called exactly 1 time but was called %d times", len(parser.ParseCalls()))
+	}
+
+	if len(repository.StoreCalls()) != 1 {
+		t.Errorf("expected characterRepository.Store() to be called exactly 1 time but was called %d times", len(repository.StoreCalls()))
+	}
+
+	for c := range results {
+		if c == nil || c.ID != "nokka" {
+			t.Errorf("expected every caller to get the parsed character, got = %v", c)
+		}
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			if id == "missing" {
+				return nil, fmt.Errorf("not found: %w", domain.ErrNotFound)
+			}
+			return &domain.Character{ID: id, LastParsed: time.Now()}, nil
+		},
+	}
+
+	parser := &parserMock{
+		ParseFunc: func(name string) (*domain.Character, error) {
+			return nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+		},
+	}
+
+	s := NewService(parser, repository, time.Minute)
+
+	names := []string{"nokka", "missing", "invalid name", "wheelz"}
+
+	results, err := s.ParseBatch(context.TODO(), names)
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(results) != len(names) {
+		t.Fatalf("expected %d results, got = %d", len(names), len(results))
+	}
+
+	for i, result := range results {
+		if result.Name != names[i] {
+			t.Errorf("expected result %d to be %s, got = %s", i, names[i], result.Name)
+		}
+	}
+
+	if results[0].Err != nil || results[3].Err != nil {
+		t.Errorf("expected existing characters to succeed, got = %v, %v", results[0].Err, results[3].Err)
+	}
+
+	if !errors.Is(results[1].Err, domain.ErrNotFound) {
+		t.Errorf("expected missing character to be not found, got = %v", results[1].Err)
+	}
+
+	if !errors.Is(results[2].Err, domain.ErrInvalidArgument) {
+		t.Errorf("expected invalid name to be an invalid argument, got = %v", results[2].Err)
+	}
+
+	if _, err := s.ParseBatch(context.TODO(), nil); !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected empty batch to be a request error, got = %v", err)
+	}
+}
+
+func TestParseUpload(t *testing.T) {
+	tests := []struct {
+		name          string
+		store         bool
+		parsed        *domain.Character
+		found         bool
+		storeCalls    int
+		updateCalls   int
+		expectedError error
+	}{
+		{
+			name:   "parse without storing",
+			parsed: &domain.Character{ID: "nokka", Uploaded: true},
+		},
+		{
+			name:       "store new character",
+			store:      true,
+			parsed:     &domain.Character{ID: "nokka", Uploaded: true},
+			storeCalls: 1,
+		},
+		{
+			name:        "replace stored character",
+			store:       true,
+			parsed:      &domain.Character{ID: "nokka", Uploaded: true},
+			found:       true,
+			updateCalls: 1,
+		},
+		{
+			name:          "refuse to store invalid name",
+			store:         true,
+			parsed:        &domain.Character{ID: "nokka 2", Uploaded: true},
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &characterRepositoryMock{
+				FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+					if !tt.found {
+						return nil, domain.ErrNotFound
+					}
+					return &domain.Character{ID: id}, nil
+				},
+				StoreFunc: func(ctx context.Context, character *domain.Character) error {
+					return nil
+				},
+				UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+					return nil
+				},
+			}
+
+			parser := &parserMock{
+				ParseContentFunc: func(data []byte) (*domain.Character, error) {
+					return tt.parsed, nil
+				},
+			}
+
+			s := NewService(parser, repository, time.Minute)
+
+			c, err := s.ParseUpload(context.TODO(), []byte("d2s"), tt.store)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if err == nil && c.ID != tt.parsed.ID {
+				t.Errorf("expected character %s, got = %s", tt.parsed.ID, c.ID)
+			}
+
+			if len(repository.StoreCalls()) != tt.storeCalls {
+				t.Errorf("expected %d store calls, got = %d", tt.storeCalls, len(repository.StoreCalls()))
+			}
+
+			if len(repository.UpdateCalls()) != tt.updateCalls {
+				t.Errorf("expected %d update calls, got = %d", tt.updateCalls, len(repository.UpdateCalls()))
+			}
+		})
+	}
+}
+
+func TestParseUploadedCharacter(t *testing.T) {
+	uploadedAt := time.Now().Add(-10 * time.Minute)
+
+	tests := []struct {
+		name        string
+		modified    time.Time
+		parseCalls  int
+		updateCalls int
+		uploaded    bool
+	}{
+		{
+			name:     "upload is served while the binary on disk is older",
+			modified: uploadedAt.Add(-time.Hour),
+			uploaded: true,
+		},
+		{
+			name:        "binary written on disk after the upload is parsed",
+			modified:    uploadedAt.Add(time.Minute),
+			parseCalls:  1,
+			updateCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &characterRepositoryMock{
+				FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+					return &domain.Character{
+						ID:          id,
+						Uploaded:    true,
+						LastParsed:  uploadedAt,
+						Fingerprint: domain.Fingerprint{Hash: "c0ffee"},
+					}, nil
+				},
+				UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+					return nil
+				},
+			}
+
+			parser := &parserMock{
+				ParseFunc: func(name string) (*domain.Character, error) {
+					return &domain.Character{ID: name, LastParsed: time.Now()}, nil
+				},
+				FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+					return &domain.Fingerprint{Hash: "b4d1c0de", Modified: tt.modified}, nil
+				},
+			}
+
+			s := NewService(parser, repository, time.Minute)
+
+			c, err := s.Parse(context.TODO(), "nokka")
+			if err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if c.Uploaded != tt.uploaded {
+				t.Errorf("expected uploaded to be %t, got = %t", tt.uploaded, c.Uploaded)
+			}
+
+			if len(parser.ParseCalls()) != tt.parseCalls {
+				t.Errorf("expected %d parse calls, got = %d", tt.parseCalls, len(parser.ParseCalls()))
+			}
+
+			if len(repository.UpdateCalls()) != tt.updateCalls {
+				t.Errorf("expected %d update calls, got = %d", tt.updateCalls, len(repository.UpdateCalls()))
+			}
+		})
+	}
+}
+
+func TestParseCharacterStaleWhileRevalidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		parsedAgo time.Duration
+		wantStale bool
+	}{
+		{
+			name:      "serve stale and refresh in the background",
+			parsedAgo: 10 * time.Minute,
+			wantStale: true,
+		},
+		{
+			name:      "parse when older than max staleness",
+			parsedAgo: 2 * time.Hour,
+			wantStale: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := make(chan struct{}, 1)
+
+			repository := &characterRepositoryMock{
+				FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+					return &domain.Character{ID: id, LastParsed: time.Now().Add(-tt.parsedAgo)}, nil
+				},
+				UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+					updated <- struct{}{}
+					return nil
+				},
+			}
+
+			parser := &parserMock{
+				ParseFunc: func(name string) (*domain.Character, error) {
+					return &domain.Character{ID: name, LastParsed: time.Now()}, nil
+				},
+				FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+					return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+				},
+			}
+
+			s := NewService(parser, repository, time.Minute, WithStaleWhileRevalidate(time.Hour))
+
+			c, err := s.Parse(context.TODO(), "nokka")
+			if err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if c.Stale != tt.wantStale {
+				t.Errorf("expected stale to be %t, got = %t", tt.wantStale, c.Stale)
+			}
+
+			select {
+			case <-updated:
+			case <-time.After(time.Second):
+				t.Fatal("expected the character to be refreshed")
+			}
+		})
+	}
+}
+
+func TestParseCharacterTouchesUnchangedBinary(t *testing.T) {
+	lastParsed := time.Now().Add(-10 * time.Minute)
+
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			return &domain.Character{
+				ID:          id,
+				Fingerprint: domain.Fingerprint{Hash: "b4d1c0de"},
+				LastParsed:  lastParsed,
+			}, nil
+		},
+		TouchFunc: func(ctx context.Context, id string, at time.Time) error {
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+			return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+		},
+	}
+
+	s := NewService(parser, repository, time.Minute)
+
+	c, err := s.Parse(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(repository.TouchCalls()) != 1 {
+		t.Fatalf("expected characterRepository.Touch() to be called exactly 1 time but was called %d times", len(repository.TouchCalls()))
+	}
+
+	if touched := repository.TouchCalls()[0].At; !touched.After(lastParsed) {
+		t.Errorf("expected lastparsed to move forward from %s, got = %s", lastParsed, touched)
+	}
+
+	if !c.LastParsed.After(lastParsed) {
+		t.Errorf("expected the served character to be parsed after %s, got = %s", lastParsed, c.LastParsed)
+	}
+
+	if len(parser.ParseCalls()) != 0 || len(repository.UpdateCalls()) != 0 {
+		t.Error("expected the unchanged binary not to be parsed and updated")
+	}
+}
+
+func TestParseCharacterRevalidatedUnchangedIsFresh(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		stored  = &domain.Character{ID: "nokka", Fingerprint: domain.Fingerprint{Hash: "b4d1c0de"}, LastParsed: time.Now().Add(-10 * time.Minute)}
+		touched = make(chan struct{}, 1)
+	)
+
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			c := *stored
+			return &c, nil
+		},
+		TouchFunc: func(ctx context.Context, id string, at time.Time) error {
+			mu.Lock()
+			stored.LastParsed = at
+			mu.Unlock()
+
+			touched <- struct{}{}
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+			return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+		},
+	}
+
+	s := NewService(parser, repository, time.Minute, WithStaleWhileRevalidate(time.Hour))
+
+	c, err := s.Parse(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if !c.Stale {
+		t.Fatal("expected the expired character to be served stale")
+	}
+
+	select {
+	case <-touched:
+	case <-time.After(time.Second):
+		t.Fatal("expected the character to be revalidated")
+	}
+
+	c, err = s.Parse(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if c.Stale {
+		t.Error("expected the revalidated character not to be stale")
+	}
+
+	if len(repository.TouchCalls()) != 1 {
+		t.Errorf("expected a single revalidation, got = %d", len(repository.TouchCalls()))
+	}
+}
+
+func TestParseCharacterFallsBackToLastStored(t *testing.T) {
+	parseError := errors.New("binary parse error: unexpected EOF")
+
+	tests := []struct {
+		name         string
+		failures     int
+		parseCalls   int
+		updateCalls  int
+		wantDegraded bool
+	}{
+		{
+			name:        "binary parses after a failed attempt",
+			failures:    1,
+			parseCalls:  2,
+			updateCalls: 1,
+		},
+		{
+			name:         "binary keeps failing to parse",
+			failures:     parseAttempts,
+			parseCalls:   parseAttempts,
+			wantDegraded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := &domain.Character{ID: "nokka"}
+
+			repository := &characterRepositoryMock{
+				FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+					return stored, nil
+				},
+				UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+					return nil
+				},
+			}
+
+			var mu sync.Mutex
+			failures := tt.failures
+
+			parser := &parserMock{
+				ParseFunc: func(name string) (*domain.Character, error) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					if failures > 0 {
+						failures--
+						return nil, parseError
+					}
+					return &domain.Character{ID: name}, nil
+				},
+				FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+					return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+				},
+			}
+
+			s := NewService(parser, repository, 0)
+
+			c, err := s.Parse(context.TODO(), "nokka")
+			if err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if c.Degraded != tt.wantDegraded {
+				t.Errorf("expected degraded to be %t, got = %t", tt.wantDegraded, c.Degraded)
+			}
+
+			if tt.wantDegraded && c != stored {
+				t.Errorf("expected the stored character to be served")
+			}
+
+			if len(parser.ParseCalls()) != tt.parseCalls {
+				t.Errorf("expected parser.Parse() to be called exactly %d times but was called %d times", tt.parseCalls, len(parser.ParseCalls()))
+			}
+
+			if len(repository.UpdateCalls()) != tt.updateCalls {
+				t.Errorf("expected characterRepository.Update() to be called exactly %d times but was called %d times", tt.updateCalls, len(repository.UpdateCalls()))
+			}
+		})
+	}
+}
+
+func TestParseCharacterOnlyRetriesChangedBinary(t *testing.T) {
+	stored := &domain.Character{ID: "nokka"}
+	hash := "b4d1c0de"
+
+	repository := &characterRepositoryMock{
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			return stored, nil
+		},
+		UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		ParseFunc: func(name string) (*domain.Character, error) {
+			return nil, errors.New("binary parse error: unexpected EOF")
+		},
+		FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+			return &domain.Fingerprint{Hash: hash}, nil
+		},
+	}
+
+	s := NewService(parser, repository, 0)
+
+	for i := 0; i < 2; i++ {
+		c, err := s.Parse(context.TODO(), "nokka")
+		if err != nil {
+			t.Fatalf("didn't expect an error, got = %v", err)
+		}
+
+		if !c.Degraded {
+			t.Error("expected the stored character to be served degraded")
+		}
+	}
+
+	if len(parser.ParseCalls()) != parseAttempts {
+		t.Errorf("expected the undecodable binary to be parsed once, got = %d parse calls", len(parser.ParseCalls()))
+	}
+
+	hash = "c0ffee"
+
+	if _, err := s.Parse(context.TODO(), "nokka"); err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(parser.ParseCalls()) != 2*parseAttempts {
+		t.Errorf("expected the changed binary to be parsed again, got = %d parse calls", len(parser.ParseCalls()))
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	repository := &characterRepositoryMock{
+		NamesFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"nokka", "wheelz", "deleted"}, nil
+		},
+		MarkDeletedFunc: func(ctx context.Context, id string, at time.Time) error {
+			return nil
+		},
+	}
+
+	parser := &parserMock{
+		NamesFunc: func() ([]string, error) {
+			return []string{"nokka", "wheelz", "newcomer"}, nil
+		},
+	}
+
+	l := &deleteListenerMock{
+		CharacterDeletedFunc: func(ctx context.Context, name string) {},
+	}
+
+	s := NewService(parser, repository, time.Minute, WithDeleteListener(l))
+
+	deleted, err := s.Reconcile(context.TODO())
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(deleted) != 1 || deleted[0] != "deleted" {
+		t.Errorf("expected only the character without a binary to be deleted, got = %v", deleted)
+	}
+
+	calls := repository.MarkDeletedCalls()
+	if len(calls) != 1 || calls[0].ID != "deleted" {
+		t.Errorf("expected characterRepository.MarkDeleted() to be called for the deleted character, got = %v", calls)
+	}
+
+	if notified := l.CharacterDeletedCalls(); len(notified) != 1 || notified[0].Name != "deleted" {
+		t.Errorf("expected the delete listener to be told about the deleted character, got = %v", notified)
+	}
+}
+
+func TestReconcileRefusesMassDeletion(t *testing.T) {
+	tests := []struct {
+		name   string
+		onDisk []string
+	}{
+		{
+			name:   "empty d2s directory",
+			onDisk: []string{},
+		},
+		{
+			name:   "most characters missing",
+			onDisk: []string{"nokka"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &characterRepositoryMock{
+				NamesFunc: func(ctx context.Context) ([]string, error) {
+					return []string{"nokka", "wheelz", "meanski", "atomic"}, nil
+				},
+				MarkDeletedFunc: func(ctx context.Context, id string, at time.Time) error {
+					return nil
+				},
+			}
+
+			parser := &parserMock{
+				NamesFunc: func() ([]string, error) {
+					return tt.onDisk, nil
+				},
+			}
+
+			s := NewService(parser, repository, time.Minute)
+
+			deleted, err := s.Reconcile(context.TODO())
+			if !errors.Is(err, domain.ErrUnavailable) {
+				t.Errorf("expected error to be = %v, got = %v", domain.ErrUnavailable, err)
+			}
+
+			if len(deleted) != 0 || len(repository.MarkDeletedCalls()) != 0 {
+				t.Errorf("expected no character to be marked as deleted, got = %v", deleted)
+			}
+		})
+	}
+}
+
+func TestParseDeletedCharacter(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		stored       *domain.Character
+		binaryExists bool
+		wantDeleted  bool
+		markCalls    int
+		updateCalls  int
+	}{
+		{
+			name:        "deleted character is served as deleted",
+			stored:      &domain.Character{ID: "nokka", DeletedAt: &deletedAt},
+			wantDeleted: true,
+		},
+		{
+			name:         "deleted character created again",
+			stored:       &domain.Character{ID: "nokka", DeletedAt: &deletedAt},
+			binaryExists: true,
+			updateCalls:  1,
+		},
+		{
+			name:        "binary vanished since the last parse",
+			stored:      &domain.Character{ID: "nokka"},
+			wantDeleted: true,
+			markCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &characterRepositoryMock{
+				FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+					return tt.stored, nil
+				},
+				UpdateFunc: func(ctx context.Context, character *domain.Character) error {
+					return nil
+				},
+				MarkDeletedFunc: func(ctx context.Context, id string, at time.Time) error {
+					return nil
+				},
+			}
+
+			parser := &parserMock{
+				ParseFunc: func(name string) (*domain.Character, error) {
+					return &domain.Character{ID: name}, nil
+				},
+				FingerprintFunc: func(name string) (*domain.Fingerprint, error) {
+					if !tt.binaryExists {
+						return nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+					}
+					return &domain.Fingerprint{Hash: "b4d1c0de"}, nil
+				},
+			}
+
+			l := &deleteListenerMock{
+				CharacterDeletedFunc: func(ctx context.Context, name string) {},
+			}
+
+			s := NewService(parser, repository, 0, WithDeleteListener(l))
+
+			c, err := s.Parse(context.TODO(), "nokka")
+			if err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if (c.DeletedAt != nil) != tt.wantDeleted {
+				t.Errorf("expected deleted to be %t, got = %v", tt.wantDeleted, c.DeletedAt)
+			}
+
+			if len(repository.MarkDeletedCalls()) != tt.markCalls {
+				t.Errorf("expected characterRepository.MarkDeleted() to be called exactly %d times but was called %d times", tt.markCalls, len(repository.MarkDeletedCalls()))
+			}
+
+			if len(l.CharacterDeletedCalls()) != tt.markCalls {
+				t.Errorf("expected deleteListener.CharacterDeleted() to be called exactly %d times but was called %d times", tt.markCalls, len(l.CharacterDeletedCalls()))
+			}
+
+			if len(repository.UpdateCalls()) != tt.updateCalls {
+				t.Errorf("expected characterRepository.Update() to be called exactly %d times but was called %d times", tt.updateCalls, len(repository.UpdateCalls()))
+			}
+		})
+	}
+}
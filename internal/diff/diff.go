@@ -0,0 +1,281 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// entry is an item together with the location it's kept in.
+type entry struct {
+	item     d2s.Item
+	location string
+}
+
+// Compare returns the difference between two versions of the same character,
+// going from the first to the second.
+func Compare(from *domain.Character, to *domain.Character) *domain.CharacterDiff {
+	a, b := characterOf(from), characterOf(to)
+
+	diff := &domain.CharacterDiff{
+		Level:      valueChange(a.Attributes.Level, b.Attributes.Level),
+		Experience: valueChange(a.Attributes.Experience, b.Attributes.Experience),
+		Attributes: attributeChanges(a.Attributes, b.Attributes),
+		Skills:     skillChanges(a.Skills, b.Skills),
+		Items:      itemChanges(entries(a), entries(b)),
+	}
+
+	if from != nil {
+		diff.Character = from.ID
+		diff.From = from.LastParsed
+	}
+
+	if to != nil {
+		diff.Character = to.ID
+		diff.To = to.LastParsed
+	}
+
+	return diff
+}
+
+// characterOf returns the binary of the character, or an empty one if it's missing.
+func characterOf(c *domain.Character) *d2s.Character {
+	if c == nil || c.D2s == nil {
+		return &d2s.Character{}
+	}
+
+	return c.D2s
+}
+
+func valueChange(from uint64, to uint64) domain.ValueChange {
+	return domain.ValueChange{
+		From:   from,
+		To:     to,
+		Change: int64(to) - int64(from),
+	}
+}
+
+// attributeChanges returns the change of every attribute that changed.
+func attributeChanges(from d2s.Attributes, to d2s.Attributes) map[string]int {
+	changes := map[string]int{}
+
+	for name, values := range map[string][2]uint64{
+		"strength":            {from.Strength, to.Strength},
+		"energy":              {from.Energy, to.Energy},
+		"dexterity":           {from.Dexterity, to.Dexterity},
+		"vitality":            {from.Vitality, to.Vitality},
+		"unused_stats":        {from.UnusedStats, to.UnusedStats},
+		"unused_skill_points": {from.UnusedSkillPoints, to.UnusedSkillPoints},
+		"max_hp":              {from.MaxHP, to.MaxHP},
+		"max_mana":            {from.MaxMana, to.MaxMana},
+		"max_stamina":         {from.MaxStamina, to.MaxStamina},
+		"gold":                {from.Gold, to.Gold},
+		"stashed_gold":        {from.StashedGold, to.StashedGold},
+	} {
+		if change := int(values[1]) - int(values[0]); change != 0 {
+			changes[name] = change
+		}
+	}
+
+	return changes
+}
+
+// skillChanges returns every skill that had points added or removed.
+func skillChanges(from []d2s.Skill, to []d2s.Skill) []domain.SkillChange {
+	points := make(map[int]int, len(from))
+	for _, skill := range from {
+		points[skill.ID] = skill.Points
+	}
+
+	changes := make([]domain.SkillChange, 0)
+
+	for _, skill := range to {
+		previous := points[skill.ID]
+		delete(points, skill.ID)
+
+		if skill.Points == previous {
+			continue
+		}
+
+		changes = append(changes, domain.SkillChange{
+			ID:     skill.ID,
+			Name:   skill.Name,
+			From:   previous,
+			To:     skill.Points,
+			Change: skill.Points - previous,
+		})
+	}
+
+	// Skills that only exist in the first version, which would mean the class changed.
+	for _, skill := range from {
+		if _, ok := points[skill.ID]; ok && skill.Points > 0 {
+			changes = append(changes, domain.SkillChange{
+				ID:     skill.ID,
+				Name:   skill.Name,
+				From:   skill.Points,
+				Change: -skill.Points,
+			})
+		}
+	}
+
+	return changes
+}
+
+// entries returns all items of the character with their location.
+func entries(c *d2s.Character) []entry {
+	list := make([]entry, 0, len(c.Items)+len(c.CorpseItems)+len(c.MercItems))
+
+	for _, item := range c.Items {
+		list = append(list, entry{item: item, location: domain.ItemLocation(item)})
+	}
+
+	for _, item := range c.CorpseItems {
+		list = append(list, entry{item: item, location: domain.LocationCorpse})
+	}
+
+	for _, item := range c.MercItems {
+		list = append(list, entry{item: item, location: domain.LocationMercenary})
+	}
+
+	return list
+}
+
+// itemChanges matches the items of both versions. Extended items have a unique
+// id so they're matched on it, simple items such as runes and gems don't, so
+// they're matched by type, preferring items that stayed in the same location.
+func itemChanges(from []entry, to []entry) domain.ItemChanges {
+	changes := domain.ItemChanges{
+		Added:   make([]domain.ItemChange, 0),
+		Removed: make([]domain.ItemChange, 0),
+		Moved:   make([]domain.ItemChange, 0),
+	}
+
+	fromIDs, fromSimple := index(from)
+	toIDs, toSimple := index(to)
+
+	for _, id := range sortedIDs(fromIDs) {
+		a := fromIDs[id]
+
+		b, ok := toIDs[id]
+		if !ok {
+			changes.Removed = append(changes.Removed, itemChange(a.item, a.location, ""))
+			continue
+		}
+
+		if a.location != b.location {
+			changes.Moved = append(changes.Moved, itemChange(b.item, a.location, b.location))
+		}
+	}
+
+	for _, id := range sortedIDs(toIDs) {
+		if _, ok := fromIDs[id]; !ok {
+			b := toIDs[id]
+			changes.Added = append(changes.Added, itemChange(b.item, "", b.location))
+		}
+	}
+
+	for _, typ := range sortedTypes(fromSimple, toSimple) {
+		a, b := unmatched(fromSimple[typ], toSimple[typ])
+
+		// Pair up what's left, those are items that changed location.
+		for len(a) > 0 && len(b) > 0 {
+			changes.Moved = append(changes.Moved, itemChange(b[0].item, a[0].location, b[0].location))
+			a, b = a[1:], b[1:]
+		}
+
+		for _, e := range a {
+			changes.Removed = append(changes.Removed, itemChange(e.item, e.location, ""))
+		}
+
+		for _, e := range b {
+			changes.Added = append(changes.Added, itemChange(e.item, "", e.location))
+		}
+	}
+
+	return changes
+}
+
+// index splits the items into extended items by id and simple items by type.
+func index(list []entry) (map[uint64]entry, map[string][]entry) {
+	ids := make(map[uint64]entry)
+	simple := make(map[string][]entry)
+
+	for _, e := range list {
+		if e.item.SimpleItem == 0 && e.item.ID != 0 {
+			ids[e.item.ID] = e
+			continue
+		}
+
+		simple[e.item.Type] = append(simple[e.item.Type], e)
+	}
+
+	return ids, simple
+}
+
+// unmatched removes items of the same type that are in the same location in
+// both versions, and returns what's left of both.
+func unmatched(from []entry, to []entry) ([]entry, []entry) {
+	left := make([]entry, 0, len(from))
+	right := append([]entry(nil), to...)
+
+	for _, a := range from {
+		matched := false
+
+		for i, b := range right {
+			if a.location == b.location {
+				right = append(right[:i], right[i+1:]...)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			left = append(left, a)
+		}
+	}
+
+	return left, right
+}
+
+func itemChange(item d2s.Item, from string, to string) domain.ItemChange {
+	return domain.ItemChange{
+		ID:           item.ID,
+		Type:         item.Type,
+		Name:         domain.ItemName(item),
+		Quality:      item.Quality,
+		FromLocation: from,
+		ToLocation:   to,
+	}
+}
+
+func sortedIDs(m map[uint64]entry) []uint64 {
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+func sortedTypes(maps ...map[string][]entry) []string {
+	seen := make(map[string]struct{})
+	types := make([]string, 0)
+
+	for _, m := range maps {
+		for typ := range m {
+			if _, ok := seen[typ]; !ok {
+				seen[typ] = struct{}{}
+				types = append(types, typ)
+			}
+		}
+	}
+
+	sort.Strings(types)
+
+	return types
+}
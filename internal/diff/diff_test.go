@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestCompare(t *testing.T) {
+	shako := d2s.Item{ID: 1, Type: "uap", TypeName: "Shako", UniqueName: "Harlequin Crest", LocationID: 1}
+	enigma := d2s.Item{ID: 2, Type: "uui", TypeName: "Dusk Shroud", RunewordName: "Enigma", AltPositionID: 5}
+	ist := d2s.Item{SimpleItem: 1, Type: "r24", TypeName: "Ist Rune", AltPositionID: 5}
+	mal := d2s.Item{SimpleItem: 1, Type: "r23", TypeName: "Mal Rune", AltPositionID: 1}
+
+	movedEnigma := enigma
+	movedEnigma.LocationID = 1
+	movedEnigma.AltPositionID = 0
+
+	movedIst := ist
+	movedIst.AltPositionID = 4
+
+	from := &domain.Character{
+		ID:         "nokka",
+		LastParsed: time.Now().Add(-time.Hour),
+		D2s: &d2s.Character{
+			Attributes: d2s.Attributes{Level: 80, Experience: 1000, Strength: 100},
+			Skills:     []d2s.Skill{{ID: 54, Name: "Teleport", Points: 1}, {ID: 59, Name: "Blizzard", Points: 10}},
+			Items:      []d2s.Item{shako, enigma, ist, ist, mal},
+		},
+	}
+
+	to := &domain.Character{
+		ID:         "nokka",
+		LastParsed: time.Now(),
+		D2s: &d2s.Character{
+			Attributes: d2s.Attributes{Level: 82, Experience: 3000, Strength: 105},
+			Skills:     []d2s.Skill{{ID: 54, Name: "Teleport", Points: 1}, {ID: 59, Name: "Blizzard", Points: 14}},
+			Items:      []d2s.Item{movedEnigma, ist, movedIst},
+			MercItems:  []d2s.Item{shako},
+		},
+	}
+
+	diff := Compare(from, to)
+
+	if diff.Level.Change != 2 {
+		t.Errorf("expected level change to be = 2, got = %d", diff.Level.Change)
+	}
+
+	if diff.Experience.Change != 2000 {
+		t.Errorf("expected experience change to be = 2000, got = %d", diff.Experience.Change)
+	}
+
+	if len(diff.Attributes) != 1 || diff.Attributes["strength"] != 5 {
+		t.Errorf("expected only strength to change by 5, got = %v", diff.Attributes)
+	}
+
+	if len(diff.Skills) != 1 || diff.Skills[0].Name != "Blizzard" || diff.Skills[0].Change != 4 {
+		t.Errorf("expected only blizzard to change by 4, got = %+v", diff.Skills)
+	}
+
+	if len(diff.Items.Added) != 0 {
+		t.Errorf("expected no added items, got = %+v", diff.Items.Added)
+	}
+
+	if len(diff.Items.Removed) != 1 || diff.Items.Removed[0].Name != "Mal Rune" {
+		t.Errorf("expected the mal rune to be removed, got = %+v", diff.Items.Removed)
+	}
+
+	moved := map[string]domain.ItemChange{}
+	for _, change := range diff.Items.Moved {
+		moved[change.Name] = change
+	}
+
+	for name, want := range map[string][2]string{
+		"Harlequin Crest": {domain.LocationEquipped, domain.LocationMercenary},
+		"Enigma":          {domain.LocationStash, domain.LocationEquipped},
+		"Ist Rune":        {domain.LocationStash, domain.LocationCube},
+	} {
+		got, ok := moved[name]
+		if !ok {
+			t.Errorf("expected %s to be moved", name)
+			continue
+		}
+
+		if got.FromLocation != want[0] || got.ToLocation != want[1] {
+			t.Errorf("expected %s to move from %s to %s, got = %s to %s", name, want[0], want[1], got.FromLocation, got.ToLocation)
+		}
+	}
+
+	if len(diff.Items.Moved) != 3 {
+		t.Errorf("expected 3 moved items, got = %+v", diff.Items.Moved)
+	}
+}
+
+func TestCompareNewCharacter(t *testing.T) {
+	to := &domain.Character{
+		ID: "nokka",
+		D2s: &d2s.Character{
+			Attributes: d2s.Attributes{Level: 1},
+			Items:      []d2s.Item{{ID: 1, Type: "hax", TypeName: "Hand Axe", AltPositionID: 1}},
+		},
+	}
+
+	diff := Compare(nil, to)
+
+	if diff.Character != "nokka" {
+		t.Errorf("expected character to be = nokka, got = %s", diff.Character)
+	}
+
+	if len(diff.Items.Added) != 1 || diff.Items.Added[0].ToLocation != domain.LocationInventory {
+		t.Errorf("expected the hand axe to be added to the inventory, got = %+v", diff.Items.Added)
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Account is a battle.net account and the names of the characters created on it.
+type Account struct {
+	Name       string   `json:"name"`
+	Characters []string `json:"characters"`
+}
+
+// AccountSummary is an account together with a summary of each of its characters.
+type AccountSummary struct {
+	Name       string             `json:"name"`
+	Characters []CharacterSummary `json:"characters"`
+}
+
+// CharacterSummary is the short version of a character used in listings, the
+// error is set instead when the character couldn't be looked up.
+type CharacterSummary struct {
+	Name       string    `json:"name"`
+	Class      string    `json:"class"`
+	Level      uint64    `json:"level"`
+	Hardcore   bool      `json:"hardcore"`
+	LastParsed time.Time `json:"last_parsed"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Summary returns the summary of the character.
+func (c *Character) Summary() CharacterSummary {
+	summary := CharacterSummary{
+		Name:       c.ID,
+		LastParsed: c.LastParsed,
+	}
+
+	if c.D2s != nil {
+		summary.Class = c.D2s.Header.Class.String()
+		summary.Level = c.D2s.Attributes.Level
+		summary.Hardcore = c.D2s.Header.Status.Readable().Hardcore
+	}
+
+	return summary
+}
@@ -1,14 +1,80 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/nokka/d2s"
 )
 
+// CharacterClasses maps the lowercase names of the character classes to their id.
+var CharacterClasses = map[string]uint8{
+	"amazon":      d2s.Amazon,
+	"sorceress":   d2s.Sorceress,
+	"necromancer": d2s.Necromancer,
+	"paladin":     d2s.Paladin,
+	"barbarian":   d2s.Barbarian,
+	"druid":       d2s.Druid,
+	"assassin":    d2s.Assassin,
+}
+
 // Character represents a Diablo II character.
 type Character struct {
-	ID         string         `json:"d2s_id"`
-	D2s        *d2s.Character `json:"d2s"`
-	LastParsed time.Time      `json:"last_parsed"`
+	ID          string         `json:"d2s_id"`
+	Realm       string         `json:"realm"`
+	D2s         *d2s.Character `json:"d2s"`
+	LastParsed  time.Time      `json:"last_parsed"`
+	Fingerprint Fingerprint    `json:"fingerprint"`
+	Uploaded    bool           `json:"uploaded"`
+	DeletedAt   *time.Time     `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+
+	// Stale is set when the character is served from the cache while it's
+	// being refreshed in the background, it's never persisted.
+	Stale bool `json:"-" bson:"-"`
+
+	// Degraded is set when the binary failed to parse and the last stored
+	// version is served instead, it's never persisted.
+	Degraded bool `json:"-" bson:"-"`
+}
+
+// Dead tells if the character is a hardcore character that has died, the
+// character can't be played anymore once that happens.
+func (c *Character) Dead() bool {
+	if c == nil || c.D2s == nil {
+		return false
+	}
+
+	status := c.D2s.Header.Status.Readable()
+
+	return status.Hardcore && (status.Died || c.D2s.IsDead > 0)
+}
+
+// Decoded returns the decoded binary of the character, nothing more can be
+// decoded from characters that were deleted or that have no binary.
+func (c *Character) Decoded() (*d2s.Character, error) {
+	if c.DeletedAt != nil {
+		return nil, fmt.Errorf("character was deleted: %w", ErrGone)
+	}
+
+	if c.D2s == nil {
+		return nil, fmt.Errorf("character has not been parsed: %w", ErrNotFound)
+	}
+
+	return c.D2s, nil
+}
+
+// Fingerprint identifies the content of the binary a character was parsed from,
+// it's used to detect if the binary has changed since we last parsed it.
+type Fingerprint struct {
+	Hash     string    `json:"hash"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// CharacterResult is the outcome of looking up one of several characters,
+// either the character or the error that prevented us from getting it is set.
+type CharacterResult struct {
+	Name      string
+	Character *Character
+	Err       error
 }
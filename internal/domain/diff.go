@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// CharacterDiff is the structured difference between two versions of a character.
+type CharacterDiff struct {
+	Character  string         `json:"character"`
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Level      ValueChange    `json:"level"`
+	Experience ValueChange    `json:"experience"`
+	Attributes map[string]int `json:"attributes"`
+	Skills     []SkillChange  `json:"skills"`
+	Items      ItemChanges    `json:"items"`
+}
+
+// ValueChange describes how a value changed between two versions.
+type ValueChange struct {
+	From   uint64 `json:"from"`
+	To     uint64 `json:"to"`
+	Change int64  `json:"change"`
+}
+
+// SkillChange describes points added to or removed from a skill.
+type SkillChange struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Change int    `json:"change"`
+}
+
+// ItemChanges groups the items that were added, removed or moved.
+type ItemChanges struct {
+	Added   []ItemChange `json:"added"`
+	Removed []ItemChange `json:"removed"`
+	Moved   []ItemChange `json:"moved"`
+}
+
+// ItemChange describes an item and where it was before and after the change,
+// the location is empty on the side where the item didn't exist.
+type ItemChange struct {
+	ID           uint64 `json:"id,omitempty"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Quality      uint64 `json:"quality,omitempty"`
+	FromLocation string `json:"from_location,omitempty"`
+	ToLocation   string `json:"to_location,omitempty"`
+}
@@ -17,6 +17,9 @@ const (
 	// ErrInvalidArgument is returned when one or more arguments are invalid.
 	ErrInvalidArgument = Error("invalid argument")
 
+	// ErrGone is returned when a resource existed but has been deleted.
+	ErrGone = Error("resource is gone")
+
 	// ErrUnavailable is returned when the service is unavailable.
 	ErrUnavailable = Error("service unavailable")
 
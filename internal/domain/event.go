@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Event types of what happens to characters.
+const (
+	EventCharacterCreated   = "character.created"
+	EventCharacterLevelUp   = "character.level_up"
+	EventCharacterMilestone = "character.milestone"
+	EventCharacterDied      = "character.died"
+	EventItemFound          = "item.found"
+	EventStatisticsPosted   = "statistics.posted"
+	EventCharacterUpdated   = "character.updated"
+)
+
+// Event is something that happened to a character.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Realm      string      `json:"realm,omitempty"`
+	Character  string      `json:"character"`
+	Message    string      `json:"message"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data,omitempty"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/nokka/d2s"
+)
+
+// Death is the record of a hardcore character as it looked when it died.
+type Death struct {
+	Character  string     `json:"character"`
+	Realm      string     `json:"realm"`
+	Class      string     `json:"class"`
+	Level      uint64     `json:"level"`
+	Experience uint64     `json:"experience"`
+	Area       string     `json:"area"`
+	DiedAt     time.Time  `json:"died_at" bson:"died_at"`
+	Items      []d2s.Item `json:"items"`
+}
+
+// GraveyardQuery filters and paginates the deaths of a realm.
+type GraveyardQuery struct {
+	Class    string
+	MinLevel uint64
+	MaxLevel uint64
+	Cursor   string
+	Limit    int
+}
+
+// GraveyardPage is a page of deaths, most recent first, the cursor is empty on the last page.
+type GraveyardPage struct {
+	Deaths     []Death `json:"deaths"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/nokka/d2s"
+)
+
+// Snapshot is a version of a character as it looked at the time it was parsed.
+type Snapshot struct {
+	Character  string         `json:"character"`
+	Realm      string         `json:"realm"`
+	Hash       string         `json:"hash"`
+	ParsedAt   time.Time      `json:"parsed_at" bson:"parsed_at"`
+	Level      uint64         `json:"level"`
+	Experience uint64         `json:"experience"`
+	Attributes d2s.Attributes `json:"attributes"`
+	Skills     []d2s.Skill    `json:"skills"`
+	Items      []d2s.Item     `json:"items"`
+	MercItems  []d2s.Item     `json:"merc_items" bson:"merc_items"`
+}
+
+// HistoryQuery filters and paginates the snapshots of a character.
+type HistoryQuery struct {
+	From   time.Time
+	To     time.Time
+	Cursor string
+	Limit  int
+}
+
+// HistoryPage is a page of snapshots, newest first, the cursor is empty on the last page.
+type HistoryPage struct {
+	Snapshots  []Snapshot `json:"snapshots"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,163 @@
+package domain
+
+import "github.com/nokka/d2s"
+
+// Item locations, where a character keeps an item.
+const (
+	LocationEquipped  = "equipped"
+	LocationInventory = "inventory"
+	LocationBelt      = "belt"
+	LocationStash     = "stash"
+	LocationCube      = "cube"
+	LocationCursor    = "cursor"
+	LocationMercenary = "mercenary"
+	LocationCorpse    = "corpse"
+	LocationUnknown   = "unknown"
+)
+
+// Item qualities of the d2s item format.
+const (
+	ItemQualityLow      = 0x01
+	ItemQualityNormal   = 0x02
+	ItemQualitySuperior = 0x03
+	ItemQualityMagic    = 0x04
+	ItemQualitySet      = 0x05
+	ItemQualityRare     = 0x06
+	ItemQualityUnique   = 0x07
+	ItemQualityCrafted  = 0x08
+)
+
+// ItemQualities maps the names item qualities are searched by to their id.
+var ItemQualities = map[string]uint64{
+	"low":      ItemQualityLow,
+	"normal":   ItemQualityNormal,
+	"superior": ItemQualitySuperior,
+	"magic":    ItemQualityMagic,
+	"set":      ItemQualitySet,
+	"rare":     ItemQualityRare,
+	"unique":   ItemQualityUnique,
+	"crafted":  ItemQualityCrafted,
+}
+
+// The location and storage ids used by the d2s item format.
+const (
+	itemLocationStored   = 0
+	itemLocationEquipped = 1
+	itemLocationBelt     = 2
+	itemLocationCursor   = 4
+
+	itemStorageInventory = 1
+	itemStorageCube      = 4
+	itemStorageStash     = 5
+)
+
+// ItemLocation returns where the item is kept by the character, items of the
+// mercenary or corpse are not marked as such in the binary so those locations
+// are decided by the list the item belongs to.
+func ItemLocation(item d2s.Item) string {
+	switch item.LocationID {
+	case itemLocationEquipped:
+		return LocationEquipped
+	case itemLocationBelt:
+		return LocationBelt
+	case itemLocationCursor:
+		return LocationCursor
+	case itemLocationStored:
+		switch item.AltPositionID {
+		case itemStorageInventory:
+			return LocationInventory
+		case itemStorageCube:
+			return LocationCube
+		case itemStorageStash:
+			return LocationStash
+		}
+	}
+
+	return LocationUnknown
+}
+
+// ItemName returns the name the item is displayed with in game, falling back
+// to the name of the base type for items without a name of their own.
+func ItemName(item d2s.Item) string {
+	switch {
+	case item.UniqueName != "":
+		return item.UniqueName
+	case item.SetName != "":
+		return item.SetName
+	case item.RunewordName != "":
+		return item.RunewordName
+	case item.RareName != "":
+		if item.RareName2 != "" {
+			return item.RareName + " " + item.RareName2
+		}
+		return item.RareName
+	}
+
+	return item.TypeName
+}
+
+// IndexedItem is an item of a character as it's kept in the item index, the
+// index is rebuilt from the character every time it's parsed with changes.
+type IndexedItem struct {
+	Character  string          `json:"character"`
+	Realm      string          `json:"realm"`
+	Location   string          `json:"location"`
+	ItemID     uint64          `json:"item_id,omitempty" bson:"item_id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	TypeName   string          `json:"type_name" bson:"type_name"`
+	Quality    uint64          `json:"quality"`
+	SetName    string          `json:"set_name,omitempty" bson:"set_name"`
+	UniqueName string          `json:"unique_name,omitempty" bson:"unique_name"`
+	Runeword   string          `json:"runeword,omitempty"`
+	Ethereal   bool            `json:"ethereal"`
+	Sockets    uint64          `json:"sockets"`
+	Attributes []ItemAttribute `json:"attributes"`
+}
+
+// ItemAttribute is a magic attribute of an item, the value is the amount the
+// attribute adds, which is the last of its values.
+type ItemAttribute struct {
+	ID     uint64  `json:"id"`
+	Name   string  `json:"name"`
+	Values []int64 `json:"values"`
+	Value  int64   `json:"value"`
+}
+
+// ItemQuery filters and paginates the item index, empty filters are ignored.
+type ItemQuery struct {
+	Name       string
+	Type       string
+	Quality    string
+	SetName    string
+	UniqueName string
+	Runeword   string
+	Ethereal   *bool
+	MinSockets uint64
+	Attributes []AttributeFilter
+	Cursor     string
+	Limit      int
+}
+
+// AttributeFilter matches items with an attribute compared to a value, such as
+// +skills>=2. The key is either the id of the attribute or a well known alias.
+type AttributeFilter struct {
+	Key      string
+	ID       uint64
+	Operator string
+	Value    int64
+}
+
+// ItemPage is a page of the item index, the cursor is empty on the last page.
+type ItemPage struct {
+	Items      []IndexedItem `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// ItemTooltips holds the tooltip lines of the items of a character, in the
+// same order as the item lists of the character.
+type ItemTooltips struct {
+	Items       [][]string `json:"items"`
+	CorpseItems [][]string `json:"corpse_items"`
+	MercItems   [][]string `json:"merc_items"`
+}
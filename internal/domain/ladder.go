@@ -0,0 +1,30 @@
+package domain
+
+// LadderQuery filters and paginates the ladder, filters that aren't set are ignored.
+type LadderQuery struct {
+	Class     string
+	Hardcore  *bool
+	Expansion *bool
+	Cursor    string
+	Offset    int
+	Limit     int
+}
+
+// LadderEntry is a character and its rank on the ladder. The previous rank is
+// the rank the character had at the end of the previous day, and is empty for
+// characters that weren't on the ladder yet.
+type LadderEntry struct {
+	Rank         int              `json:"rank"`
+	PreviousRank *int             `json:"previous_rank"`
+	RankChange   *int             `json:"rank_change"`
+	Character    CharacterSummary `json:"character"`
+	Experience   uint64           `json:"experience"`
+	Expansion    bool             `json:"expansion"`
+}
+
+// LadderPage is a page of the ladder, the cursor is empty on the last page.
+type LadderPage struct {
+	Entries    []LadderEntry `json:"entries"`
+	Total      int64         `json:"total"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
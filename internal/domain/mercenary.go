@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/nokka/d2s"
+)
+
+// Classes of the mercenaries, one is hired in every act but act IV.
+const (
+	MercenaryRogueScout      = "Rogue Scout"
+	MercenaryDesertMercenary = "Desert Mercenary"
+	MercenaryIronWolf        = "Iron Wolf"
+	MercenaryBarbarian       = "Barbarian"
+)
+
+// maxMercenaryLevel is the highest level a mercenary can reach.
+const maxMercenaryLevel = 98
+
+// hireling is a type of mercenary, the act and difficulty it was hired in
+// decides its skills, and the act its experience per level.
+type hireling struct {
+	class       string
+	act         int
+	difficulty  string
+	variant     string
+	aura        string
+	expPerLevel uint64
+}
+
+// hirelings are the types of mercenaries in the order of their ids, the Desert
+// Mercenaries are the only ones with an aura which depends on the difficulty.
+var hirelings = []hireling{
+	{MercenaryRogueScout, 0, DifficultyNormal, "Fire Arrow", "", 100},
+	{MercenaryRogueScout, 0, DifficultyNormal, "Cold Arrow", "", 100},
+	{MercenaryRogueScout, 0, DifficultyNightmare, "Fire Arrow", "", 100},
+	{MercenaryRogueScout, 0, DifficultyNightmare, "Cold Arrow", "", 100},
+	{MercenaryRogueScout, 0, DifficultyHell, "Fire Arrow", "", 100},
+	{MercenaryRogueScout, 0, DifficultyHell, "Cold Arrow", "", 100},
+	{MercenaryDesertMercenary, 1, DifficultyNormal, "Combat", "Prayer", 110},
+	{MercenaryDesertMercenary, 1, DifficultyNormal, "Defensive", "Defiance", 110},
+	{MercenaryDesertMercenary, 1, DifficultyNormal, "Offensive", "Blessed Aim", 110},
+	{MercenaryDesertMercenary, 1, DifficultyNightmare, "Combat", "Thorns", 110},
+	{MercenaryDesertMercenary, 1, DifficultyNightmare, "Defensive", "Holy Freeze", 110},
+	{MercenaryDesertMercenary, 1, DifficultyNightmare, "Offensive", "Might", 110},
+	{MercenaryDesertMercenary, 1, DifficultyHell, "Combat", "Prayer", 110},
+	{MercenaryDesertMercenary, 1, DifficultyHell, "Defensive", "Defiance", 110},
+	{MercenaryDesertMercenary, 1, DifficultyHell, "Offensive", "Blessed Aim", 110},
+	{MercenaryIronWolf, 2, DifficultyNormal, "Fire", "", 110},
+	{MercenaryIronWolf, 2, DifficultyNormal, "Cold", "", 110},
+	{MercenaryIronWolf, 2, DifficultyNormal, "Lightning", "", 110},
+	{MercenaryIronWolf, 2, DifficultyNightmare, "Fire", "", 110},
+	{MercenaryIronWolf, 2, DifficultyNightmare, "Cold", "", 110},
+	{MercenaryIronWolf, 2, DifficultyNightmare, "Lightning", "", 110},
+	{MercenaryIronWolf, 2, DifficultyHell, "Fire", "", 110},
+	{MercenaryIronWolf, 2, DifficultyHell, "Cold", "", 110},
+	{MercenaryIronWolf, 2, DifficultyHell, "Lightning", "", 110},
+	{MercenaryBarbarian, 4, DifficultyNormal, "", "", 120},
+	{MercenaryBarbarian, 4, DifficultyNormal, "", "", 120},
+	{MercenaryBarbarian, 4, DifficultyNightmare, "", "", 120},
+	{MercenaryBarbarian, 4, DifficultyNightmare, "", "", 120},
+	{MercenaryBarbarian, 4, DifficultyHell, "", "", 120},
+	{MercenaryBarbarian, 4, DifficultyHell, "", "", 120},
+}
+
+// mercenaryNames are the names of the mercenaries of an act in the order of
+// their name ids. The names of the Barbarians aren't in the table, so they're
+// named by their class and number like the mercenaries of mods.
+var mercenaryNames = map[string][]string{
+	MercenaryRogueScout: {
+		"Aliza", "Ampliza", "Annor", "Abhaya", "Elly", "Paige", "Basanti", "Blaise", "Kyoko",
+		"Klaudia", "Kundri", "Kyle", "Visala", "Elexa", "Floria", "Fiona", "Gwinni", "Gaile",
+		"Hannah", "Heather", "Iantha", "Diane", "Isolde", "Divo", "Ithera", "Itonya", "Liene",
+		"Maeko", "Mahala", "Liaza", "Meghan", "Olena", "Oriana", "Ryann", "Rozene", "Raissa",
+		"Sharyn", "Shikha", "Debi", "Tylena", "Wendy",
+	},
+	MercenaryDesertMercenary: {
+		"Hazade", "Alhizeer", "Azrael", "Ahsab", "Chalan", "Haseen", "Razan", "Emilio", "Pratham",
+		"Fazel", "Jemali", "Kasim", "Gulzar", "Mizan", "Leharas", "Durga", "Neeraj", "Ilzan",
+		"Zanarhi", "Waheed", "Vikhyat",
+	},
+	MercenaryIronWolf: {
+		"Jelani", "Barani", "Jabari", "Devak", "Raldin", "Telash", "Ajheed", "Narphet", "Khaleel",
+		"Phaet", "Geshef", "Vanji", "Haphet", "Thadar", "Yatiraj", "Rhadge", "Yashied", "Jarulf",
+		"Flux", "Scorch",
+	},
+}
+
+// Mercenary is the hired mercenary of a character and the items it has equipped.
+type Mercenary struct {
+	Character  string     `json:"character"`
+	ID         string     `json:"id"`
+	Type       uint16     `json:"type"`
+	Class      string     `json:"class"`
+	Variant    string     `json:"variant,omitempty"`
+	Act        string     `json:"act"`
+	Difficulty string     `json:"difficulty"`
+	NameID     uint16     `json:"name_id"`
+	Name       string     `json:"name"`
+	Aura       string     `json:"aura,omitempty"`
+	Experience uint32     `json:"experience"`
+	Level      uint64     `json:"level"`
+	Dead       bool       `json:"dead"`
+	Items      []d2s.Item `json:"items"`
+}
+
+// CharacterMercenary decodes the mercenary of the character, it returns nil
+// when the character hasn't hired a mercenary.
+func CharacterMercenary(character *d2s.Character) *Mercenary {
+	header := character.Header
+	if header.MercID == 0 {
+		return nil
+	}
+
+	mercenary := &Mercenary{
+		ID:         fmt.Sprintf("%x", header.MercID),
+		Type:       header.MercType,
+		NameID:     header.MercNameID,
+		Experience: header.MercExp,
+		Dead:       header.DeadMerc != 0,
+		Items:      character.MercItems,
+	}
+
+	if mercenary.Items == nil {
+		mercenary.Items = []d2s.Item{}
+	}
+
+	// Mods can add types of mercenaries we know nothing about.
+	if int(header.MercType) >= len(hirelings) {
+		mercenary.Name = mercenaryName("", header.MercNameID)
+		return mercenary
+	}
+
+	h := hirelings[header.MercType]
+	mercenary.Class = h.class
+	mercenary.Variant = h.variant
+	mercenary.Act = actNames[h.act]
+	mercenary.Difficulty = h.difficulty
+	mercenary.Aura = h.aura
+	mercenary.Level = mercenaryLevel(uint64(header.MercExp), h.expPerLevel)
+	mercenary.Name = mercenaryName(h.class, header.MercNameID)
+
+	return mercenary
+}
+
+// mercenaryName returns the name of the mercenary with the name id, mercenaries
+// without a name are named by their class and number.
+func mercenaryName(class string, id uint16) string {
+	if names := mercenaryNames[class]; int(id) < len(names) {
+		return names[id]
+	}
+
+	if class == "" {
+		class = "Mercenary"
+	}
+
+	return fmt.Sprintf("%s #%d", class, id+1)
+}
+
+// mercenaryLevel returns the level of a mercenary with the experience, a
+// mercenary needs the experience per level of its act times level² × (level+1)
+// to reach a level.
+func mercenaryLevel(exp uint64, expPerLevel uint64) uint64 {
+	level := uint64(1)
+	for level < maxMercenaryLevel {
+		next := level + 1
+		if expPerLevel*next*next*(next+1) > exp {
+			break
+		}
+		level = next
+	}
+
+	return level
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxLevel is the highest level a character can reach.
+const MaxLevel = 99
+
+// levelExperience is the experience required to reach each level, indexed by level.
+var levelExperience = [MaxLevel + 1]uint64{
+	0, 0, 500, 1500, 3750, 7875, 14175, 22680, 32886, 44396,
+	57715, 72144, 90180, 112725, 140906, 176132, 220165, 275207, 344008, 430010,
+	537513, 671891, 839864, 1049830, 1312287, 1640359, 2050449, 2563061, 3203826, 3902260,
+	4663553, 5493363, 6397855, 7383752, 8458379, 9629723, 10906488, 12298162, 13815086, 15468534,
+	17270791, 19235252, 21376515, 23710491, 26254525, 29027522, 32050088, 35344686, 38935798, 42850109,
+	47116709, 51767302, 56836449, 62361819, 68384473, 74949165, 82104680, 89904191, 98405658, 107672256,
+	117772849, 128782495, 140783010, 153863570, 168121381, 183662396, 200602101, 219066380, 239192444, 261129853,
+	285041630, 311105466, 339515048, 370481492, 404234916, 441026148, 481128591, 524840254, 572485967, 624419793,
+	681027665, 742730244, 809986056, 883294891, 963201521, 1050299747, 1145236814, 1248718217, 1361512946, 1484459201,
+	1618470619, 1764543065, 1923762030, 2097310703, 2286478756, 2492671933, 2717422497, 2962400612, 3229426756, 3520485254,
+}
+
+// LevelExperience returns the experience required to reach the level.
+func LevelExperience(level uint64) uint64 {
+	if level > MaxLevel {
+		level = MaxLevel
+	}
+
+	return levelExperience[level]
+}
+
+// ProgressPoint is the level and experience of a character at the time it was parsed.
+type ProgressPoint struct {
+	ParsedAt   time.Time `json:"parsed_at" bson:"parsed_at"`
+	Level      uint64    `json:"level"`
+	Experience uint64    `json:"experience"`
+}
+
+// Progress describes how fast a character is gaining experience.
+type Progress struct {
+	Character  string           `json:"character"`
+	Level      uint64           `json:"level"`
+	Experience uint64           `json:"experience"`
+	LastParsed time.Time        `json:"last_parsed"`
+	Rates      []ExperienceRate `json:"rates"`
+	NextLevel  *LevelEstimate   `json:"next_level"`
+	MaxLevel   *LevelEstimate   `json:"max_level"`
+	Milestones []Milestone      `json:"milestones"`
+}
+
+// ExperienceRate is the experience gained per hour over a window of time.
+type ExperienceRate struct {
+	Window  string  `json:"window"`
+	PerHour float64 `json:"per_hour"`
+}
+
+// LevelEstimate is the experience remaining until a level and when it will be
+// reached at the current rate, the time is empty when no experience is being gained.
+type LevelEstimate struct {
+	Level     uint64     `json:"level"`
+	Remaining uint64     `json:"experience_remaining"`
+	Seconds   *int64     `json:"seconds"`
+	At        *time.Time `json:"at"`
+}
+
+// Milestone is when a character reached a level and how long it took since it
+// was first seen, both are empty when it hasn't been seen reaching it.
+type Milestone struct {
+	Level     uint64     `json:"level"`
+	ReachedAt *time.Time `json:"reached_at"`
+	Seconds   *int64     `json:"seconds"`
+}
+
+// ParseWindow parses a window of time, either a duration like "90m" or "6h",
+// or a number of days like "7d".
+func ParseWindow(value string) (time.Duration, error) {
+	var (
+		window time.Duration
+		err    error
+	)
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		window, err = time.ParseDuration(value)
+	}
+
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("invalid window %q: %w", value, ErrRequest)
+	}
+
+	return window, nil
+}
+
+// FormatWindow formats a window of time in the largest whole unit it can be expressed in.
+func FormatWindow(window time.Duration) string {
+	switch {
+	case window%(24*time.Hour) == 0:
+		return strconv.Itoa(int(window/(24*time.Hour))) + "d"
+	case window%time.Hour == 0:
+		return strconv.Itoa(int(window/time.Hour)) + "h"
+	case window%time.Minute == 0:
+		return strconv.Itoa(int(window/time.Minute)) + "m"
+	default:
+		return window.String()
+	}
+}
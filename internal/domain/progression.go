@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"github.com/nokka/d2s"
+)
+
+// Statuses of quest rewards, a reward is available once the quest requirement
+// is completed until it's collected.
+const (
+	RewardCollected   = "collected"
+	RewardAvailable   = "available"
+	RewardUnavailable = "unavailable"
+)
+
+// Bits of the quest flags, the first bits tell if the quest is completed, and if
+// its requirement is completed with the reward left to collect.
+const (
+	questCompleted            = 0
+	questRequirementCompleted = 1
+	// Set on The Search for Cain once the Cow King has been killed, which closes the Cow Level.
+	questCowKingKilled = 10
+)
+
+// waypointNames are the names of the waypoints of every act, in the order of
+// the waypoint flags.
+var waypointNames = [][]string{
+	{"Rogue Encampment", "Cold Plains", "Stony Field", "Dark Wood", "Black Marsh", "Outer Cloister", "Jail Level 1", "Inner Cloister", "Catacombs Level 2"},
+	{"Lut Gholein", "Sewers Level 2", "Dry Hills", "Halls of the Dead Level 2", "Far Oasis", "Lost City", "Palace Cellar Level 1", "Arcane Sanctuary", "Canyon of the Magi"},
+	{"Kurast Docks", "Spider Forest", "Great Marsh", "Flayer Jungle", "Lower Kurast", "Kurast Bazaar", "Upper Kurast", "Travincal", "Durance of Hate Level 2"},
+	{"The Pandemonium Fortress", "City of the Damned", "River of Flame"},
+	{"Harrogath", "Frigid Highlands", "Arreat Plateau", "Crystalline Passage", "Halls of Pain", "Glacial Trail", "Frozen Tundra", "The Ancients' Way", "Worldstone Keep Level 2"},
+}
+
+// waypointOffset is where the waypoint flags start in the waypoint data of a difficulty.
+const waypointOffset = 2
+
+// actNames are the names of the acts.
+var actNames = []string{"Act I", "Act II", "Act III", "Act IV", "Act V"}
+
+// Progression is how far a character has come in every difficulty.
+type Progression struct {
+	Character    string                  `json:"character"`
+	Difficulties []DifficultyProgression `json:"difficulties"`
+}
+
+// DifficultyProgression is how far a character has come in a difficulty, the
+// quests it has done, the waypoints it has and the rewards it has collected.
+type DifficultyProgression struct {
+	Difficulty string            `json:"difficulty"`
+	Acts       []ActProgression  `json:"acts"`
+	CowLevel   CowLevel          `json:"cow_level"`
+	Rewards    []QuestReward     `json:"rewards"`
+	Waypoints  WaypointsUnlocked `json:"waypoints"`
+}
+
+// ActProgression is the quests of an act and how many of them are done.
+type ActProgression struct {
+	Act       string          `json:"act"`
+	Completed int             `json:"completed"`
+	Total     int             `json:"total"`
+	Quests    []QuestProgress `json:"quests"`
+}
+
+// QuestProgress tells if a quest is done, a quest with its requirement completed
+// has a reward left to collect.
+type QuestProgress struct {
+	Name                 string `json:"name"`
+	Completed            bool   `json:"completed"`
+	RequirementCompleted bool   `json:"requirement_completed"`
+}
+
+// WaypointsUnlocked are the waypoints a character has unlocked in a difficulty.
+type WaypointsUnlocked struct {
+	Unlocked int      `json:"unlocked"`
+	Total    int      `json:"total"`
+	Names    []string `json:"names"`
+}
+
+// CowLevel tells if the Cow Level can be entered, it opens by completing the
+// last quest of the game and closes for good once the Cow King is killed.
+type CowLevel struct {
+	Open          bool `json:"open"`
+	CowKingKilled bool `json:"cow_king_killed"`
+}
+
+// QuestReward is a reward of a quest and whether it's been collected.
+type QuestReward struct {
+	Quest  string `json:"quest"`
+	Reward string `json:"reward"`
+	Status string `json:"status"`
+}
+
+// questFlags are the flags of a quest, the quest types of d2s are unexported.
+type questFlags [2]byte
+
+func (q questFlags) has(bit uint) bool {
+	return q[bit/8]>>(bit%8)&1 > 0
+}
+
+// status returns the status of the reward of the quest.
+func (q questFlags) status() string {
+	switch {
+	case q.has(questCompleted):
+		return RewardCollected
+	case q.has(questRequirementCompleted):
+		return RewardAvailable
+	}
+
+	return RewardUnavailable
+}
+
+type namedQuest struct {
+	name  string
+	flags questFlags
+}
+
+type questReward struct {
+	quest  string
+	reward string
+	flags  questFlags
+}
+
+// CharacterProgression decodes the quest and waypoint flags of every difficulty of the character.
+func CharacterProgression(character *d2s.Character) *Progression {
+	header := character.Header
+	expansion := header.Status.Readable().Expansion
+
+	progression := &Progression{
+		Difficulties: make([]DifficultyProgression, 0, 3),
+	}
+
+	for _, difficulty := range []string{DifficultyNormal, DifficultyNightmare, DifficultyHell} {
+		q, waypoints := header.QuestsNormal, header.WaypointsNormal
+		switch difficulty {
+		case DifficultyNightmare:
+			q, waypoints = header.QuestsNm, header.WaypointsNm
+		case DifficultyHell:
+			q, waypoints = header.QuestsHell, header.WaypointsHell
+		}
+
+		acts := [][]namedQuest{
+			{
+				{"Den of Evil", questFlags(q.ActI.DenOfEvil)},
+				{"Sisters' Burial Grounds", questFlags(q.ActI.SistersBurialGrounds)},
+				{"Tools of the Trade", questFlags(q.ActI.ToolsOfTheTrade)},
+				{"The Search for Cain", questFlags(q.ActI.TheSearchForCain)},
+				{"The Forgotten Tower", questFlags(q.ActI.TheForgottenTower)},
+				{"Sisters to the Slaughter", questFlags(q.ActI.SistersToTheSlaughter)},
+			},
+			{
+				{"Radament's Lair", questFlags(q.ActII.RadamentsLair)},
+				{"The Horadric Staff", questFlags(q.ActII.TheHoradricStaff)},
+				{"Tainted Sun", questFlags(q.ActII.TaintedSun)},
+				{"Arcane Sanctuary", questFlags(q.ActII.ArcaneSanctuary)},
+				{"The Summoner", questFlags(q.ActII.TheSummoner)},
+				{"The Seven Tombs", questFlags(q.ActII.TheSevenTombs)},
+			},
+			{
+				{"Lam Esen's Tome", questFlags(q.ActIII.LamEsensTome)},
+				{"Khalim's Will", questFlags(q.ActIII.KhalimsWill)},
+				{"Blade of the Old Religion", questFlags(q.ActIII.BladeOfTheOldReligion)},
+				{"The Golden Bird", questFlags(q.ActIII.TheGoldenBird)},
+				{"The Blackened Temple", questFlags(q.ActIII.TheBlackenedTemple)},
+				{"The Guardian", questFlags(q.ActIII.TheGuardian)},
+			},
+			{
+				{"The Fallen Angel", questFlags(q.ActIV.TheFallenAngel)},
+				{"Terror's End", questFlags(q.ActIV.TerrorsEnd)},
+				{"Hell's Forge", questFlags(q.ActIV.HellForge)},
+			},
+		}
+
+		// The quests of act V are only part of the expansion.
+		if expansion {
+			acts = append(acts, []namedQuest{
+				{"Siege on Harrogath", questFlags(q.ActV.SiegeOnHarrogath)},
+				{"Rescue on Mount Arreat", questFlags(q.ActV.RescueOnMountArreat)},
+				{"Prison of Ice", questFlags(q.ActV.PrisonOfIce)},
+				{"Betrayal of Harrogath", questFlags(q.ActV.BetrayalOfHarrogath)},
+				{"Rite of Passage", questFlags(q.ActV.RiteOfPassage)},
+				{"Eve of Destruction", questFlags(q.ActV.EveOfDestruction)},
+			})
+		}
+
+		d := DifficultyProgression{
+			Difficulty: difficulty,
+			Acts:       make([]ActProgression, 0, len(acts)),
+			Waypoints:  unlockedWaypoints(waypoints, len(acts)),
+		}
+
+		for i, quests := range acts {
+			act := ActProgression{
+				Act:    actNames[i],
+				Total:  len(quests),
+				Quests: make([]QuestProgress, 0, len(quests)),
+			}
+
+			for _, quest := range quests {
+				completed := quest.flags.has(questCompleted)
+				if completed {
+					act.Completed++
+				}
+
+				act.Quests = append(act.Quests, QuestProgress{
+					Name:                 quest.name,
+					Completed:            completed,
+					RequirementCompleted: quest.flags.has(questRequirementCompleted),
+				})
+			}
+
+			d.Acts = append(d.Acts, act)
+		}
+
+		// The Cow Level opens once the last boss of the game is dead.
+		last := questFlags(q.ActIV.TerrorsEnd)
+		if expansion {
+			last = questFlags(q.ActV.EveOfDestruction)
+		}
+		killed := questFlags(q.ActI.TheSearchForCain).has(questCowKingKilled)
+		d.CowLevel = CowLevel{
+			Open:          last.has(questCompleted) && !killed,
+			CowKingKilled: killed,
+		}
+
+		rewards := []questReward{
+			{"Den of Evil", "1 skill point", questFlags(q.ActI.DenOfEvil)},
+			{"Tools of the Trade", "Imbue an item", questFlags(q.ActI.ToolsOfTheTrade)},
+			{"Radament's Lair", "1 skill point", questFlags(q.ActII.RadamentsLair)},
+			{"Lam Esen's Tome", "5 stat points", questFlags(q.ActIII.LamEsensTome)},
+			{"The Fallen Angel", "2 skill points", questFlags(q.ActIV.TheFallenAngel)},
+		}
+		if expansion {
+			rewards = append(rewards, []questReward{
+				{"Siege on Harrogath", "Socket an item", questFlags(q.ActV.SiegeOnHarrogath)},
+				{"Betrayal of Harrogath", "Personalize an item", questFlags(q.ActV.BetrayalOfHarrogath)},
+			}...)
+		}
+
+		d.Rewards = make([]QuestReward, 0, len(rewards))
+		for _, r := range rewards {
+			d.Rewards = append(d.Rewards, QuestReward{Quest: r.quest, Reward: r.reward, Status: r.flags.status()})
+		}
+
+		progression.Difficulties = append(progression.Difficulties, d)
+	}
+
+	return progression
+}
+
+// unlockedWaypoints returns the waypoints unlocked in the acts, the waypoints
+// are flagged in the order of the acts.
+func unlockedWaypoints(data [24]byte, acts int) WaypointsUnlocked {
+	waypoints := WaypointsUnlocked{Names: []string{}}
+
+	var bit uint
+	for i, names := range waypointNames {
+		for _, name := range names {
+			unlocked := data[waypointOffset+bit/8]>>(bit%8)&1 > 0
+			bit++
+
+			if i >= acts {
+				continue
+			}
+
+			waypoints.Total++
+			if unlocked {
+				waypoints.Unlocked++
+				waypoints.Names = append(waypoints.Names, name)
+			}
+		}
+	}
+
+	return waypoints
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Realm is a set of characters kept in its own d2s directory, such as the
+// softcore, hardcore or ladder characters of a server.
+type Realm struct {
+	Name string
+	Path string
+}
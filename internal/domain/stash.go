@@ -0,0 +1,20 @@
+package domain
+
+import "github.com/nokka/d2s"
+
+// Stash is a PlugY stash, either the personal stash of a character or the
+// stash shared by all characters of an account.
+type Stash struct {
+	Owner  string      `json:"owner"`
+	Shared bool        `json:"shared"`
+	Gold   uint32      `json:"gold"`
+	Pages  []StashPage `json:"pages"`
+}
+
+// StashPage is a page of a stash with the items stored on it.
+type StashPage struct {
+	Number int        `json:"number"`
+	Name   string     `json:"name"`
+	Flags  uint32     `json:"flags"`
+	Items  []d2s.Item `json:"items"`
+}
@@ -0,0 +1,91 @@
+package domain
+
+// EffectiveStats are the stats of a character with the attributes of its
+// equipped items, charms, socketed items and active set bonuses added up.
+type EffectiveStats struct {
+	Character            string                 `json:"character"`
+	Class                string                 `json:"class"`
+	Level                uint64                 `json:"level"`
+	Attributes           EffectiveAttributes    `json:"attributes"`
+	Life                 int64                  `json:"life"`
+	Mana                 int64                  `json:"mana"`
+	AllSkills            int64                  `json:"all_skills"`
+	FasterCastRate       int64                  `json:"faster_cast_rate"`
+	FasterHitRecovery    int64                  `json:"faster_hit_recovery"`
+	FasterBlockRate      int64                  `json:"faster_block_rate"`
+	IncreasedAttackSpeed int64                  `json:"increased_attack_speed"`
+	FasterRunWalk        int64                  `json:"faster_run_walk"`
+	MagicFind            int64                  `json:"magic_find"`
+	GoldFind             int64                  `json:"gold_find"`
+	LifeStolen           int64                  `json:"life_stolen"`
+	ManaStolen           int64                  `json:"mana_stolen"`
+	CrushingBlow         int64                  `json:"crushing_blow"`
+	DeadlyStrike         int64                  `json:"deadly_strike"`
+	OpenWounds           int64                  `json:"open_wounds"`
+	DamageReduced        int64                  `json:"damage_reduced"`
+	MagicResist          int64                  `json:"magic_resist"`
+	Resistances          map[string]Resistances `json:"resistances"`
+	Sources              []string               `json:"sources"`
+
+	// UnknownSetBonuses are the sets worn whose own bonuses aren't known yet,
+	// the stats are missing those bonuses.
+	UnknownSetBonuses []string `json:"unknown_set_bonuses"`
+}
+
+// EffectiveAttributes are the attributes of a character with the attributes of its items added.
+type EffectiveAttributes struct {
+	Strength  int64 `json:"strength"`
+	Dexterity int64 `json:"dexterity"`
+	Vitality  int64 `json:"vitality"`
+	Energy    int64 `json:"energy"`
+}
+
+// Resistances are the elemental resistances of a character in a difficulty.
+type Resistances struct {
+	Fire      Resistance `json:"fire"`
+	Cold      Resistance `json:"cold"`
+	Lightning Resistance `json:"lightning"`
+	Poison    Resistance `json:"poison"`
+}
+
+// Resistance is a resistance capped at its max, along with what it would be without the cap.
+type Resistance struct {
+	Value    int64 `json:"value"`
+	Uncapped int64 `json:"uncapped"`
+	Max      int64 `json:"max"`
+}
+
+// Breakpoints are the frame tiers of the speed stats of a character, every
+// tier takes a frame less to perform the action.
+type Breakpoints struct {
+	Character            string       `json:"character"`
+	Class                string       `json:"class"`
+	Weapon               string       `json:"weapon"`
+	FasterCastRate       []Breakpoint `json:"faster_cast_rate"`
+	FasterHitRecovery    []Breakpoint `json:"faster_hit_recovery"`
+	FasterBlockRate      []Breakpoint `json:"faster_block_rate"`
+	IncreasedAttackSpeed []Breakpoint `json:"increased_attack_speed"`
+}
+
+// Breakpoint is the tier a stat of a character is at, some classes have
+// separate tiers for their shapeshifted forms and certain skills.
+type Breakpoint struct {
+	Form   string           `json:"form"`
+	Value  int64            `json:"value"`
+	Frames int64            `json:"frames"`
+	Next   *NextBreakpoint  `json:"next"`
+	Tiers  []BreakpointTier `json:"tiers"`
+}
+
+// BreakpointTier is the least of a stat needed to perform the action in the frames.
+type BreakpointTier struct {
+	Value  int64 `json:"value"`
+	Frames int64 `json:"frames"`
+}
+
+// NextBreakpoint is the next tier of a stat and how much more of the stat it takes to reach it,
+// it's nil when the stat is at the last tier.
+type NextBreakpoint struct {
+	BreakpointTier
+	Needed int64 `json:"needed"`
+}
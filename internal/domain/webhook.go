@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// Webhook payload formats.
+const (
+	WebhookFormatJSON    = "json"
+	WebhookFormatDiscord = "discord"
+)
+
+// WebhookTarget is an endpoint events are pushed to, only events of the listed
+// types are pushed when there are any.
+type WebhookTarget struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Format string   `json:"format"`
+}
+
+// Accepts tells if events of the type should be pushed to the target.
+func (t WebhookTarget) Accepts(eventType string) bool {
+	if len(t.Events) == 0 {
+		return true
+	}
+
+	for _, e := range t.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is an event waiting in the outbox to be pushed to a target, the
+// payload is encoded when the event is emitted so it's signed and sent as is.
+type Delivery struct {
+	ID          string    `json:"id"`
+	Target      string    `json:"target"`
+	EventID     string    `json:"event_id" bson:"event_id"`
+	EventType   string    `json:"event_type" bson:"event_type"`
+	Payload     []byte    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt" bson:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Failed      bool      `json:"failed"`
+}
@@ -0,0 +1,127 @@
+package graveyard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+//go:generate moq -out ./service_mocks.go . graveyardRepository
+
+// Default and max number of deaths returned in a page.
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// classes maps the lowercase class names accepted in queries to the names classes are stored with.
+var classes = map[string]string{
+	"amazon":      "Amazon",
+	"sorceress":   "Sorceress",
+	"necromancer": "Necromancer",
+	"paladin":     "Paladin",
+	"barbarian":   "Barbarian",
+	"druid":       "Druid",
+	"assassin":    "Assassin",
+}
+
+// difficulties are the names of the difficulties in the order the header stores them.
+var difficulties = []string{"Normal", "Nightmare", "Hell"}
+
+// graveyardRepository is the interface representation of the data layer
+// the service depend on.
+type graveyardRepository interface {
+	Store(ctx context.Context, death *domain.Death) error
+	List(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error)
+}
+
+// Service keeps a record of every hardcore character that died.
+type Service struct {
+	repository graveyardRepository
+}
+
+// CharacterParsed records a death when a hardcore character was alive the last
+// time it was parsed and is dead now.
+func (s Service) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if previous == nil || previous.Dead() || !current.Dead() {
+		return
+	}
+
+	if err := s.repository.Store(ctx, newDeath(current)); err != nil {
+		log.Printf("failed to record death of character %s: %v", current.ID, err)
+	}
+}
+
+// List returns a page of deaths, most recent first.
+func (s Service) List(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error) {
+	if query.Class != "" {
+		class, ok := classes[strings.ToLower(query.Class)]
+		if !ok {
+			return nil, fmt.Errorf("unknown class %s: %w", query.Class, domain.ErrRequest)
+		}
+		query.Class = class
+	}
+
+	if query.MaxLevel > 0 && query.MaxLevel < query.MinLevel {
+		return nil, fmt.Errorf("max level is below min level: %w", domain.ErrRequest)
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultLimit
+	case query.Limit > maxLimit:
+		query.Limit = maxLimit
+	}
+
+	return s.repository.List(ctx, query)
+}
+
+// newDeath creates the death record of the character with the items it had equipped.
+func newDeath(character *domain.Character) *domain.Death {
+	death := &domain.Death{
+		Character:  character.ID,
+		Realm:      character.Realm,
+		Class:      character.D2s.Header.Class.String(),
+		Level:      character.D2s.Attributes.Level,
+		Experience: character.D2s.Attributes.Experience,
+		Area:       area(character.D2s.Header),
+		DiedAt:     character.LastParsed,
+		Items:      []d2s.Item{},
+	}
+
+	for _, item := range character.D2s.Items {
+		if domain.ItemLocation(item) == domain.LocationEquipped {
+			death.Items = append(death.Items, item)
+		}
+	}
+
+	return death
+}
+
+// area describes where the character was when it was last saved. The binary
+// only keeps the act of the active difficulty, the highest bit of a difficulty
+// marks it as active and the lowest bits hold the act.
+func area(header d2s.Header) string {
+	for i, difficulty := range []byte{
+		header.CurrentDifficulty.Normal,
+		header.CurrentDifficulty.Nightmare,
+		header.CurrentDifficulty.Hell,
+	} {
+		if difficulty&0x80 != 0 {
+			return fmt.Sprintf("%s Act %d", difficulties[i], difficulty&0x07+1)
+		}
+	}
+
+	return ""
+}
+
+// NewService constructs a new graveyard service with all the dependencies.
+func NewService(repository graveyardRepository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
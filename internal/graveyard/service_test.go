@@ -0,0 +1,160 @@
+package graveyard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// Header status bits of a hardcore character, and of one that has died.
+const (
+	statusHardcore = 1 << 2
+	statusDied     = 1 << 3
+)
+
+func TestCharacterParsed(t *testing.T) {
+	hardcore := func(status byte) *domain.Character {
+		c := &domain.Character{
+			ID:         "nokka",
+			LastParsed: time.Now(),
+			D2s: &d2s.Character{
+				Attributes: d2s.Attributes{Level: 42, Experience: 1337},
+				Items: []d2s.Item{
+					{Type: "helm", LocationID: 1},
+					{Type: "potion", LocationID: 2},
+				},
+			},
+		}
+		c.D2s.Header.Status = statusHardcore
+		if status&statusDied != 0 {
+			c.D2s.Header.Status |= statusDied
+		}
+		c.D2s.Header.CurrentDifficulty.Hell = 0x80 | 4
+		return c
+	}
+
+	softcore := hardcore(statusDied)
+	softcore.D2s.Header.Status = statusDied
+
+	tests := []struct {
+		name       string
+		previous   *domain.Character
+		current    *domain.Character
+		storeCalls int
+	}{
+		{
+			name:       "hardcore character died",
+			previous:   hardcore(0),
+			current:    hardcore(statusDied),
+			storeCalls: 1,
+		},
+		{
+			name:     "hardcore character is still alive",
+			previous: hardcore(0),
+			current:  hardcore(0),
+		},
+		{
+			name:     "hardcore character was already dead",
+			previous: hardcore(statusDied),
+			current:  hardcore(statusDied),
+		},
+		{
+			name:    "dead character parsed for the first time",
+			current: hardcore(statusDied),
+		},
+		{
+			name:     "softcore character died",
+			previous: hardcore(0),
+			current:  softcore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &graveyardRepositoryMock{
+				StoreFunc: func(ctx context.Context, death *domain.Death) error {
+					return nil
+				},
+			}
+
+			s := NewService(repository)
+			s.CharacterParsed(context.TODO(), tt.previous, tt.current)
+
+			calls := repository.StoreCalls()
+			if len(calls) != tt.storeCalls {
+				t.Fatalf("expected graveyardRepository.Store() to be called exactly %d times but was called %d times", tt.storeCalls, len(calls))
+			}
+
+			if tt.storeCalls == 0 {
+				return
+			}
+
+			death := calls[0].Death
+			if death.Level != 42 || death.Experience != 1337 || death.Area != "Hell Act 5" {
+				t.Errorf("unexpected death recorded, got = %+v", death)
+			}
+
+			if len(death.Items) != 1 || death.Items[0].Type != "helm" {
+				t.Errorf("expected only the equipped items to be recorded, got = %v", death.Items)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         domain.GraveyardQuery
+		expectedQuery domain.GraveyardQuery
+		expectedError error
+	}{
+		{
+			name:          "defaults are applied",
+			expectedQuery: domain.GraveyardQuery{Limit: defaultLimit},
+		},
+		{
+			name:          "class is matched regardless of case",
+			query:         domain.GraveyardQuery{Class: "sorceress", MinLevel: 80, MaxLevel: 90, Limit: 500},
+			expectedQuery: domain.GraveyardQuery{Class: "Sorceress", MinLevel: 80, MaxLevel: 90, Limit: maxLimit},
+		},
+		{
+			name:          "unknown class",
+			query:         domain.GraveyardQuery{Class: "warlock"},
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "inverted level range",
+			query:         domain.GraveyardQuery{MinLevel: 90, MaxLevel: 80},
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &graveyardRepositoryMock{
+				ListFunc: func(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error) {
+					return &domain.GraveyardPage{}, nil
+				},
+			}
+
+			s := NewService(repository)
+
+			_, err := s.List(context.TODO(), tt.query)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				return
+			}
+
+			if got := repository.ListCalls()[0].Query; got != tt.expectedQuery {
+				t.Errorf("expected query %+v, got = %+v", tt.expectedQuery, got)
+			}
+		})
+	}
+}
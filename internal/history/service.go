@@ -0,0 +1,180 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/diff"
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+//go:generate moq -out ./service_mocks.go . historyRepository
+
+// Default and max number of snapshots returned in a page.
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// historyRepository is the interface representation of the data layer
+// the service depend on.
+type historyRepository interface {
+	Latest(ctx context.Context, character string) (*domain.Snapshot, error)
+	Store(ctx context.Context, snapshot *domain.Snapshot) error
+	List(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error)
+	Nearest(ctx context.Context, character string, at time.Time) (*domain.Snapshot, error)
+}
+
+// Service keeps track of how characters evolve between parses.
+type Service struct {
+	repository historyRepository
+}
+
+// CharacterParsed records a snapshot every time a character has been parsed.
+func (s Service) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if err := s.Record(ctx, current); err != nil {
+		log.Printf("failed to record snapshot of character %s: %v", current.ID, err)
+	}
+}
+
+// Record will store a snapshot of the character, unless nothing we keep track of
+// has changed since the latest snapshot.
+func (s Service) Record(ctx context.Context, character *domain.Character) error {
+	if character == nil || character.D2s == nil {
+		return nil
+	}
+
+	snapshot, err := newSnapshot(character)
+	if err != nil {
+		return err
+	}
+
+	latest, err := s.repository.Latest(ctx, character.ID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	if latest != nil && latest.Hash == snapshot.Hash {
+		return nil
+	}
+
+	return s.repository.Store(ctx, snapshot)
+}
+
+// List returns a page of snapshots of the character, newest first.
+func (s Service) List(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error) {
+	if character == "" {
+		return nil, fmt.Errorf("character name is required: %w", domain.ErrRequest)
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, fmt.Errorf("to is before from: %w", domain.ErrRequest)
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultLimit
+	case query.Limit > maxLimit:
+		query.Limit = maxLimit
+	}
+
+	return s.repository.List(ctx, character, query)
+}
+
+// Diff returns the difference between the character as it looked at from and
+// as it looked at to, to defaults to now. When the character didn't exist yet
+// at one of the times its first snapshot is used.
+func (s Service) Diff(ctx context.Context, character string, from time.Time, to time.Time) (*domain.CharacterDiff, error) {
+	if character == "" {
+		return nil, fmt.Errorf("character name is required: %w", domain.ErrRequest)
+	}
+
+	if from.IsZero() {
+		return nil, fmt.Errorf("from is required: %w", domain.ErrRequest)
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	if to.Before(from) {
+		return nil, fmt.Errorf("to is before from: %w", domain.ErrRequest)
+	}
+
+	a, err := s.repository.Nearest(ctx, character, from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.repository.Nearest(ctx, character, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff.Compare(characterOf(a), characterOf(b)), nil
+}
+
+// characterOf turns the snapshot back into the character it was taken of.
+func characterOf(snapshot *domain.Snapshot) *domain.Character {
+	return &domain.Character{
+		ID:         snapshot.Character,
+		Realm:      snapshot.Realm,
+		LastParsed: snapshot.ParsedAt,
+		D2s: &d2s.Character{
+			Attributes: snapshot.Attributes,
+			Skills:     snapshot.Skills,
+			Items:      snapshot.Items,
+			MercItems:  snapshot.MercItems,
+		},
+	}
+}
+
+// newSnapshot creates a snapshot of the character, the hash is calculated on
+// the tracked content only so snapshots can be deduplicated.
+func newSnapshot(character *domain.Character) (*domain.Snapshot, error) {
+	snapshot := &domain.Snapshot{
+		Character:  character.ID,
+		Realm:      character.Realm,
+		ParsedAt:   character.LastParsed,
+		Level:      character.D2s.Attributes.Level,
+		Experience: character.D2s.Attributes.Experience,
+		Attributes: character.D2s.Attributes,
+		Skills:     character.D2s.Skills,
+		Items:      character.D2s.Items,
+		MercItems:  character.D2s.MercItems,
+	}
+
+	content, err := json.Marshal(struct {
+		Attributes interface{}
+		Skills     interface{}
+		Items      interface{}
+		MercItems  interface{}
+	}{
+		Attributes: snapshot.Attributes,
+		Skills:     snapshot.Skills,
+		Items:      snapshot.Items,
+		MercItems:  snapshot.MercItems,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash snapshot: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	snapshot.Hash = hex.EncodeToString(sum[:])
+
+	return snapshot, nil
+}
+
+// NewService constructs a new history service with all the dependencies.
+func NewService(repository historyRepository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
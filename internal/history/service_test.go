@@ -0,0 +1,308 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestRecord(t *testing.T) {
+	character := &domain.Character{
+		ID: "nokka",
+		D2s: &d2s.Character{
+			Attributes: d2s.Attributes{Level: 42, Experience: 1337},
+		},
+		LastParsed: time.Now(),
+	}
+
+	snapshot, err := newSnapshot(character)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		repository    *historyRepositoryMock
+		storeCalls    int
+		expectedError error
+	}{
+		{
+			name: "first snapshot is stored",
+			repository: &historyRepositoryMock{
+				LatestFunc: func(ctx context.Context, character string) (*domain.Snapshot, error) {
+					return nil, domain.ErrNotFound
+				},
+				StoreFunc: func(ctx context.Context, snapshot *domain.Snapshot) error {
+					return nil
+				},
+			},
+			storeCalls: 1,
+		},
+		{
+			name: "changed snapshot is stored",
+			repository: &historyRepositoryMock{
+				LatestFunc: func(ctx context.Context, character string) (*domain.Snapshot, error) {
+					return &domain.Snapshot{Hash: "c0ffee"}, nil
+				},
+				StoreFunc: func(ctx context.Context, snapshot *domain.Snapshot) error {
+					return nil
+				},
+			},
+			storeCalls: 1,
+		},
+		{
+			name: "unchanged snapshot is deduplicated",
+			repository: &historyRepositoryMock{
+				LatestFunc: func(ctx context.Context, character string) (*domain.Snapshot, error) {
+					return snapshot, nil
+				},
+			},
+			storeCalls: 0,
+		},
+		{
+			name: "temporary error getting latest snapshot",
+			repository: &historyRepositoryMock{
+				LatestFunc: func(ctx context.Context, character string) (*domain.Snapshot, error) {
+					return nil, domain.ErrTemporary
+				},
+			},
+			storeCalls:    0,
+			expectedError: domain.ErrTemporary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.repository)
+
+			err := s.Record(context.TODO(), character)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error to be = %v, got = %v", tt.expectedError, err)
+			}
+
+			if len(tt.repository.StoreCalls()) != tt.storeCalls {
+				t.Errorf("expected historyRepository.Store() to be called exactly %d times but was called %d times",
+					tt.storeCalls,
+					len(tt.repository.StoreCalls()),
+				)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		character     string
+		query         domain.HistoryQuery
+		expectedLimit int
+		listCalls     int
+		expectedError error
+	}{
+		{
+			name:          "default limit",
+			character:     "nokka",
+			expectedLimit: defaultLimit,
+			listCalls:     1,
+		},
+		{
+			name:          "limit is capped",
+			character:     "nokka",
+			query:         domain.HistoryQuery{Limit: 1000},
+			expectedLimit: maxLimit,
+			listCalls:     1,
+		},
+		{
+			name:          "invalid time range",
+			character:     "nokka",
+			query:         domain.HistoryQuery{From: now, To: now.Add(-time.Hour)},
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "missing character",
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &historyRepositoryMock{
+				ListFunc: func(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error) {
+					return &domain.HistoryPage{}, nil
+				},
+			}
+
+			s := NewService(repository)
+
+			_, err := s.List(context.TODO(), tt.character, tt.query)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error to be = %v, got = %v", tt.expectedError, err)
+			}
+
+			calls := repository.ListCalls()
+			if len(calls) != tt.listCalls {
+				t.Fatalf("expected historyRepository.List() to be called exactly %d times but was called %d times",
+					tt.listCalls,
+					len(calls),
+				)
+			}
+
+			if len(calls) > 0 && calls[0].Query.Limit != tt.expectedLimit {
+				t.Errorf("expected limit to be = %d, got = %d", tt.expectedLimit, calls[0].Query.Limit)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+
+	from := &domain.Snapshot{
+		Character: "nokka",
+		ParsedAt:  now.Add(-48 * time.Hour),
+		Attributes: d2s.Attributes{
+			Level: 41, Experience: 1000, Strength: 60, Vitality: 100,
+		},
+		Skills: []d2s.Skill{{ID: 36, Name: "Fire Bolt", Points: 5}},
+		Items: []d2s.Item{
+			{ID: 1, Type: "cap", TypeName: "Cap", LocationID: 1},
+			{ID: 2, Type: "rin", TypeName: "Ring", AltPositionID: 1},
+			{ID: 3, Type: "amu", TypeName: "Amulet", AltPositionID: 5},
+		},
+	}
+
+	to := &domain.Snapshot{
+		Character: "nokka",
+		ParsedAt:  now,
+		Attributes: d2s.Attributes{
+			Level: 42, Experience: 1500, Strength: 60, Vitality: 105,
+		},
+		Skills: []d2s.Skill{{ID: 36, Name: "Fire Bolt", Points: 6}},
+		Items: []d2s.Item{
+			{ID: 1, Type: "cap", TypeName: "Cap", LocationID: 1},
+			{ID: 2, Type: "rin", TypeName: "Ring", LocationID: 1},
+			{ID: 4, Type: "lgl", TypeName: "Leather Gloves", LocationID: 1},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		character     string
+		from          time.Time
+		to            time.Time
+		nearestCalls  int
+		expectedError error
+	}{
+		{
+			name:         "difference between two snapshots",
+			character:    "nokka",
+			from:         from.ParsedAt,
+			to:           to.ParsedAt,
+			nearestCalls: 2,
+		},
+		{
+			name:         "to defaults to now",
+			character:    "nokka",
+			from:         from.ParsedAt,
+			nearestCalls: 2,
+		},
+		{
+			name:          "missing character",
+			from:          from.ParsedAt,
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "missing from",
+			character:     "nokka",
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "to is before from",
+			character:     "nokka",
+			from:          to.ParsedAt,
+			to:            from.ParsedAt,
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &historyRepositoryMock{
+				NearestFunc: func(ctx context.Context, character string, at time.Time) (*domain.Snapshot, error) {
+					if at.Before(to.ParsedAt) {
+						return from, nil
+					}
+					return to, nil
+				},
+			}
+
+			s := NewService(repository)
+
+			got, err := s.Diff(context.TODO(), tt.character, tt.from, tt.to)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error to be = %v, got = %v", tt.expectedError, err)
+			}
+
+			if len(repository.NearestCalls()) != tt.nearestCalls {
+				t.Fatalf("expected historyRepository.Nearest() to be called exactly %d times but was called %d times",
+					tt.nearestCalls,
+					len(repository.NearestCalls()),
+				)
+			}
+
+			if tt.expectedError != nil {
+				return
+			}
+
+			if got.Level.Change != 1 || got.Experience.Change != 500 {
+				t.Errorf("expected 1 level and 500 experience gained, got = %+v and %+v", got.Level, got.Experience)
+			}
+
+			if len(got.Attributes) != 1 || got.Attributes["vitality"] != 5 {
+				t.Errorf("expected only vitality to change by 5, got = %v", got.Attributes)
+			}
+
+			if len(got.Skills) != 1 || got.Skills[0].Change != 1 {
+				t.Errorf("expected a point added to Fire Bolt, got = %+v", got.Skills)
+			}
+
+			items := got.Items
+			if len(items.Added) != 1 || items.Added[0].ID != 4 || items.Added[0].ToLocation != domain.LocationEquipped {
+				t.Errorf("expected the gloves to be added, got = %+v", items.Added)
+			}
+
+			if len(items.Removed) != 1 || items.Removed[0].ID != 3 || items.Removed[0].FromLocation != domain.LocationStash {
+				t.Errorf("expected the amulet to be removed, got = %+v", items.Removed)
+			}
+
+			expectedMove := domain.ItemChange{ID: 2, Type: "rin", Name: "Ring", FromLocation: domain.LocationInventory, ToLocation: domain.LocationEquipped}
+			if len(items.Moved) != 1 || items.Moved[0] != expectedMove {
+				t.Errorf("expected the ring to be equipped, got = %+v", items.Moved)
+			}
+		})
+	}
+}
+
+func TestDiffSnapshotNotFound(t *testing.T) {
+	repository := &historyRepositoryMock{
+		NearestFunc: func(ctx context.Context, character string, at time.Time) (*domain.Snapshot, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+
+	s := NewService(repository)
+
+	_, err := s.Diff(context.TODO(), "nokka", time.Now().Add(-time.Hour), time.Time{})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected error to be = %v, got = %v", domain.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// accountService represents the functionality we need to look at accounts.
+type accountService interface {
+	// Summary returns the account with a summary of each of its characters.
+	Summary(ctx context.Context, name string) (*domain.AccountSummary, error)
+}
+
+// accountHandler is used to get accounts and their characters.
+type accountHandler struct {
+	encoder        *encoder
+	accountService accountService
+}
+
+func (h accountHandler) Routes(router chi.Router) {
+	router.Get("/{account}", h.getAccount)
+}
+
+func (h accountHandler) getAccount(w http.ResponseWriter, r *http.Request) {
+	// Pass the request context in order to make use of cancellation for lower level work.
+	account, err := h.accountService.Summary(r.Context(), chi.URLParam(r, "account"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, struct {
+		Account *domain.AccountSummary `json:"account"`
+	}{
+		Account: account,
+	})
+}
+
+func newAccountHandler(encoder *encoder, accountService accountService) *accountHandler {
+	return &accountHandler{
+		encoder:        encoder,
+		accountService: accountService,
+	}
+}
@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// batchHandler is used to get several characters in one request.
+type batchHandler struct {
+	encoder          *encoder
+	characterService characterService
+}
+
+// batchRequest is the body posted to get several characters.
+type batchRequest struct {
+	Names []string `json:"names"`
+}
+
+// batchResult is the result of one of the characters in the batch.
+type batchResult struct {
+	Name      string            `json:"name"`
+	Character *domain.Character `json:"character,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Status    int               `json:"status"`
+}
+
+func (h batchHandler) Routes(router chi.Router) {
+	router.Post("/", h.postBatch)
+	router.Get("/", h.getBatch)
+}
+
+func (h batchHandler) postBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.encoder.Error(w, fmt.Errorf("invalid batch request: %w", domain.ErrRequest))
+		return
+	}
+
+	h.batch(w, r, req.Names)
+}
+
+func (h batchHandler) getBatch(w http.ResponseWriter, r *http.Request) {
+	h.batch(w, r, r.URL.Query()["name"])
+}
+
+func (h batchHandler) batch(w http.ResponseWriter, r *http.Request, names []string) {
+	// Pass the request context in order to make use of cancellation for lower level work.
+	results, err := h.characterService.ParseBatch(r.Context(), names)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	response := make([]batchResult, 0, len(results))
+
+	for _, result := range results {
+		res := batchResult{
+			Name:      result.Name,
+			Character: result.Character,
+			Status:    http.StatusOK,
+		}
+
+		err := result.Err
+		if err == nil && result.Character.DeletedAt != nil {
+			err = fmt.Errorf("character was deleted: %w", domain.ErrGone)
+			res.Character = nil
+		}
+
+		if err != nil {
+			res.Error = err.Error()
+			res.Status = statusCode(err)
+		}
+
+		response = append(response, res)
+	}
+
+	h.encoder.Response(w, struct {
+		Characters []batchResult `json:"characters"`
+	}{
+		Characters: response,
+	})
+}
+
+func newBatchHandler(encoder *encoder, characterService characterService) *batchHandler {
+	return &batchHandler{
+		encoder:          encoder,
+		characterService: characterService,
+	}
+}
@@ -0,0 +1,65 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestBatchHandler(t *testing.T) {
+	service := staticCharacterService{
+		"nokka":  &domain.Character{ID: "nokka"},
+		"wheelz": &domain.Character{ID: "wheelz"},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name string
+		req  *http.Request
+	}{
+		{"post names", httptest.NewRequest("POST", "/api/v1/characters:batch", strings.NewReader(`{"names":["nokka","missing","wheelz"]}`))},
+		{"repeated name params", httptest.NewRequest("GET", "/api/v1/characters:batch?name=nokka&name=missing&name=wheelz", nil)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, tt.req)
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("want status 200, got = %d", recorder.Code)
+			}
+
+			var resp struct {
+				Characters []batchResult `json:"characters"`
+			}
+
+			if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := []struct {
+				name   string
+				status int
+			}{
+				{"nokka", http.StatusOK},
+				{"missing", http.StatusNotFound},
+				{"wheelz", http.StatusOK},
+			}
+
+			if len(resp.Characters) != len(want) {
+				t.Fatalf("expected %d results, got = %d", len(want), len(resp.Characters))
+			}
+
+			for i, w := range want {
+				if got := resp.Characters[i]; got.Name != w.name || got.Status != w.status {
+					t.Errorf("expected result %d to be %s with status %d, got = %s with status %d", i, w.name, w.status, got.Name, got.Status)
+				}
+			}
+		})
+	}
+}
@@ -2,16 +2,32 @@ package httpserver
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2-armory-api/internal/stats"
+	"github.com/nokka/d2-armory-api/internal/tooltip"
+)
+
+// Sections of the character response that are only included when asked for with expand.
+const (
+	expandTooltips    = "tooltips"
+	expandBreakpoints = "breakpoints"
 )
 
 // characterService represents the functionality we need to perform our character requests.
 type characterService interface {
 	// Parse parses a character binary.
 	Parse(ctx context.Context, name string) (*domain.Character, error)
+
+	// ParseBatch parses several character binaries.
+	ParseBatch(ctx context.Context, names []string) ([]domain.CharacterResult, error)
 }
 
 // characterHandler is used to put parse characters.
@@ -27,6 +43,12 @@ func (h characterHandler) Routes(router chi.Router) {
 func (h characterHandler) parseCharacter(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 
+	expand, err := parseExpand(r, expandTooltips, expandBreakpoints)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
 	// Pass the request context in order to make use of cancellation for lower level work.
 	char, err := h.characterService.Parse(r.Context(), name)
 	if err != nil {
@@ -34,11 +56,110 @@ func (h characterHandler) parseCharacter(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	h.encoder.Response(w, struct {
-		Character *domain.Character `json:"character"`
+	// The last known version of a deleted character is only served when asked for.
+	includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+	if char.DeletedAt != nil && !includeDeleted {
+		h.encoder.Error(w, fmt.Errorf("character was deleted: %w", domain.ErrGone))
+		return
+	}
+
+	// The character is being refreshed in the background, let the client know
+	// how old the version we serve is.
+	if char.Stale {
+		w.Header().Set("X-Armory-Stale", "true")
+		w.Header().Set("Age", strconv.Itoa(int(time.Since(char.LastParsed).Seconds())))
+	}
+
+	// The binary failed to parse so the last version we stored is served.
+	if char.Degraded {
+		w.Header().Set("X-Armory-Degraded", "true")
+	}
+
+	// The hash of the binary identifies the content of the character, so clients
+	// that already have this version can be told nothing changed. The tag is weak
+	// since the response also holds when the character was parsed, and it holds
+	// the sections expanded since they're part of the response.
+	if char.Fingerprint.Hash != "" {
+		etag := characterETag(char.Fingerprint.Hash, expand)
+		w.Header().Set("ETag", etag)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	response := struct {
+		Character   *domain.Character    `json:"character"`
+		Tooltips    *domain.ItemTooltips `json:"tooltips,omitempty"`
+		Breakpoints *domain.Breakpoints  `json:"breakpoints,omitempty"`
 	}{
 		Character: char,
-	})
+	}
+
+	if expand[expandTooltips] && char.D2s != nil {
+		response.Tooltips = tooltip.ForCharacter(char.D2s)
+	}
+
+	if expand[expandBreakpoints] && char.D2s != nil {
+		response.Breakpoints = stats.Breakpoints(char.D2s)
+		response.Breakpoints.Character = char.ID
+	}
+
+	h.encoder.Response(w, response)
+}
+
+// parseExpand reads the comma separated sections to expand the response with,
+// only the allowed sections can be asked for.
+func parseExpand(r *http.Request, allowed ...string) (map[string]bool, error) {
+	expand := make(map[string]bool)
+
+	for _, value := range r.URL.Query()["expand"] {
+		for _, section := range strings.Split(value, ",") {
+			section = strings.TrimSpace(section)
+			if section == "" {
+				continue
+			}
+
+			if !slices.Contains(allowed, section) {
+				return nil, fmt.Errorf("unknown expand %s: %w", section, domain.ErrRequest)
+			}
+			expand[section] = true
+		}
+	}
+
+	return expand, nil
+}
+
+// characterETag returns the weak etag of a character parsed from the binary with
+// the hash, with the sections expanded.
+func characterETag(hash string, expand map[string]bool) string {
+	sections := make([]string, 0, len(expand))
+	for section := range expand {
+		sections = append(sections, section)
+	}
+
+	if len(sections) == 0 {
+		return fmt.Sprintf("W/%q", hash)
+	}
+	slices.Sort(sections)
+
+	return fmt.Sprintf("W/%q", hash+"-"+strings.Join(sections, "-"))
+}
+
+// etagMatches reports whether the If-None-Match header contains the given etag,
+// If-None-Match uses the weak comparison so the weak indicator is ignored.
+func etagMatches(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.TrimPrefix(candidate, "W/") == etag || candidate == "*" {
+			return true
+		}
+	}
+
+	return false
 }
 
 func newCharacterHandler(encoder *encoder, characterService characterService) *characterHandler {
@@ -0,0 +1,296 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// staticCharacterService serves characters from a map.
+type staticCharacterService map[string]*domain.Character
+
+func (s staticCharacterService) Parse(ctx context.Context, name string) (*domain.Character, error) {
+	c, ok := s[name]
+	if !ok {
+		return nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+	}
+
+	return c, nil
+}
+
+func (s staticCharacterService) ParseBatch(ctx context.Context, names []string) ([]domain.CharacterResult, error) {
+	results := make([]domain.CharacterResult, 0, len(names))
+
+	for _, name := range names {
+		c, err := s.Parse(ctx, name)
+		results = append(results, domain.CharacterResult{Name: name, Character: c, Err: err})
+	}
+
+	return results, nil
+}
+
+func TestCharacterHandlerETag(t *testing.T) {
+	service := staticCharacterService{
+		"nokka": &domain.Character{
+			ID:          "nokka",
+			Fingerprint: domain.Fingerprint{Hash: "b4d1c0de"},
+		},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name        string
+		query       string
+		ifNoneMatch string
+		want        int
+		wantETag    string
+	}{
+		{"no etag supplied", "", "", http.StatusOK, `W/"b4d1c0de"`},
+		{"stale etag supplied", "", `W/"c0ffee"`, http.StatusOK, `W/"b4d1c0de"`},
+		{"current etag supplied", "", `W/"b4d1c0de"`, http.StatusNotModified, `W/"b4d1c0de"`},
+		{"current etag supplied as strong", "", `"b4d1c0de"`, http.StatusNotModified, `W/"b4d1c0de"`},
+		{"current etag in list", "", `W/"c0ffee", W/"b4d1c0de"`, http.StatusNotModified, `W/"b4d1c0de"`},
+		{"etag of another expand", "&expand=tooltips", `W/"b4d1c0de"`, http.StatusOK, `W/"b4d1c0de-tooltips"`},
+		{"etag of the same expand", "&expand=tooltips,breakpoints", `W/"b4d1c0de-breakpoints-tooltips"`, http.StatusNotModified, `W/"b4d1c0de-breakpoints-tooltips"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			req := httptest.NewRequest("GET", "/api/v1/characters?name=nokka"+tt.query, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			srv.Handler().ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if got, want := recorder.Header().Get("ETag"), tt.wantETag; got != want {
+				t.Errorf(`recorder.Header().Get("ETag") = %q, want %q`, got, want)
+			}
+		})
+	}
+}
+
+func TestCharacterHandlerRealms(t *testing.T) {
+	softcore := staticCharacterService{
+		"nokka": &domain.Character{ID: "nokka", Realm: "softcore"},
+	}
+	hardcore := staticCharacterService{
+		"nokka": &domain.Character{ID: "nokka", Realm: "hardcore"},
+	}
+
+	srv := NewServer(":80", softcore, nil, nil, false, false,
+		WithRealm("softcore", Realm{CharacterService: softcore}),
+		WithRealm("hardcore", Realm{CharacterService: hardcore}),
+	)
+
+	for _, tt := range []struct {
+		name  string
+		path  string
+		want  int
+		realm string
+	}{
+		{"unscoped uses default realm", "/api/v1/characters?name=nokka", http.StatusOK, "softcore"},
+		{"default realm", "/api/v1/realms/softcore/characters?name=nokka", http.StatusOK, "softcore"},
+		{"other realm", "/api/v1/realms/hardcore/characters?name=nokka", http.StatusOK, "hardcore"},
+		{"unknown realm", "/api/v1/realms/ladder/characters?name=nokka", http.StatusNotFound, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if tt.realm == "" {
+				return
+			}
+
+			var body struct {
+				Character domain.Character `json:"character"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body.Character.Realm != tt.realm {
+				t.Errorf("want realm %q, got = %q", tt.realm, body.Character.Realm)
+			}
+		})
+	}
+}
+
+func TestCharacterHandlerDegraded(t *testing.T) {
+	service := staticCharacterService{
+		"nokka": &domain.Character{ID: "nokka", Degraded: true},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/characters?name=nokka", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("want status %d, got = %d", http.StatusOK, recorder.Code)
+	}
+
+	if got, want := recorder.Header().Get("X-Armory-Degraded"), "true"; got != want {
+		t.Errorf(`recorder.Header().Get("X-Armory-Degraded") = %q, want %q`, got, want)
+	}
+}
+
+func TestCharacterHandlerStale(t *testing.T) {
+	service := staticCharacterService{
+		"nokka": &domain.Character{
+			ID:         "nokka",
+			LastParsed: time.Now().Add(-90 * time.Second),
+			Stale:      true,
+		},
+		"wheelz": &domain.Character{
+			ID:         "wheelz",
+			LastParsed: time.Now(),
+		},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name      string
+		character string
+		wantStale string
+		wantAge   string
+	}{
+		{"stale character", "nokka", "true", "90"},
+		{"fresh character", "wheelz", "", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/characters?name="+tt.character, nil))
+
+			if got := recorder.Header().Get("X-Armory-Stale"); got != tt.wantStale {
+				t.Errorf(`recorder.Header().Get("X-Armory-Stale") = %q, want %q`, got, tt.wantStale)
+			}
+
+			if got := recorder.Header().Get("Age"); got != tt.wantAge {
+				t.Errorf(`recorder.Header().Get("Age") = %q, want %q`, got, tt.wantAge)
+			}
+		})
+	}
+}
+
+func TestCharacterHandlerDeleted(t *testing.T) {
+	deletedAt := time.Now()
+
+	service := staticCharacterService{
+		"nokka": &domain.Character{ID: "nokka", DeletedAt: &deletedAt},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want int
+	}{
+		{"deleted character", "/api/v1/characters?name=nokka", http.StatusGone},
+		{"last known version of deleted character", "/api/v1/characters?name=nokka&include_deleted=true", http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/characters:batch?name=nokka", nil))
+
+	var resp struct {
+		Characters []batchResult `json:"characters"`
+	}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Characters) != 1 || resp.Characters[0].Status != http.StatusGone || resp.Characters[0].Character != nil {
+		t.Errorf("expected the deleted character to be left out of the batch with status 410, got = %+v", resp.Characters)
+	}
+}
+
+func TestCharacterHandlerExpand(t *testing.T) {
+	service := staticCharacterService{
+		"nokka": &domain.Character{
+			ID: "nokka",
+			D2s: &d2s.Character{
+				Items: []d2s.Item{{TypeName: "Shako", DefenseRating: 98}},
+			},
+		},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name        string
+		path        string
+		want        int
+		tooltips    []string
+		breakpoints bool
+	}{
+		{"not expanded", "/api/v1/characters?name=nokka", http.StatusOK, nil, false},
+		{"tooltips", "/api/v1/characters?name=nokka&expand=tooltips", http.StatusOK, []string{"Shako", "Defense: 98"}, false},
+		{"breakpoints", "/api/v1/characters?name=nokka&expand=breakpoints", http.StatusOK, nil, true},
+		{"tooltips and breakpoints", "/api/v1/characters?name=nokka&expand=tooltips,breakpoints", http.StatusOK, []string{"Shako", "Defense: 98"}, true},
+		{"unknown section", "/api/v1/characters?name=nokka&expand=tooltips,everything", http.StatusBadRequest, nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			var resp struct {
+				Tooltips    *domain.ItemTooltips `json:"tooltips"`
+				Breakpoints *domain.Breakpoints  `json:"breakpoints"`
+			}
+
+			if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch {
+			case tt.tooltips == nil && resp.Tooltips != nil:
+				t.Errorf("expected no tooltips, got = %+v", resp.Tooltips)
+			case tt.tooltips != nil && (resp.Tooltips == nil || !reflect.DeepEqual(resp.Tooltips.Items[0], tt.tooltips)):
+				t.Errorf("expected tooltip %q, got = %+v", tt.tooltips, resp.Tooltips)
+			}
+
+			if tt.breakpoints != (resp.Breakpoints != nil) {
+				t.Errorf("expected breakpoints to be included = %t, got = %+v", tt.breakpoints, resp.Breakpoints)
+			}
+		})
+	}
+}
@@ -47,16 +47,23 @@ func (e *encoder) Error(w http.ResponseWriter, err error) {
 		w.Header().Set("x-temporary", "true")
 	}
 
+	w.WriteHeader(statusCode(err))
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// statusCode determines the http status code of the error.
+func statusCode(err error) int {
 	switch errors.Unwrap(err) {
 	case domain.ErrRequest:
-		w.WriteHeader(http.StatusBadRequest)
+		return http.StatusBadRequest
 	case domain.ErrNotFound:
-		w.WriteHeader(http.StatusNotFound)
+		return http.StatusNotFound
+	case domain.ErrGone:
+		return http.StatusGone
 	case domain.ErrUnavailable:
-		w.WriteHeader(http.StatusServiceUnavailable)
+		return http.StatusServiceUnavailable
 	default:
-		w.WriteHeader(http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
-
-	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// heartbeatInterval is how often a comment is sent on an idle stream, it keeps
+// proxies from closing the connection and lets us notice clients that are gone.
+const heartbeatInterval = 15 * time.Second
+
+// eventBroker represents the functionality we need to stream live character updates.
+type eventBroker interface {
+	// Subscribe returns the events of the character, or of all characters when
+	// it's empty, until cancel is called.
+	Subscribe(character string) (<-chan domain.Event, func())
+}
+
+// eventsHandler is used to stream character updates as server-sent events.
+type eventsHandler struct {
+	encoder     *encoder
+	eventBroker eventBroker
+}
+
+func (h eventsHandler) Routes(router chi.Router) {
+	router.Get("/", h.getEvents)
+}
+
+func (h eventsHandler) CharacterRoutes(router chi.Router) {
+	router.Get("/{name}/events", h.getCharacterEvents)
+}
+
+func (h eventsHandler) getEvents(w http.ResponseWriter, r *http.Request) {
+	h.stream(w, r, "")
+}
+
+func (h eventsHandler) getCharacterEvents(w http.ResponseWriter, r *http.Request) {
+	h.stream(w, r, chi.URLParam(r, "name"))
+}
+
+// stream writes the events to the client until it disconnects, or until the
+// broker drops it for falling behind.
+func (h eventsHandler) stream(w http.ResponseWriter, r *http.Request, character string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.encoder.Error(w, errors.New("streaming is not supported"))
+		return
+	}
+
+	events, cancel := h.eventBroker.Subscribe(character)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+func newEventsHandler(encoder *encoder, eventBroker eventBroker) *eventsHandler {
+	return &eventsHandler{
+		encoder:     encoder,
+		eventBroker: eventBroker,
+	}
+}
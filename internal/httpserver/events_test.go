@@ -0,0 +1,68 @@
+package httpserver
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/broker"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestEventsHandler(t *testing.T) {
+	b := broker.NewBroker()
+
+	srv := httptest.NewServer(NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithEventBroker(b)).Handler())
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want []string
+	}{
+		{"realm wide", "/api/v1/events", []string{"wheelz", "nokka"}},
+		{"single character", "/api/v1/characters/nokka/events", []string{"nokka"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+				t.Fatalf("expected an event stream, got = %s", ct)
+			}
+
+			// The subscription exists once the headers have been sent.
+			b.Publish(domain.Event{Type: domain.EventCharacterUpdated, Character: "wheelz"})
+			b.Publish(domain.Event{Type: domain.EventCharacterUpdated, Character: "nokka"})
+
+			lines := make(chan string)
+			go func() {
+				scanner := bufio.NewScanner(resp.Body)
+				for scanner.Scan() {
+					if strings.HasPrefix(scanner.Text(), "data: ") {
+						lines <- scanner.Text()
+					}
+				}
+				close(lines)
+			}()
+
+			// Events of other characters are published first, so they'd be read first.
+			for _, name := range tt.want {
+				select {
+				case line := <-lines:
+					if !strings.Contains(line, `"character":"`+name+`"`) {
+						t.Errorf("expected an event of %s, got = %s", name, line)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("timed out waiting for an event of %s", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// graveyardService represents the functionality we need to look at fallen hardcore characters.
+type graveyardService interface {
+	// List returns a page of deaths, most recent first.
+	List(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error)
+}
+
+// graveyardHandler is used to list the hardcore characters that have died.
+type graveyardHandler struct {
+	encoder          *encoder
+	graveyardService graveyardService
+}
+
+func (h graveyardHandler) Routes(router chi.Router) {
+	router.Get("/", h.getGraveyard)
+}
+
+func (h graveyardHandler) getGraveyard(w http.ResponseWriter, r *http.Request) {
+	query, err := parseGraveyardQuery(r)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	page, err := h.graveyardService.List(r.Context(), query)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, page)
+}
+
+// parseGraveyardQuery reads the filters and pagination from the query string.
+func parseGraveyardQuery(r *http.Request) (domain.GraveyardQuery, error) {
+	var (
+		values = r.URL.Query()
+		query  = domain.GraveyardQuery{
+			Class:  values.Get("class"),
+			Cursor: values.Get("cursor"),
+		}
+		err error
+	)
+
+	if level := values.Get("min_level"); level != "" {
+		if query.MinLevel, err = strconv.ParseUint(level, 10, 64); err != nil {
+			return query, fmt.Errorf("min_level must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	if level := values.Get("max_level"); level != "" {
+		if query.MaxLevel, err = strconv.ParseUint(level, 10, 64); err != nil {
+			return query, fmt.Errorf("max_level must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	return query, nil
+}
+
+func newGraveyardHandler(encoder *encoder, graveyardService graveyardService) *graveyardHandler {
+	return &graveyardHandler{
+		encoder:          encoder,
+		graveyardService: graveyardService,
+	}
+}
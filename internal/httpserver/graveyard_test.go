@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// graveyardServiceFunc lets a function act as the graveyard service.
+type graveyardServiceFunc func(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error)
+
+func (f graveyardServiceFunc) List(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error) {
+	return f(ctx, query)
+}
+
+func TestGraveyardHandler(t *testing.T) {
+	var got domain.GraveyardQuery
+
+	service := graveyardServiceFunc(func(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error) {
+		got = query
+		return &domain.GraveyardPage{Deaths: []domain.Death{}}, nil
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithGraveyardService(service))
+
+	for _, tt := range []struct {
+		name  string
+		path  string
+		want  int
+		query domain.GraveyardQuery
+	}{
+		{"no filters", "/api/v1/graveyard", http.StatusOK, domain.GraveyardQuery{}},
+		{"filters", "/api/v1/graveyard?class=paladin&min_level=80&max_level=99&limit=5", http.StatusOK, domain.GraveyardQuery{Class: "paladin", MinLevel: 80, MaxLevel: 99, Limit: 5}},
+		{"invalid level", "/api/v1/graveyard?min_level=high", http.StatusBadRequest, domain.GraveyardQuery{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.GraveyardQuery{}
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if got != tt.query {
+				t.Errorf("expected query %+v, got = %+v", tt.query, got)
+			}
+		})
+	}
+}
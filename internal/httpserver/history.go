@@ -0,0 +1,125 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// historyService represents the functionality we need to look at character snapshots.
+type historyService interface {
+	// List returns a page of snapshots of the character.
+	List(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error)
+
+	// Diff returns the difference between two versions of the character.
+	Diff(ctx context.Context, character string, from time.Time, to time.Time) (*domain.CharacterDiff, error)
+}
+
+// historyHandler is used to get the history of a character and compare its versions.
+type historyHandler struct {
+	encoder        *encoder
+	historyService historyService
+}
+
+func (h historyHandler) Routes(router chi.Router) {
+	router.Get("/{name}/history", h.getHistory)
+	router.Get("/{name}/diff", h.getDiff)
+}
+
+func (h historyHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	query, err := parseHistoryQuery(r)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	page, err := h.historyService.List(r.Context(), name, query)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, page)
+}
+
+func (h historyHandler) getDiff(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	from, err := parseTime(r, "from")
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	to, err := parseTime(r, "to")
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	diff, err := h.historyService.Diff(r.Context(), name, from, to)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, diff)
+}
+
+// parseHistoryQuery reads the time range and pagination from the query string.
+func parseHistoryQuery(r *http.Request) (domain.HistoryQuery, error) {
+	var (
+		values = r.URL.Query()
+		query  = domain.HistoryQuery{
+			Cursor: values.Get("cursor"),
+		}
+		err error
+	)
+
+	if query.From, err = parseTime(r, "from"); err != nil {
+		return query, err
+	}
+
+	if query.To, err = parseTime(r, "to"); err != nil {
+		return query, err
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	return query, nil
+}
+
+// parseTime reads an optional RFC3339 time from the query string.
+func parseTime(r *http.Request, key string) (time.Time, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be RFC3339: %w", key, domain.ErrRequest)
+	}
+
+	return t, nil
+}
+
+func newHistoryHandler(encoder *encoder, historyService historyService) *historyHandler {
+	return &historyHandler{
+		encoder:        encoder,
+		historyService: historyService,
+	}
+}
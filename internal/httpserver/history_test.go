@@ -0,0 +1,65 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// historyServiceFunc lets a function act as the history service, the history
+// is listed for the characters it has a diff for.
+type historyServiceFunc func(ctx context.Context, character string, from time.Time, to time.Time) (*domain.CharacterDiff, error)
+
+func (f historyServiceFunc) List(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error) {
+	if _, err := f(ctx, character, query.From, query.To); err != nil {
+		return nil, err
+	}
+
+	return &domain.HistoryPage{Snapshots: []domain.Snapshot{}}, nil
+}
+
+func (f historyServiceFunc) Diff(ctx context.Context, character string, from time.Time, to time.Time) (*domain.CharacterDiff, error) {
+	return f(ctx, character, from, to)
+}
+
+func TestHistoryHandler(t *testing.T) {
+	service := historyServiceFunc(func(ctx context.Context, character string, from time.Time, to time.Time) (*domain.CharacterDiff, error) {
+		if character != "nokka" {
+			return nil, fmt.Errorf("no snapshots of %s: %w", character, domain.ErrNotFound)
+		}
+
+		return &domain.CharacterDiff{Character: character, From: from, To: to}, nil
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithHistoryService(service))
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want int
+	}{
+		{"history", "/api/v1/characters/nokka/history?from=2024-01-01T00:00:00Z&limit=5", http.StatusOK},
+		{"history of unknown character", "/api/v1/characters/unknown/history", http.StatusNotFound},
+		{"history with invalid time", "/api/v1/characters/nokka/history?from=yesterday", http.StatusBadRequest},
+		{"history with invalid limit", "/api/v1/characters/nokka/history?limit=many", http.StatusBadRequest},
+		{"diff", "/api/v1/characters/nokka/diff?from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z", http.StatusOK},
+		{"diff of unknown character", "/api/v1/characters/unknown/diff?from=2024-01-01T00:00:00Z", http.StatusNotFound},
+		{"diff with invalid from", "/api/v1/characters/nokka/diff?from=yesterday", http.StatusBadRequest},
+		{"diff with invalid to", "/api/v1/characters/nokka/diff?from=2024-01-01T00:00:00Z&to=now", http.StatusBadRequest},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+		})
+	}
+}
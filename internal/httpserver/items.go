@@ -0,0 +1,114 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// attributeFilterRegexp matches attribute filters such as skills>=2, the plus
+// sign of +skills>=2 is decoded as a space when it isn't escaped.
+var attributeFilterRegexp = regexp.MustCompile(`^[+ ]?([a-zA-Z0-9_]+)\s*(>=|<=|=|>|<)\s*(-?[0-9]+)$`)
+
+// itemService represents the functionality we need to search the item index.
+type itemService interface {
+	// Search returns a page of the items matching the query.
+	Search(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
+}
+
+// itemHandler is used to search the items of all characters.
+type itemHandler struct {
+	encoder     *encoder
+	itemService itemService
+}
+
+func (h itemHandler) Routes(router chi.Router) {
+	router.Get("/search", h.searchItems)
+}
+
+func (h itemHandler) searchItems(w http.ResponseWriter, r *http.Request) {
+	query, err := parseItemQuery(r)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	page, err := h.itemService.Search(r.Context(), query)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, page)
+}
+
+// parseItemQuery reads the filters and pagination from the query string.
+func parseItemQuery(r *http.Request) (domain.ItemQuery, error) {
+	var (
+		values = r.URL.Query()
+		query  = domain.ItemQuery{
+			Name:       values.Get("name"),
+			Type:       values.Get("type"),
+			Quality:    values.Get("quality"),
+			SetName:    values.Get("set"),
+			UniqueName: values.Get("unique"),
+			Runeword:   values.Get("runeword"),
+			Cursor:     values.Get("cursor"),
+		}
+		err error
+	)
+
+	if ethereal := values.Get("ethereal"); ethereal != "" {
+		b, err := strconv.ParseBool(ethereal)
+		if err != nil {
+			return query, fmt.Errorf("ethereal must be a boolean: %w", domain.ErrRequest)
+		}
+		query.Ethereal = &b
+	}
+
+	if sockets := values.Get("min_sockets"); sockets != "" {
+		if query.MinSockets, err = strconv.ParseUint(sockets, 10, 64); err != nil {
+			return query, fmt.Errorf("min_sockets must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	for _, attr := range values["attr"] {
+		match := attributeFilterRegexp.FindStringSubmatch(strings.TrimSpace(attr))
+		if match == nil {
+			return query, fmt.Errorf("invalid attribute filter %q: %w", attr, domain.ErrRequest)
+		}
+
+		value, err := strconv.ParseInt(match[3], 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid attribute filter %q: %w", attr, domain.ErrRequest)
+		}
+
+		query.Attributes = append(query.Attributes, domain.AttributeFilter{
+			Key:      match[1],
+			Operator: match[2],
+			Value:    value,
+		})
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	return query, nil
+}
+
+func newItemHandler(encoder *encoder, itemService itemService) *itemHandler {
+	return &itemHandler{
+		encoder:     encoder,
+		itemService: itemService,
+	}
+}
@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// itemServiceFunc lets a function act as the item service.
+type itemServiceFunc func(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
+
+func (f itemServiceFunc) Search(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	return f(ctx, query)
+}
+
+func TestItemHandler(t *testing.T) {
+	var got domain.ItemQuery
+
+	service := itemServiceFunc(func(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+		got = query
+		return &domain.ItemPage{Items: []domain.IndexedItem{}}, nil
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithItemService(service))
+
+	ethereal := true
+
+	for _, tt := range []struct {
+		name  string
+		path  string
+		want  int
+		query domain.ItemQuery
+	}{
+		{
+			name:  "runeword",
+			path:  "/api/v1/items/search?runeword=Enigma",
+			want:  http.StatusOK,
+			query: domain.ItemQuery{Runeword: "Enigma"},
+		},
+		{
+			name: "attribute thresholds",
+			path: "/api/v1/items/search?quality=unique&ethereal=true&min_sockets=2&attr=%2Bskills%3E%3D2&attr=fcr>20",
+			want: http.StatusOK,
+			query: domain.ItemQuery{
+				Quality:    "unique",
+				Ethereal:   &ethereal,
+				MinSockets: 2,
+				Attributes: []domain.AttributeFilter{
+					{Key: "skills", Operator: ">=", Value: 2},
+					{Key: "fcr", Operator: ">", Value: 20},
+				},
+			},
+		},
+		{
+			name: "unescaped plus sign",
+			path: "/api/v1/items/search?attr=+skills>=2",
+			want: http.StatusOK,
+			query: domain.ItemQuery{
+				Attributes: []domain.AttributeFilter{{Key: "skills", Operator: ">=", Value: 2}},
+			},
+		},
+		{
+			name: "invalid attribute filter",
+			path: "/api/v1/items/search?attr=skills",
+			want: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.ItemQuery{}
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if !reflect.DeepEqual(got, tt.query) {
+				t.Errorf("expected query %+v, got = %+v", tt.query, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// ladderService represents the functionality we need to rank characters.
+type ladderService interface {
+	// List returns a page of the ladder.
+	List(ctx context.Context, query domain.LadderQuery) (*domain.LadderPage, error)
+}
+
+// ladderHandler is used to rank the characters of a realm.
+type ladderHandler struct {
+	encoder       *encoder
+	ladderService ladderService
+}
+
+func (h ladderHandler) Routes(router chi.Router) {
+	router.Get("/", h.getLadder)
+}
+
+func (h ladderHandler) getLadder(w http.ResponseWriter, r *http.Request) {
+	query, err := parseLadderQuery(r)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	page, err := h.ladderService.List(r.Context(), query)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, page)
+}
+
+// parseLadderQuery reads the filters and pagination from the query string.
+func parseLadderQuery(r *http.Request) (domain.LadderQuery, error) {
+	var (
+		values = r.URL.Query()
+		query  = domain.LadderQuery{
+			Class:  values.Get("class"),
+			Cursor: values.Get("cursor"),
+		}
+		err error
+	)
+
+	for key, dst := range map[string]**bool{
+		"hardcore":  &query.Hardcore,
+		"expansion": &query.Expansion,
+	} {
+		value := values.Get(key)
+		if value == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("%s must be a boolean: %w", key, domain.ErrRequest)
+		}
+		*dst = &b
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be a number: %w", domain.ErrRequest)
+		}
+	}
+
+	return query, nil
+}
+
+func newLadderHandler(encoder *encoder, ladderService ladderService) *ladderHandler {
+	return &ladderHandler{
+		encoder:       encoder,
+		ladderService: ladderService,
+	}
+}
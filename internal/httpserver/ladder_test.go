@@ -0,0 +1,62 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// ladderServiceFunc lets a function act as the ladder service.
+type ladderServiceFunc func(ctx context.Context, query domain.LadderQuery) (*domain.LadderPage, error)
+
+func (f ladderServiceFunc) List(ctx context.Context, query domain.LadderQuery) (*domain.LadderPage, error) {
+	return f(ctx, query)
+}
+
+func TestLadderHandler(t *testing.T) {
+	var got domain.LadderQuery
+
+	service := ladderServiceFunc(func(ctx context.Context, query domain.LadderQuery) (*domain.LadderPage, error) {
+		got = query
+		return &domain.LadderPage{Entries: []domain.LadderEntry{}}, nil
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithLadderService(service))
+
+	hardcore, expansion := true, false
+
+	for _, tt := range []struct {
+		name  string
+		path  string
+		want  int
+		query domain.LadderQuery
+	}{
+		{"no filters", "/api/v1/ladder", http.StatusOK, domain.LadderQuery{}},
+		{
+			name:  "filters",
+			path:  "/api/v1/ladder?class=druid&hardcore=true&expansion=false&cursor=40&limit=20",
+			want:  http.StatusOK,
+			query: domain.LadderQuery{Class: "druid", Hardcore: &hardcore, Expansion: &expansion, Cursor: "40", Limit: 20},
+		},
+		{"invalid hardcore", "/api/v1/ladder?hardcore=maybe", http.StatusBadRequest, domain.LadderQuery{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.LadderQuery{}
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if !reflect.DeepEqual(got, tt.query) {
+				t.Errorf("expected query %+v, got = %+v", tt.query, got)
+			}
+		})
+	}
+}
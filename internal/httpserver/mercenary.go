@@ -0,0 +1,52 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// mercenaryHandler is used to get the mercenary of a character.
+type mercenaryHandler struct {
+	encoder          *encoder
+	characterService characterService
+}
+
+func (h mercenaryHandler) Routes(router chi.Router) {
+	router.Get("/{name}/mercenary", h.getMercenary)
+}
+
+func (h mercenaryHandler) getMercenary(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	char, err := h.characterService.Parse(r.Context(), name)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	decoded, err := char.Decoded()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	mercenary := domain.CharacterMercenary(decoded)
+	if mercenary == nil {
+		h.encoder.Error(w, fmt.Errorf("character has no mercenary: %w", domain.ErrNotFound))
+		return
+	}
+	mercenary.Character = char.ID
+
+	h.encoder.Response(w, mercenary)
+}
+
+func newMercenaryHandler(encoder *encoder, characterService characterService) *mercenaryHandler {
+	return &mercenaryHandler{
+		encoder:          encoder,
+		characterService: characterService,
+	}
+}
@@ -0,0 +1,118 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestMercenaryHandler(t *testing.T) {
+	char := &d2s.Character{}
+	char.Header.MercID = 0xbeef
+	char.Header.MercType = 10
+	char.Header.MercNameID = 3
+	char.Header.MercExp = 2_000_000
+	char.Header.DeadMerc = 1
+	char.MercItems = []d2s.Item{{Type: "pax", TypeName: "Giant Thresher"}}
+
+	deletedAt := time.Now()
+	service := staticCharacterService{
+		"nokka":   &domain.Character{ID: "nokka", D2s: char},
+		"nomerc":  &domain.Character{ID: "nomerc", D2s: &d2s.Character{}},
+		"deleted": &domain.Character{ID: "deleted", D2s: char, DeletedAt: &deletedAt},
+		"pending": &domain.Character{ID: "pending"},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want int
+	}{
+		{"deleted character", "/api/v1/characters/deleted/mercenary", http.StatusGone},
+		{"character without binary", "/api/v1/characters/pending/mercenary", http.StatusNotFound},
+		{"character without mercenary", "/api/v1/characters/nomerc/mercenary", http.StatusNotFound},
+		{"unknown character", "/api/v1/characters/unknown/mercenary", http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/characters/nokka/mercenary", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("want status 200, got = %d", recorder.Code)
+	}
+
+	var mercenary domain.Mercenary
+	if err := json.Unmarshal(recorder.Body.Bytes(), &mercenary); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mercenary.Character != "nokka" || mercenary.ID != "beef" {
+		t.Errorf("expected mercenary beef of nokka, got = %s of %s", mercenary.ID, mercenary.Character)
+	}
+
+	if mercenary.Class != domain.MercenaryDesertMercenary || mercenary.Act != "Act II" || mercenary.Difficulty != domain.DifficultyNightmare {
+		t.Errorf("expected a Desert Mercenary of act II in Nightmare, got = %s of %s in %s", mercenary.Class, mercenary.Act, mercenary.Difficulty)
+	}
+
+	if mercenary.Variant != "Defensive" || mercenary.Aura != "Holy Freeze" {
+		t.Errorf("expected a Defensive mercenary with Holy Freeze, got = %s with %s", mercenary.Variant, mercenary.Aura)
+	}
+
+	if mercenary.Name != "Ahsab" {
+		t.Errorf("expected name Ahsab, got = %s", mercenary.Name)
+	}
+
+	// 110 × 25² × 26 = 1,787,500 and 110 × 26² × 27 = 2,007,720.
+	if mercenary.Level != 25 {
+		t.Errorf("expected level 25, got = %d", mercenary.Level)
+	}
+
+	if !mercenary.Dead {
+		t.Error("expected the mercenary to be dead")
+	}
+
+	if len(mercenary.Items) != 1 || mercenary.Items[0].TypeName != "Giant Thresher" {
+		t.Errorf("expected the equipped Giant Thresher, got = %+v", mercenary.Items)
+	}
+}
+
+func TestMercenaryNames(t *testing.T) {
+	for _, tt := range []struct {
+		mercType uint16
+		nameID   uint16
+		want     string
+	}{
+		{0, 40, "Wendy"},
+		{6, 20, "Vikhyat"},
+		{17, 0, "Jelani"},
+		{23, 19, "Scorch"},
+		// Types added by mods are named by their number.
+		{64, 3, "Mercenary #4"},
+	} {
+		char := &d2s.Character{}
+		char.Header.MercID = 0xbeef
+		char.Header.MercType = tt.mercType
+		char.Header.MercNameID = tt.nameID
+
+		if got := domain.CharacterMercenary(char).Name; got != tt.want {
+			t.Errorf("expected mercenary type %d with name id %d to be named %s, got = %s", tt.mercType, tt.nameID, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// progressService represents the functionality we need to tell how fast characters level.
+type progressService interface {
+	// Progress returns the experience rate of the character over the windows.
+	Progress(ctx context.Context, character string, windows ...time.Duration) (*domain.Progress, error)
+}
+
+// progressHandler is used to get the experience rate and level estimates of a character.
+type progressHandler struct {
+	encoder         *encoder
+	progressService progressService
+}
+
+func (h progressHandler) Routes(router chi.Router) {
+	router.Get("/{name}/progress", h.getProgress)
+}
+
+func (h progressHandler) getProgress(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var windows []time.Duration
+	if value := r.URL.Query().Get("windows"); value != "" {
+		for _, v := range strings.Split(value, ",") {
+			window, err := domain.ParseWindow(strings.TrimSpace(v))
+			if err != nil {
+				h.encoder.Error(w, err)
+				return
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	progress, err := h.progressService.Progress(r.Context(), name, windows...)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, progress)
+}
+
+func newProgressHandler(encoder *encoder, progressService progressService) *progressHandler {
+	return &progressHandler{
+		encoder:         encoder,
+		progressService: progressService,
+	}
+}
@@ -0,0 +1,57 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// progressServiceFunc lets a function act as the progress service.
+type progressServiceFunc func(ctx context.Context, character string, windows ...time.Duration) (*domain.Progress, error)
+
+func (f progressServiceFunc) Progress(ctx context.Context, character string, windows ...time.Duration) (*domain.Progress, error) {
+	return f(ctx, character, windows...)
+}
+
+func TestProgressHandler(t *testing.T) {
+	var got []time.Duration
+
+	service := progressServiceFunc(func(ctx context.Context, character string, windows ...time.Duration) (*domain.Progress, error) {
+		got = windows
+		return &domain.Progress{Character: character}, nil
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithProgressService(service))
+
+	for _, tt := range []struct {
+		name    string
+		path    string
+		want    int
+		windows []time.Duration
+	}{
+		{"default windows", "/api/v1/characters/nokka/progress", http.StatusOK, nil},
+		{"windows", "/api/v1/characters/nokka/progress?windows=90m,6h,7d", http.StatusOK, []time.Duration{90 * time.Minute, 6 * time.Hour, 7 * 24 * time.Hour}},
+		{"invalid window", "/api/v1/characters/nokka/progress?windows=1h,soon", http.StatusBadRequest, nil},
+		{"negative window", "/api/v1/characters/nokka/progress?windows=-1h", http.StatusBadRequest, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if !reflect.DeepEqual(got, tt.windows) {
+				t.Errorf("expected windows %v, got = %v", tt.windows, got)
+			}
+		})
+	}
+}
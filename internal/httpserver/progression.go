@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// progressionHandler is used to get the quests and waypoints a character has done.
+type progressionHandler struct {
+	encoder          *encoder
+	characterService characterService
+}
+
+func (h progressionHandler) Routes(router chi.Router) {
+	router.Get("/{name}/progression", h.getProgression)
+}
+
+func (h progressionHandler) getProgression(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	char, err := h.characterService.Parse(r.Context(), name)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	decoded, err := char.Decoded()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	progression := domain.CharacterProgression(decoded)
+	progression.Character = char.ID
+
+	h.encoder.Response(w, progression)
+}
+
+func newProgressionHandler(encoder *encoder, characterService characterService) *progressionHandler {
+	return &progressionHandler{
+		encoder:          encoder,
+		characterService: characterService,
+	}
+}
@@ -0,0 +1,105 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestProgressionHandler(t *testing.T) {
+	char := &d2s.Character{}
+	// Expansion character.
+	char.Header.Status = 0x20
+	char.Header.QuestsNormal.ActI.DenOfEvil = [2]byte{0x01, 0}
+	char.Header.QuestsNormal.ActI.ToolsOfTheTrade = [2]byte{0x02, 0}
+	char.Header.QuestsNormal.ActI.TheSearchForCain = [2]byte{0x01, 0x04}
+	char.Header.QuestsNormal.ActII.RadamentsLair = [2]byte{0x02, 0}
+	char.Header.QuestsNormal.ActV.EveOfDestruction = [2]byte{0x01, 0}
+	char.Header.QuestsNm.ActV.EveOfDestruction = [2]byte{0x01, 0}
+	char.Header.WaypointsNormal[2] = 0x07
+	char.Header.WaypointsNormal[5] = 0x08
+
+	deletedAt := time.Now()
+	service := staticCharacterService{
+		"nokka":   &domain.Character{ID: "nokka", D2s: char},
+		"deleted": &domain.Character{ID: "deleted", D2s: char, DeletedAt: &deletedAt},
+		"pending": &domain.Character{ID: "pending"},
+	}
+
+	srv := NewServer(":80", service, nil, nil, false, false)
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want int
+	}{
+		{"deleted character", "/api/v1/characters/deleted/progression", http.StatusGone},
+		{"character without binary", "/api/v1/characters/pending/progression", http.StatusNotFound},
+		{"unknown character", "/api/v1/characters/unknown/progression", http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/characters/nokka/progression", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("want status 200, got = %d", recorder.Code)
+	}
+
+	var progression domain.Progression
+	if err := json.Unmarshal(recorder.Body.Bytes(), &progression); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(progression.Difficulties) != 3 {
+		t.Fatalf("expected 3 difficulties, got = %d", len(progression.Difficulties))
+	}
+
+	normal, nightmare := progression.Difficulties[0], progression.Difficulties[1]
+
+	if len(normal.Acts) != 5 || normal.Acts[0].Completed != 2 || normal.Acts[0].Total != 6 {
+		t.Errorf("expected 2 of 6 quests done in act I of 5 acts, got = %+v", normal.Acts)
+	}
+
+	expectedRewards := []domain.QuestReward{
+		{Quest: "Den of Evil", Reward: "1 skill point", Status: domain.RewardCollected},
+		{Quest: "Tools of the Trade", Reward: "Imbue an item", Status: domain.RewardAvailable},
+		{Quest: "Radament's Lair", Reward: "1 skill point", Status: domain.RewardAvailable},
+		{Quest: "Lam Esen's Tome", Reward: "5 stat points", Status: domain.RewardUnavailable},
+		{Quest: "The Fallen Angel", Reward: "2 skill points", Status: domain.RewardUnavailable},
+		{Quest: "Siege on Harrogath", Reward: "Socket an item", Status: domain.RewardUnavailable},
+		{Quest: "Betrayal of Harrogath", Reward: "Personalize an item", Status: domain.RewardUnavailable},
+	}
+	if !reflect.DeepEqual(normal.Rewards, expectedRewards) {
+		t.Errorf("unexpected rewards\nexpected = %+v\ngot      = %+v", expectedRewards, normal.Rewards)
+	}
+
+	// The Cow King has been killed in normal, closing the Cow Level.
+	if normal.CowLevel != (domain.CowLevel{Open: false, CowKingKilled: true}) || nightmare.CowLevel != (domain.CowLevel{Open: true}) {
+		t.Errorf("unexpected cow level, got = %+v in normal, %+v in nightmare", normal.CowLevel, nightmare.CowLevel)
+	}
+
+	expectedWaypoints := domain.WaypointsUnlocked{
+		Unlocked: 4,
+		Total:    39,
+		Names:    []string{"Rogue Encampment", "Cold Plains", "Stony Field", "The Pandemonium Fortress"},
+	}
+	if !reflect.DeepEqual(normal.Waypoints, expectedWaypoints) {
+		t.Errorf("unexpected waypoints, expected = %+v, got = %+v", expectedWaypoints, normal.Waypoints)
+	}
+}
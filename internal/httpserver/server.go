@@ -20,11 +20,118 @@ type Server struct {
 	addr              string
 	characterService  characterService
 	statisticsService statisticsService
+	historyService    historyService
+	stashService      stashService
+	accountService    accountService
+	graveyardService  graveyardService
+	eventBroker       eventBroker
+	itemService       itemService
+	ladderService     ladderService
+	progressService   progressService
+	statsService      statsService
+	uploadService     uploadService
+	realms            map[string]Realm
 	credentials       map[string]string
 	corsEnabled       bool
 	loggingEnabled    bool
 }
 
+// Realm holds the services serving the characters of a realm, only the
+// character service is required.
+type Realm struct {
+	CharacterService characterService
+	HistoryService   historyService
+	StashService     stashService
+	AccountService   accountService
+	GraveyardService graveyardService
+	EventBroker      eventBroker
+	ItemService      itemService
+	LadderService    ladderService
+	ProgressService  progressService
+	StatsService     statsService
+}
+
+// Option is used to enable optional functionality of the server.
+type Option func(s *Server)
+
+// WithHistoryService enables the character history and diff endpoints.
+func WithHistoryService(historyService historyService) Option {
+	return func(s *Server) {
+		s.historyService = historyService
+	}
+}
+
+// WithStashService enables the PlugY stash endpoints.
+func WithStashService(stashService stashService) Option {
+	return func(s *Server) {
+		s.stashService = stashService
+	}
+}
+
+// WithAccountService enables the account endpoints.
+func WithAccountService(accountService accountService) Option {
+	return func(s *Server) {
+		s.accountService = accountService
+	}
+}
+
+// WithGraveyardService enables the graveyard of fallen hardcore characters.
+func WithGraveyardService(graveyardService graveyardService) Option {
+	return func(s *Server) {
+		s.graveyardService = graveyardService
+	}
+}
+
+// WithEventBroker enables streaming live character updates as server-sent events.
+func WithEventBroker(eventBroker eventBroker) Option {
+	return func(s *Server) {
+		s.eventBroker = eventBroker
+	}
+}
+
+// WithItemService enables searching the items of all characters.
+func WithItemService(itemService itemService) Option {
+	return func(s *Server) {
+		s.itemService = itemService
+	}
+}
+
+// WithLadderService enables ranking the characters on a ladder.
+func WithLadderService(ladderService ladderService) Option {
+	return func(s *Server) {
+		s.ladderService = ladderService
+	}
+}
+
+// WithProgressService enables the experience rate and level estimates of characters.
+func WithProgressService(progressService progressService) Option {
+	return func(s *Server) {
+		s.progressService = progressService
+	}
+}
+
+// WithStatsService enables the effective stats of characters.
+func WithStatsService(statsService statsService) Option {
+	return func(s *Server) {
+		s.statsService = statsService
+	}
+}
+
+// WithUploadService enables parsing uploaded binaries, storing them requires
+// the same credentials as posting statistics.
+func WithUploadService(uploadService uploadService) Option {
+	return func(s *Server) {
+		s.uploadService = uploadService
+	}
+}
+
+// WithRealm serves the characters of the realm under /api/v1/realms/{realm}.
+func WithRealm(name string, realm Realm) Option {
+	return func(s *Server) {
+		s.realms[name] = realm
+	}
+}
+
 // Open will open a tcp listener to serve http requests.
 func (s *Server) Open() error {
 	ln, err := net.Listen("tcp", s.addr)
@@ -34,9 +141,21 @@ func (s *Server) Open() error {
 
 	s.listener = ln
 
+	handler := s.Handler()
+	timeout := http.TimeoutHandler(handler, (2 * time.Second), "connection timeout")
+	streams := s.streamRoutes()
+
 	// Create an http server.
 	server := http.Server{
-		Handler:     http.TimeoutHandler(s.Handler(), (2 * time.Second), "connection timeout"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Event streams stay open for as long as the client is connected.
+			if streams.Match(chi.NewRouteContext(), r.Method, r.URL.Path) {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			timeout.ServeHTTP(w, r)
+		}),
 		ReadTimeout: 5 * time.Second,
 	}
 
@@ -69,7 +188,28 @@ func (s *Server) Handler() http.Handler {
 	}
 
 	r.Route("/health", newHealthHandler().Routes)
-	r.Route("/api/v1/characters", newCharacterHandler(s.encoder, s.characterService).Routes)
+	// Unscoped routes serve the default realm.
+	s.realmRoutes(r, "/api/v1", Realm{
+		CharacterService: s.characterService,
+		HistoryService:   s.historyService,
+		StashService:     s.stashService,
+		AccountService:   s.accountService,
+		GraveyardService: s.graveyardService,
+		EventBroker:      s.eventBroker,
+		ItemService:      s.itemService,
+		LadderService:    s.ladderService,
+		ProgressService:  s.progressService,
+		StatsService:     s.statsService,
+	})
+
+	for name, realm := range s.realms {
+		s.realmRoutes(r, "/api/v1/realms/"+name, realm)
+	}
+
+	if s.uploadService != nil {
+		r.Route("/api/v1/parse", newUploadHandler(s.encoder, s.uploadService, s.credentials).Routes)
+	}
+
 	r.Route("/api/v1/statistics", newStatisticsHandler(s.encoder, s.statisticsService, s.credentials).Routes)
 
 	// Deprecated handler, supported for consumers who rely on it.
@@ -86,15 +226,103 @@ func (s *Server) Handler() http.Handler {
 	return r
 }
 
+// realmRoutes mounts the routes of the realm under the prefix.
+func (s *Server) realmRoutes(r chi.Router, prefix string, realm Realm) {
+	r.Route(prefix+"/characters", func(r chi.Router) {
+		newCharacterHandler(s.encoder, realm.CharacterService).Routes(r)
+		newProgressionHandler(s.encoder, realm.CharacterService).Routes(r)
+		newMercenaryHandler(s.encoder, realm.CharacterService).Routes(r)
+
+		if realm.HistoryService != nil {
+			newHistoryHandler(s.encoder, realm.HistoryService).Routes(r)
+		}
+
+		if realm.StashService != nil {
+			newStashHandler(s.encoder, realm.StashService).Routes(r)
+		}
+
+		if realm.EventBroker != nil {
+			newEventsHandler(s.encoder, realm.EventBroker).CharacterRoutes(r)
+		}
+
+		if realm.ProgressService != nil {
+			newProgressHandler(s.encoder, realm.ProgressService).Routes(r)
+		}
+
+		if realm.StatsService != nil {
+			newStatsHandler(s.encoder, realm.StatsService).Routes(r)
+		}
+	})
+	r.Route(prefix+"/characters:batch", newBatchHandler(s.encoder, realm.CharacterService).Routes)
+
+	if realm.AccountService != nil || realm.StashService != nil {
+		r.Route(prefix+"/accounts", func(r chi.Router) {
+			if realm.AccountService != nil {
+				newAccountHandler(s.encoder, realm.AccountService).Routes(r)
+			}
+
+			if realm.StashService != nil {
+				newStashHandler(s.encoder, realm.StashService).AccountRoutes(r)
+			}
+		})
+	}
+
+	if realm.GraveyardService != nil {
+		r.Route(prefix+"/graveyard", newGraveyardHandler(s.encoder, realm.GraveyardService).Routes)
+	}
+
+	if realm.ItemService != nil {
+		r.Route(prefix+"/items", newItemHandler(s.encoder, realm.ItemService).Routes)
+	}
+
+	if realm.LadderService != nil {
+		r.Route(prefix+"/ladder", newLadderHandler(s.encoder, realm.LadderService).Routes)
+	}
+
+	if realm.EventBroker != nil {
+		r.Route(prefix+"/events", newEventsHandler(s.encoder, realm.EventBroker).Routes)
+	}
+}
+
+// streamRoutes returns a router matching exactly the event stream routes
+// mounted by Handler, it's only used to tell them apart from other requests.
+func (s *Server) streamRoutes() chi.Routes {
+	r := chi.NewRouter()
+
+	mount := func(prefix string, eventBroker eventBroker) {
+		if eventBroker == nil {
+			return
+		}
+
+		h := newEventsHandler(s.encoder, eventBroker)
+		r.Route(prefix+"/characters", h.CharacterRoutes)
+		r.Route(prefix+"/events", h.Routes)
+	}
+
+	mount("/api/v1", s.eventBroker)
+	for name, realm := range s.realms {
+		mount("/api/v1/realms/"+name, realm.EventBroker)
+	}
+
+	return r
+}
+
 // NewServer returns a new server with all dependencies.
-func NewServer(addr string, characterService characterService, statisticsService statisticsService, credentials map[string]string, corsEnabled bool, loggingEnabled bool) *Server {
-	return &Server{
+func NewServer(addr string, characterService characterService, statisticsService statisticsService, credentials map[string]string, corsEnabled bool, loggingEnabled bool, opts ...Option) *Server {
+	s := &Server{
 		addr:              addr,
 		encoder:           newEncoder(),
 		characterService:  characterService,
 		statisticsService: statisticsService,
+		realms:            make(map[string]Realm),
 		credentials:       credentials,
 		corsEnabled:       corsEnabled,
 		loggingEnabled:    loggingEnabled,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
@@ -0,0 +1,41 @@
+package httpserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	"github.com/nokka/d2-armory-api/internal/broker"
+)
+
+func TestStreamRoutes(t *testing.T) {
+	b := broker.NewBroker()
+	s := NewServer(":80", staticCharacterService{}, nil, nil, false, false,
+		WithEventBroker(b),
+		WithRealm("classic", Realm{CharacterService: staticCharacterService{}, EventBroker: b}),
+		WithRealm("ladder", Realm{CharacterService: staticCharacterService{}}),
+	)
+
+	streams := s.streamRoutes()
+
+	for _, tt := range []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/api/v1/events", true},
+		{http.MethodGet, "/api/v1/characters/nokka/events", true},
+		{http.MethodGet, "/api/v1/realms/classic/events", true},
+		{http.MethodGet, "/api/v1/realms/classic/characters/nokka/events", true},
+		// Requests that only end like a stream keep their timeout.
+		{http.MethodGet, "/api/v1/characters/events", false},
+		{http.MethodGet, "/api/v1/items/events", false},
+		{http.MethodGet, "/api/v1/characters/nokka/history/events", false},
+		{http.MethodGet, "/api/v1/realms/ladder/events", false},
+	} {
+		if got := streams.Match(chi.NewRouteContext(), tt.method, tt.path); got != tt.want {
+			t.Errorf("%s %s: expected a stream match to be %v, got = %v", tt.method, tt.path, tt.want, got)
+		}
+	}
+}
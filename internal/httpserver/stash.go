@@ -0,0 +1,71 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// stashService represents the functionality we need to look at PlugY stashes.
+type stashService interface {
+	// Stash returns the personal stash of the character.
+	Stash(ctx context.Context, name string) (*domain.Stash, error)
+
+	// SharedStash returns the stash shared by the characters of the account.
+	SharedStash(ctx context.Context, account string) (*domain.Stash, error)
+}
+
+// stashHandler is used to get personal and shared stashes.
+type stashHandler struct {
+	encoder      *encoder
+	stashService stashService
+}
+
+// Routes mounts the personal stash under the character routes.
+func (h stashHandler) Routes(router chi.Router) {
+	router.Get("/{name}/stash", h.getStash)
+}
+
+// AccountRoutes mounts the shared stash under the account routes.
+func (h stashHandler) AccountRoutes(router chi.Router) {
+	router.Get("/{account}/shared-stash", h.getSharedStash)
+}
+
+func (h stashHandler) getStash(w http.ResponseWriter, r *http.Request) {
+	// Pass the request context in order to make use of cancellation for lower level work.
+	stash, err := h.stashService.Stash(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, struct {
+		Stash *domain.Stash `json:"stash"`
+	}{
+		Stash: stash,
+	})
+}
+
+func (h stashHandler) getSharedStash(w http.ResponseWriter, r *http.Request) {
+	// Pass the request context in order to make use of cancellation for lower level work.
+	stash, err := h.stashService.SharedStash(r.Context(), chi.URLParam(r, "account"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, struct {
+		Stash *domain.Stash `json:"stash"`
+	}{
+		Stash: stash,
+	})
+}
+
+func newStashHandler(encoder *encoder, stashService stashService) *stashHandler {
+	return &stashHandler{
+		encoder:      encoder,
+		stashService: stashService,
+	}
+}
@@ -0,0 +1,79 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// statsService represents the functionality we need to compute the stats of characters.
+type statsService interface {
+	// EffectiveStats returns the stats of the character with its gear applied.
+	EffectiveStats(ctx context.Context, name string) (*domain.EffectiveStats, error)
+
+	// Breakpoints returns the tiers of the speed stats of the character.
+	Breakpoints(ctx context.Context, name string) (*domain.Breakpoints, error)
+
+	// View returns a view of the character with its skills decoded.
+	View(ctx context.Context, name string) (*domain.CharacterView, error)
+}
+
+// statsHandler is used to get the effective stats, breakpoints and view of a character.
+type statsHandler struct {
+	encoder      *encoder
+	statsService statsService
+}
+
+func (h statsHandler) Routes(router chi.Router) {
+	router.Get("/{name}/effective-stats", h.getEffectiveStats)
+	router.Get("/{name}/breakpoints", h.getBreakpoints)
+	router.Get("/{name}/view", h.getView)
+}
+
+func (h statsHandler) getEffectiveStats(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	stats, err := h.statsService.EffectiveStats(r.Context(), name)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, stats)
+}
+
+func (h statsHandler) getBreakpoints(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	breakpoints, err := h.statsService.Breakpoints(r.Context(), name)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, breakpoints)
+}
+
+func (h statsHandler) getView(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	view, err := h.statsService.View(r.Context(), name)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, view)
+}
+
+func newStatsHandler(encoder *encoder, statsService statsService) *statsHandler {
+	return &statsHandler{
+		encoder:      encoder,
+		statsService: statsService,
+	}
+}
@@ -0,0 +1,76 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// statsServiceFunc lets a function act as the stats service, the breakpoints
+// and views are found for the characters it has stats for.
+type statsServiceFunc func(ctx context.Context, name string) (*domain.EffectiveStats, error)
+
+func (f statsServiceFunc) EffectiveStats(ctx context.Context, name string) (*domain.EffectiveStats, error) {
+	return f(ctx, name)
+}
+
+func (f statsServiceFunc) Breakpoints(ctx context.Context, name string) (*domain.Breakpoints, error) {
+	stats, err := f(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Breakpoints{Character: stats.Character}, nil
+}
+
+func (f statsServiceFunc) View(ctx context.Context, name string) (*domain.CharacterView, error) {
+	stats, err := f(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CharacterView{Character: stats.Character}, nil
+}
+
+func TestStatsHandler(t *testing.T) {
+	service := statsServiceFunc(func(ctx context.Context, name string) (*domain.EffectiveStats, error) {
+		switch name {
+		case "nokka":
+			return &domain.EffectiveStats{Character: name}, nil
+		case "deleted":
+			return nil, fmt.Errorf("character was deleted: %w", domain.ErrGone)
+		}
+
+		return nil, fmt.Errorf("character not found: %w", domain.ErrNotFound)
+	})
+
+	srv := NewServer(":80", staticCharacterService{}, nil, nil, false, false, WithStatsService(service))
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want int
+	}{
+		{"effective stats", "/api/v1/characters/nokka/effective-stats", http.StatusOK},
+		{"deleted character", "/api/v1/characters/deleted/effective-stats", http.StatusGone},
+		{"unknown character", "/api/v1/characters/unknown/effective-stats", http.StatusNotFound},
+		{"breakpoints", "/api/v1/characters/nokka/breakpoints", http.StatusOK},
+		{"breakpoints of deleted character", "/api/v1/characters/deleted/breakpoints", http.StatusGone},
+		{"view", "/api/v1/characters/nokka/view", http.StatusOK},
+		{"view of unknown character", "/api/v1/characters/unknown/view", http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// maxUploadSize is the largest request body accepted for an upload, a d2s
+// binary is only a few kilobytes even for a character with full stashes.
+const maxUploadSize = 256 << 10
+
+// uploadService represents the functionality we need to parse uploaded binaries.
+type uploadService interface {
+	// ParseUpload parses an uploaded character binary, persisting it if store is set.
+	ParseUpload(ctx context.Context, data []byte, store bool) (*domain.Character, error)
+}
+
+// uploadHandler is used to parse characters that don't live in the d2s directory.
+type uploadHandler struct {
+	encoder       *encoder
+	uploadService uploadService
+	credentials   map[string]string
+}
+
+func (h uploadHandler) Routes(router chi.Router) {
+	router.Post("/", h.postUpload)
+}
+
+func (h uploadHandler) postUpload(w http.ResponseWriter, r *http.Request) {
+	store, _ := strconv.ParseBool(r.URL.Query().Get("store"))
+
+	// Parsing is open to anyone, but storing the character requires authentication.
+	if store {
+		middleware.BasicAuth("armory", h.credentials)(http.HandlerFunc(h.parseUpload)).ServeHTTP(w, r)
+		return
+	}
+
+	h.parseUpload(w, r)
+}
+
+func (h uploadHandler) parseUpload(w http.ResponseWriter, r *http.Request) {
+	store, _ := strconv.ParseBool(r.URL.Query().Get("store"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	data, err := readUpload(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.encoder.StatusResponse(w, errorResponse{
+				Error: fmt.Sprintf("upload exceeds %d bytes", maxUploadSize),
+			}, http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// Pass the request context in order to make use of cancellation for lower level work.
+	char, err := h.uploadService.ParseUpload(r.Context(), data, store)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.Response(w, struct {
+		Character *domain.Character `json:"character"`
+	}{
+		Character: char,
+	})
+}
+
+// readUpload reads the binary from either a multipart form with a file field
+// or a raw octet stream body.
+func readUpload(r *http.Request) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type: %w", domain.ErrRequest)
+	}
+
+	switch mediaType {
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("multipart upload requires a file field: %w", domain.ErrRequest)
+		}
+		defer file.Close()
+
+		return io.ReadAll(file)
+	case "application/octet-stream":
+		return io.ReadAll(r.Body)
+	default:
+		return nil, fmt.Errorf("unsupported content type %s: %w", mediaType, domain.ErrRequest)
+	}
+}
+
+func newUploadHandler(encoder *encoder, uploadService uploadService, credentials map[string]string) *uploadHandler {
+	return &uploadHandler{
+		encoder:       encoder,
+		uploadService: uploadService,
+		credentials:   credentials,
+	}
+}
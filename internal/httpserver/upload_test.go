@@ -0,0 +1,90 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// recordingUploadService parses every upload into the same character and
+// records if it was asked to store it.
+type recordingUploadService struct {
+	stored bool
+}
+
+func (s *recordingUploadService) ParseUpload(ctx context.Context, data []byte, store bool) (*domain.Character, error) {
+	if string(data) != "d2s" {
+		return nil, fmt.Errorf("binary parse error: %w", domain.ErrRequest)
+	}
+
+	s.stored = store
+
+	return &domain.Character{ID: "nokka", Uploaded: true}, nil
+}
+
+func TestUploadHandler(t *testing.T) {
+	multipartBody := func(content []byte) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "nokka.d2s")
+		_, _ = part.Write(content)
+		_ = writer.Close()
+
+		return body, writer.FormDataContentType()
+	}
+
+	for _, tt := range []struct {
+		name        string
+		query       string
+		content     []byte
+		multipart   bool
+		contentType string
+		auth        bool
+		want        int
+		wantStored  bool
+	}{
+		{name: "octet stream", content: []byte("d2s"), contentType: "application/octet-stream", want: http.StatusOK},
+		{name: "multipart", content: []byte("d2s"), multipart: true, want: http.StatusOK},
+		{name: "malformed binary", content: []byte("d3s"), contentType: "application/octet-stream", want: http.StatusBadRequest},
+		{name: "unsupported content type", content: []byte("d2s"), contentType: "text/plain", want: http.StatusBadRequest},
+		{name: "too large", content: make([]byte, maxUploadSize+1), contentType: "application/octet-stream", want: http.StatusRequestEntityTooLarge},
+		{name: "too large multipart", content: make([]byte, maxUploadSize+1), multipart: true, want: http.StatusRequestEntityTooLarge},
+		{name: "store without credentials", query: "?store=true", content: []byte("d2s"), contentType: "application/octet-stream", want: http.StatusUnauthorized},
+		{name: "store with credentials", query: "?store=true", content: []byte("d2s"), contentType: "application/octet-stream", auth: true, want: http.StatusOK, wantStored: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &recordingUploadService{}
+			srv := NewServer(":80", nil, nil, map[string]string{"admin": "secret"}, false, false,
+				WithUploadService(service),
+			)
+
+			body, contentType := bytes.NewBuffer(tt.content), tt.contentType
+			if tt.multipart {
+				body, contentType = multipartBody(tt.content)
+			}
+
+			req := httptest.NewRequest("POST", "/api/v1/parse"+tt.query, body)
+			req.Header.Set("Content-Type", contentType)
+			if tt.auth {
+				req.SetBasicAuth("admin", "secret")
+			}
+
+			recorder := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.want {
+				t.Errorf("want status %d, got = %d", tt.want, recorder.Code)
+			}
+
+			if service.stored != tt.wantStored {
+				t.Errorf("want stored %t, got = %t", tt.wantStored, service.stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package item
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+//go:generate moq -out ./service_mocks.go . itemRepository characterRepository
+
+// Default and max number of items returned in a page.
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// attributeAliases maps the names attribute filters can use to the id of the attribute.
+var attributeAliases = map[string]uint64{
+	"str":    0,
+	"energy": 1,
+	"dex":    2,
+	"vit":    3,
+	"life":   7,
+	"mana":   9,
+	"ed":     17,
+	"def":    31,
+	"fres":   39,
+	"lres":   41,
+	"cres":   43,
+	"pres":   45,
+	"mf":     80,
+	"ias":    93,
+	"frw":    96,
+	"fhr":    99,
+	"fcr":    105,
+	"skills": 127,
+}
+
+// operators are the comparisons attribute filters can use.
+var operators = map[string]struct{}{
+	"=":  {},
+	">":  {},
+	">=": {},
+	"<":  {},
+	"<=": {},
+}
+
+// itemRepository is the interface representation of the data layer
+// the service depend on.
+type itemRepository interface {
+	Replace(ctx context.Context, character string, items []domain.IndexedItem) error
+	Search(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
+	Indexed(ctx context.Context, character string) (bool, error)
+}
+
+// characterRepository is the interface representation of the stored characters
+// the service backfills the index from.
+type characterRepository interface {
+	Find(ctx context.Context, id string) (*domain.Character, error)
+	AllNames(ctx context.Context) ([]string, error)
+}
+
+// Service keeps an index of the items of all characters of a realm.
+type Service struct {
+	repository itemRepository
+	characters characterRepository
+}
+
+// Option is used to configure optional behaviour of the service.
+type Option func(s *Service)
+
+// WithCharacterRepository enables backfilling the index from the stored characters.
+func WithCharacterRepository(characters characterRepository) Option {
+	return func(s *Service) {
+		s.characters = characters
+	}
+}
+
+// CharacterParsed indexes the items of the character when it changed since it was last parsed.
+func (s Service) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if current == nil || current.D2s == nil {
+		return
+	}
+
+	if previous != nil && previous.Fingerprint.Hash == current.Fingerprint.Hash {
+		return
+	}
+
+	if err := s.repository.Replace(ctx, current.ID, Index(current)); err != nil {
+		log.Printf("failed to index items of character %s: %v", current.ID, err)
+	}
+}
+
+// CharacterDeleted removes the items of a deleted character from the index.
+func (s Service) CharacterDeleted(ctx context.Context, name string) {
+	if err := s.repository.Replace(ctx, name, nil); err != nil {
+		log.Printf("failed to remove indexed items of character %s: %v", name, err)
+	}
+}
+
+// Backfill indexes the items of every stored character that isn't in the index
+// yet, since characters are only indexed when they're parsed with changes. It
+// returns the number of characters it indexed.
+func (s Service) Backfill(ctx context.Context) (int, error) {
+	if s.characters == nil {
+		return 0, nil
+	}
+
+	names, err := s.characters.AllNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var indexed int
+
+	for _, name := range names {
+		ok, err := s.repository.Indexed(ctx, name)
+		if err != nil {
+			return indexed, err
+		}
+
+		if ok {
+			continue
+		}
+
+		c, err := s.characters.Find(ctx, name)
+		if err != nil {
+			log.Printf("failed to find character %s to index: %v", name, err)
+			continue
+		}
+
+		if c.DeletedAt != nil || c.D2s == nil {
+			continue
+		}
+
+		if err := s.repository.Replace(ctx, name, Index(c)); err != nil {
+			return indexed, err
+		}
+
+		indexed++
+	}
+
+	return indexed, nil
+}
+
+// Search returns a page of the items matching the query.
+func (s Service) Search(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	if query.Quality != "" {
+		query.Quality = strings.ToLower(query.Quality)
+		if _, ok := domain.ItemQualities[query.Quality]; !ok {
+			return nil, fmt.Errorf("unknown quality %s: %w", query.Quality, domain.ErrRequest)
+		}
+	}
+
+	for i, attribute := range query.Attributes {
+		if _, ok := operators[attribute.Operator]; !ok {
+			return nil, fmt.Errorf("invalid operator %s: %w", attribute.Operator, domain.ErrRequest)
+		}
+
+		id, ok := attributeAliases[strings.ToLower(attribute.Key)]
+		if !ok {
+			var err error
+			if id, err = strconv.ParseUint(attribute.Key, 10, 64); err != nil {
+				return nil, fmt.Errorf("unknown attribute %s: %w", attribute.Key, domain.ErrRequest)
+			}
+		}
+		query.Attributes[i].ID = id
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultLimit
+	case query.Limit > maxLimit:
+		query.Limit = maxLimit
+	}
+
+	return s.repository.Search(ctx, query)
+}
+
+// Index returns the items of the character the way they're kept in the index.
+func Index(character *domain.Character) []domain.IndexedItem {
+	items := make([]domain.IndexedItem, 0, len(character.D2s.Items)+len(character.D2s.CorpseItems)+len(character.D2s.MercItems))
+
+	for _, item := range character.D2s.Items {
+		items = append(items, indexed(character, item, domain.ItemLocation(item)))
+	}
+
+	for _, item := range character.D2s.CorpseItems {
+		items = append(items, indexed(character, item, domain.LocationCorpse))
+	}
+
+	for _, item := range character.D2s.MercItems {
+		items = append(items, indexed(character, item, domain.LocationMercenary))
+	}
+
+	return items
+}
+
+func indexed(character *domain.Character, item d2s.Item, location string) domain.IndexedItem {
+	indexed := domain.IndexedItem{
+		Character:  character.ID,
+		Realm:      character.Realm,
+		Location:   location,
+		ItemID:     item.ID,
+		Name:       domain.ItemName(item),
+		Type:       item.Type,
+		TypeName:   item.TypeName,
+		Quality:    item.Quality,
+		SetName:    item.SetName,
+		UniqueName: item.UniqueName,
+		Runeword:   item.RunewordName,
+		Ethereal:   item.Ethereal == 1,
+		Sockets:    item.TotalNrOfSockets,
+		Attributes: []domain.ItemAttribute{},
+	}
+
+	// The attributes of a runeword are kept apart from the magic attributes of its base.
+	for _, attribute := range item.MagicAttributes {
+		indexed.Attributes = append(indexed.Attributes, itemAttribute(attribute.ID, attribute.Name, attribute.Values))
+	}
+
+	for _, attribute := range item.RunewordAttributes {
+		indexed.Attributes = append(indexed.Attributes, itemAttribute(attribute.ID, attribute.Name, attribute.Values))
+	}
+
+	return indexed
+}
+
+// itemAttribute returns the attribute as it's kept in the index.
+func itemAttribute(id uint64, name string, values []int64) domain.ItemAttribute {
+	attribute := domain.ItemAttribute{
+		ID:     id,
+		Name:   name,
+		Values: values,
+	}
+
+	if len(values) > 0 {
+		attribute.Value = values[len(values)-1]
+	}
+
+	return attribute
+}
+
+// NewService constructs a new item service with all the dependencies.
+func NewService(repository itemRepository, opts ...Option) *Service {
+	s := &Service{
+		repository: repository,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
@@ -0,0 +1,227 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// enigma is a runeword, the attributes of its base and of the runeword are
+// decoded from JSON since the attribute type of d2s isn't exported.
+func enigma(t *testing.T) d2s.Item {
+	var item d2s.Item
+
+	err := json.Unmarshal([]byte(`{
+		"id": 1337,
+		"type": "xtp",
+		"type_name": "Mage Plate",
+		"quality": 2,
+		"location_id": 1,
+		"total_nr_of_sockets": 3,
+		"runeword_name": "Enigma",
+		"magic_attributes": [{"id": 16, "name": "+{0}% Enhanced Defense", "values": [15]}],
+		"runeword_attributes": [{"id": 127, "name": "+{0} to All Skill Levels", "values": [2]}, {"id": 97, "name": "+{1} To {0}", "values": [54, 1]}]
+	}`), &item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return item
+}
+
+func TestIndex(t *testing.T) {
+	character := &domain.Character{
+		ID:    "nokka",
+		Realm: "default",
+		D2s: &d2s.Character{
+			Items:     []d2s.Item{enigma(t)},
+			MercItems: []d2s.Item{{ID: 42, Type: "uap", TypeName: "Shako", UniqueName: "Harlequin Crest", Quality: domain.ItemQualityUnique, Ethereal: 1}},
+		},
+	}
+
+	items := Index(character)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got = %d", len(items))
+	}
+
+	armor := items[0]
+	if armor.Name != "Enigma" || armor.Runeword != "Enigma" || armor.TypeName != "Mage Plate" || armor.Sockets != 3 || armor.Location != domain.LocationEquipped {
+		t.Errorf("unexpected indexed runeword, got = %+v", armor)
+	}
+
+	if len(armor.Attributes) != 3 || armor.Attributes[1].ID != 127 || armor.Attributes[1].Value != 2 || armor.Attributes[2].Value != 1 {
+		t.Errorf("expected the magic and runeword attributes with their amount, got = %+v", armor.Attributes)
+	}
+
+	shako := items[1]
+	if shako.Name != "Harlequin Crest" || !shako.Ethereal || shako.Location != domain.LocationMercenary || shako.Character != "nokka" {
+		t.Errorf("unexpected indexed mercenary item, got = %+v", shako)
+	}
+}
+
+func TestCharacterParsed(t *testing.T) {
+	character := func(hash string) *domain.Character {
+		return &domain.Character{
+			ID:          "nokka",
+			D2s:         &d2s.Character{Items: []d2s.Item{enigma(t)}},
+			Fingerprint: domain.Fingerprint{Hash: hash},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		previous     *domain.Character
+		current      *domain.Character
+		replaceCalls int
+	}{
+		{"new character", nil, character("c0ffee"), 1},
+		{"changed character", character("c0ffee"), character("b4d1c0de"), 1},
+		{"unchanged character", character("c0ffee"), character("c0ffee"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &itemRepositoryMock{
+				ReplaceFunc: func(ctx context.Context, character string, items []domain.IndexedItem) error {
+					return nil
+				},
+			}
+
+			NewService(repository).CharacterParsed(context.TODO(), tt.previous, tt.current)
+
+			if len(repository.ReplaceCalls()) != tt.replaceCalls {
+				t.Errorf("expected itemRepository.Replace() to be called exactly %d times but was called %d times", tt.replaceCalls, len(repository.ReplaceCalls()))
+			}
+		})
+	}
+}
+
+func TestCharacterDeleted(t *testing.T) {
+	repository := &itemRepositoryMock{
+		ReplaceFunc: func(ctx context.Context, character string, items []domain.IndexedItem) error {
+			return nil
+		},
+	}
+
+	NewService(repository).CharacterDeleted(context.TODO(), "nokka")
+
+	calls := repository.ReplaceCalls()
+	if len(calls) != 1 || calls[0].Character != "nokka" || len(calls[0].Items) != 0 {
+		t.Errorf("expected the items of the deleted character to be removed, got = %+v", calls)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	deletedAt := time.Now()
+
+	stored := map[string]*domain.Character{
+		"nokka":   {ID: "nokka", D2s: &d2s.Character{Items: []d2s.Item{enigma(t)}}},
+		"wheelz":  {ID: "wheelz", D2s: &d2s.Character{}},
+		"meanski": {ID: "meanski", D2s: &d2s.Character{}, DeletedAt: &deletedAt},
+		"upped":   {ID: "upped", D2s: &d2s.Character{Items: []d2s.Item{enigma(t)}}, Uploaded: true},
+	}
+
+	repository := &itemRepositoryMock{
+		IndexedFunc: func(ctx context.Context, character string) (bool, error) {
+			return character == "wheelz", nil
+		},
+		ReplaceFunc: func(ctx context.Context, character string, items []domain.IndexedItem) error {
+			return nil
+		},
+	}
+
+	characters := &characterRepositoryMock{
+		AllNamesFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"nokka", "wheelz", "meanski", "atomic", "upped"}, nil
+		},
+		FindFunc: func(ctx context.Context, id string) (*domain.Character, error) {
+			if c, ok := stored[id]; ok {
+				return c, nil
+			}
+			return nil, domain.ErrNotFound
+		},
+	}
+
+	indexed, err := NewService(repository, WithCharacterRepository(characters)).Backfill(context.TODO())
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	// Indexed and deleted characters are skipped, and a character that can't be found doesn't stop the backfill.
+	calls := repository.ReplaceCalls()
+	if indexed != 2 || len(calls) != 2 || calls[0].Character != "nokka" || calls[1].Character != "upped" || len(calls[1].Items) != 1 {
+		t.Errorf("expected the items of nokka and of the uploaded upped to be indexed, got = %d, %+v", indexed, calls)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         domain.ItemQuery
+		expectedQuery domain.ItemQuery
+		expectedError error
+	}{
+		{
+			name:          "defaults are applied",
+			expectedQuery: domain.ItemQuery{Limit: defaultLimit},
+		},
+		{
+			name: "attribute aliases and ids are resolved",
+			query: domain.ItemQuery{
+				Quality: "Unique",
+				Attributes: []domain.AttributeFilter{
+					{Key: "skills", Operator: ">=", Value: 2},
+					{Key: "105", Operator: ">", Value: 20},
+				},
+				Limit: 500,
+			},
+			expectedQuery: domain.ItemQuery{
+				Quality: "unique",
+				Attributes: []domain.AttributeFilter{
+					{Key: "skills", ID: 127, Operator: ">=", Value: 2},
+					{Key: "105", ID: 105, Operator: ">", Value: 20},
+				},
+				Limit: maxLimit,
+			},
+		},
+		{
+			name:          "unknown quality",
+			query:         domain.ItemQuery{Quality: "legendary"},
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "unknown attribute",
+			query:         domain.ItemQuery{Attributes: []domain.AttributeFilter{{Key: "awesomeness", Operator: ">", Value: 1}}},
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &itemRepositoryMock{
+				SearchFunc: func(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+					return &domain.ItemPage{}, nil
+				},
+			}
+
+			_, err := NewService(repository).Search(context.TODO(), tt.query)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				return
+			}
+
+			if got := repository.SearchCalls()[0].Query; !reflect.DeepEqual(got, tt.expectedQuery) {
+				t.Errorf("expected query %+v, got = %+v", tt.expectedQuery, got)
+			}
+		})
+	}
+}
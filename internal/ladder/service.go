@@ -0,0 +1,114 @@
+package ladder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./service_mocks.go . ladderRepository
+
+// Default and max number of characters returned in a page.
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// dayLayout is the layout of the days the standings are kept for.
+const dayLayout = "2006-01-02"
+
+// ladderRepository is the interface representation of the data layer
+// the service depend on.
+type ladderRepository interface {
+	Standings(ctx context.Context, query domain.LadderQuery) ([]domain.LadderEntry, int64, error)
+	PreviousRanks(ctx context.Context, before string, query domain.LadderQuery, characters []string) (map[string]int, error)
+	Snapshot(ctx context.Context, day string) error
+}
+
+// Service ranks the characters of a realm by level and experience.
+type Service struct {
+	repository ladderRepository
+	now        func() time.Time
+}
+
+// List returns a page of the ladder, with the rank each character had at the
+// end of the previous day the standings were kept for.
+func (s Service) List(ctx context.Context, query domain.LadderQuery) (*domain.LadderPage, error) {
+	if query.Class != "" {
+		query.Class = strings.ToLower(query.Class)
+		if _, ok := domain.CharacterClasses[query.Class]; !ok {
+			return nil, fmt.Errorf("unknown class %s: %w", query.Class, domain.ErrRequest)
+		}
+	}
+
+	if query.Cursor != "" {
+		offset, err := strconv.Atoi(query.Cursor)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid cursor: %w", domain.ErrRequest)
+		}
+		query.Offset = offset
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultLimit
+	case query.Limit > maxLimit:
+		query.Limit = maxLimit
+	}
+
+	entries, total, err := s.repository.Standings(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Character.Name)
+	}
+
+	// Compare with the last standings kept before midnight.
+	ranks, err := s.repository.PreviousRanks(ctx, s.now().UTC().Format(dayLayout), query, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		previous, ok := ranks[entry.Character.Name]
+		if !ok {
+			continue
+		}
+
+		change := previous - entry.Rank
+		entries[i].PreviousRank = &previous
+		entries[i].RankChange = &change
+	}
+
+	page := &domain.LadderPage{
+		Entries: entries,
+		Total:   total,
+	}
+
+	if next := query.Offset + len(entries); len(entries) > 0 && int64(next) < total {
+		page.NextCursor = strconv.Itoa(next)
+	}
+
+	return page, nil
+}
+
+// Snapshot stores the standings of the current day, every snapshot replaces the
+// earlier one of the day so the last one before midnight is kept.
+func (s Service) Snapshot(ctx context.Context) error {
+	return s.repository.Snapshot(ctx, s.now().UTC().Format(dayLayout))
+}
+
+// NewService constructs a new ladder service with all the dependencies.
+func NewService(repository ladderRepository) *Service {
+	return &Service{
+		repository: repository,
+		now:        time.Now,
+	}
+}
@@ -0,0 +1,90 @@
+package ladder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestList(t *testing.T) {
+	standings := []domain.LadderEntry{
+		{Rank: 21, Character: domain.CharacterSummary{Name: "nokka", Level: 92}},
+		{Rank: 22, Character: domain.CharacterSummary{Name: "wheelz", Level: 91}},
+		{Rank: 23, Character: domain.CharacterSummary{Name: "newcomer", Level: 90}},
+	}
+
+	repository := &ladderRepositoryMock{
+		StandingsFunc: func(ctx context.Context, query domain.LadderQuery) ([]domain.LadderEntry, int64, error) {
+			return append([]domain.LadderEntry(nil), standings...), 50, nil
+		},
+		PreviousRanksFunc: func(ctx context.Context, before string, query domain.LadderQuery, characters []string) (map[string]int, error) {
+			return map[string]int{"nokka": 25, "wheelz": 20}, nil
+		},
+	}
+
+	s := NewService(repository)
+	s.now = func() time.Time {
+		return time.Date(2021, 1, 8, 23, 0, 0, 0, time.UTC)
+	}
+
+	page, err := s.List(context.TODO(), domain.LadderQuery{Class: "Sorceress", Cursor: "20", Limit: 3})
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	query := repository.StandingsCalls()[0].Query
+	if query.Class != "sorceress" || query.Offset != 20 || query.Limit != 3 {
+		t.Errorf("unexpected query, got = %+v", query)
+	}
+
+	// The ranks of the whole page are looked up at once.
+	calls := repository.PreviousRanksCalls()
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].Characters, []string{"nokka", "wheelz", "newcomer"}) {
+		t.Fatalf("expected the previous ranks of the page to be looked up once, got = %+v", calls)
+	}
+
+	if before := calls[0].Before; before != "2021-01-08" {
+		t.Errorf("expected the last standings before the start of the day, got = %s", before)
+	}
+
+	if change := page.Entries[0].RankChange; change == nil || *change != 4 {
+		t.Errorf("expected a character that climbed to have a positive change, got = %v", change)
+	}
+
+	if change := page.Entries[1].RankChange; change == nil || *change != -2 {
+		t.Errorf("expected a character that dropped to have a negative change, got = %v", change)
+	}
+
+	if page.Entries[2].PreviousRank != nil || page.Entries[2].RankChange != nil {
+		t.Errorf("expected no previous rank for a new character, got = %+v", page.Entries[2])
+	}
+
+	if page.Total != 50 || page.NextCursor != "23" {
+		t.Errorf("expected the next page to start after the last entry, got = %s", page.NextCursor)
+	}
+}
+
+func TestListValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query domain.LadderQuery
+	}{
+		{"unknown class", domain.LadderQuery{Class: "warlock"}},
+		{"invalid cursor", domain.LadderQuery{Cursor: "abc"}},
+		{"negative cursor", domain.LadderQuery{Cursor: "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(&ladderRepositoryMock{})
+
+			if _, err := s.List(context.TODO(), tt.query); !errors.Is(err, domain.ErrRequest) {
+				t.Errorf("expected a request error, got = %v", err)
+			}
+		})
+	}
+}
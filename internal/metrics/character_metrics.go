@@ -13,7 +13,7 @@ var (
 			Name: "d2_character_level",
 			Help: "Current level of the character",
 		},
-		[]string{"character", "class", "hardcore"},
+		[]string{"realm", "character", "class", "hardcore"},
 	)
 
 	CharacterExperience = promauto.NewGaugeVec(
@@ -21,7 +21,7 @@ var (
 			Name: "d2_character_experience",
 			Help: "Current experience of the character",
 		},
-		[]string{"character", "class"},
+		[]string{"realm", "character", "class"},
 	)
 
 	CharacterGold = promauto.NewGaugeVec(
@@ -29,7 +29,7 @@ var (
 			Name: "d2_character_gold",
 			Help: "Current gold of the character",
 		},
-		[]string{"character", "location"},
+		[]string{"realm", "character", "location"},
 	)
 
 	CharacterStats = promauto.NewGaugeVec(
@@ -37,7 +37,7 @@ var (
 			Name: "d2_character_stats",
 			Help: "Character base stats",
 		},
-		[]string{"character", "stat"},
+		[]string{"realm", "character", "stat"},
 	)
 
 	CharacterHP = promauto.NewGaugeVec(
@@ -45,7 +45,7 @@ var (
 			Name: "d2_character_hp",
 			Help: "Character hit points",
 		},
-		[]string{"character", "type"}, // type: "current" or "max"
+		[]string{"realm", "character", "type"}, // type: "current" or "max"
 	)
 
 	CharacterMana = promauto.NewGaugeVec(
@@ -53,7 +53,7 @@ var (
 			Name: "d2_character_mana",
 			Help: "Character mana",
 		},
-		[]string{"character", "type"}, // type: "current" or "max"
+		[]string{"realm", "character", "type"}, // type: "current" or "max"
 	)
 
 	CharacterStamina = promauto.NewGaugeVec(
@@ -61,7 +61,7 @@ var (
 			Name: "d2_character_stamina",
 			Help: "Character stamina",
 		},
-		[]string{"character", "type"}, // type: "current" or "max"
+		[]string{"realm", "character", "type"}, // type: "current" or "max"
 	)
 
 	CharacterUnusedPoints = promauto.NewGaugeVec(
@@ -69,7 +69,7 @@ var (
 			Name: "d2_character_unused_points",
 			Help: "Unused stat and skill points",
 		},
-		[]string{"character", "type"}, // type: "stats" or "skills"
+		[]string{"realm", "character", "type"}, // type: "stats" or "skills"
 	)
 
 	CharacterSocketedItemCount = promauto.NewGaugeVec(
@@ -77,7 +77,7 @@ var (
 			Name: "d2_character_socketed_item_count",
 			Help: "Number of items with sockets",
 		},
-		[]string{"character"},
+		[]string{"realm", "character"},
 	)
 
 	CharacterItemCount = promauto.NewGaugeVec(
@@ -85,7 +85,7 @@ var (
 			Name: "d2_character_item_count",
 			Help: "Number of items by location",
 		},
-		[]string{"character", "location"}, // location: "inventory", "corpse", "merc"
+		[]string{"realm", "character", "location"}, // location: "inventory", "corpse", "merc"
 	)
 
 	CharacterParsesTotal = promauto.NewCounterVec(
@@ -93,7 +93,31 @@ var (
 			Name: "d2_character_parses_total",
 			Help: "Total number of character parses",
 		},
-		[]string{"character", "status"},
+		[]string{"realm", "character", "status"},
+	)
+
+	CharacterParsesCoalescedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "d2_character_parses_coalesced_total",
+			Help: "Total number of character parses that shared the result of a parse already in flight",
+		},
+		[]string{"realm", "character"},
+	)
+
+	CharacterParseFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "d2_character_parse_failures_total",
+			Help: "Total number of attempts to decode a character binary that failed",
+		},
+		[]string{"realm", "character"},
+	)
+
+	CharacterConsecutiveParseFailures = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "d2_character_consecutive_parse_failures",
+			Help: "Number of failed attempts to decode a character binary since it last decoded, a persistently corrupt binary keeps growing",
+		},
+		[]string{"realm", "character"},
 	)
 
 	CharacterLastParsed = promauto.NewGaugeVec(
@@ -101,7 +125,7 @@ var (
 			Name: "d2_character_last_parsed_timestamp",
 			Help: "Unix timestamp of when character was last parsed",
 		},
-		[]string{"character"},
+		[]string{"realm", "character"},
 	)
 
 	CharacterIsDead = promauto.NewGaugeVec(
@@ -109,12 +133,28 @@ var (
 			Name: "d2_character_is_dead",
 			Help: "Whether character is currently dead (1) or alive (0)",
 		},
-		[]string{"character"},
+		[]string{"realm", "character"},
+	)
+
+	CharacterExperienceRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "d2_character_experience_per_hour",
+			Help: "Experience gained per hour over a window of time",
+		},
+		[]string{"realm", "character", "window"},
+	)
+
+	CharacterTimeToLevel = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "d2_character_time_to_level_seconds",
+			Help: "Estimated seconds until the character reaches a level at its current rate",
+		},
+		[]string{"realm", "character", "target"}, // target: "next" or "99"
 	)
 )
 
 // UpdateCharacterMetrics updates all character-related metrics
-func UpdateCharacterMetrics(char *domain.Character) {
+func UpdateCharacterMetrics(realm string, char *domain.Character) {
 	if char == nil || char.D2s == nil {
 		return
 	}
@@ -129,40 +169,40 @@ func UpdateCharacterMetrics(char *domain.Character) {
 	// The Status type might have a method or field to check this
 
 	// Basic character info
-	CharacterLevel.WithLabelValues(charName, className, isHardcore).Set(float64(d2sChar.Header.Level))
-	CharacterLastParsed.WithLabelValues(charName).Set(float64(char.LastParsed.Unix()))
+	CharacterLevel.WithLabelValues(realm, charName, className, isHardcore).Set(float64(d2sChar.Header.Level))
+	CharacterLastParsed.WithLabelValues(realm, charName).Set(float64(char.LastParsed.Unix()))
 
 	// Experience and Gold from Attributes
-	CharacterExperience.WithLabelValues(charName, className).Set(float64(d2sChar.Attributes.Experience))
-	CharacterGold.WithLabelValues(charName, "inventory").Set(float64(d2sChar.Attributes.Gold))
-	CharacterGold.WithLabelValues(charName, "stash").Set(float64(d2sChar.Attributes.StashedGold))
+	CharacterExperience.WithLabelValues(realm, charName, className).Set(float64(d2sChar.Attributes.Experience))
+	CharacterGold.WithLabelValues(realm, charName, "inventory").Set(float64(d2sChar.Attributes.Gold))
+	CharacterGold.WithLabelValues(realm, charName, "stash").Set(float64(d2sChar.Attributes.StashedGold))
 
 	// Base Stats
-	CharacterStats.WithLabelValues(charName, "strength").Set(float64(d2sChar.Attributes.Strength))
-	CharacterStats.WithLabelValues(charName, "dexterity").Set(float64(d2sChar.Attributes.Dexterity))
-	CharacterStats.WithLabelValues(charName, "vitality").Set(float64(d2sChar.Attributes.Vitality))
-	CharacterStats.WithLabelValues(charName, "energy").Set(float64(d2sChar.Attributes.Energy))
+	CharacterStats.WithLabelValues(realm, charName, "strength").Set(float64(d2sChar.Attributes.Strength))
+	CharacterStats.WithLabelValues(realm, charName, "dexterity").Set(float64(d2sChar.Attributes.Dexterity))
+	CharacterStats.WithLabelValues(realm, charName, "vitality").Set(float64(d2sChar.Attributes.Vitality))
+	CharacterStats.WithLabelValues(realm, charName, "energy").Set(float64(d2sChar.Attributes.Energy))
 
 	// HP, Mana, Stamina
-	CharacterHP.WithLabelValues(charName, "current").Set(float64(d2sChar.Attributes.CurrentHP))
-	CharacterHP.WithLabelValues(charName, "max").Set(float64(d2sChar.Attributes.MaxHP))
-	CharacterMana.WithLabelValues(charName, "current").Set(float64(d2sChar.Attributes.CurrentMana))
-	CharacterMana.WithLabelValues(charName, "max").Set(float64(d2sChar.Attributes.MaxMana))
-	CharacterStamina.WithLabelValues(charName, "current").Set(float64(d2sChar.Attributes.CurrentStamina))
-	CharacterStamina.WithLabelValues(charName, "max").Set(float64(d2sChar.Attributes.MaxStamina))
+	CharacterHP.WithLabelValues(realm, charName, "current").Set(float64(d2sChar.Attributes.CurrentHP))
+	CharacterHP.WithLabelValues(realm, charName, "max").Set(float64(d2sChar.Attributes.MaxHP))
+	CharacterMana.WithLabelValues(realm, charName, "current").Set(float64(d2sChar.Attributes.CurrentMana))
+	CharacterMana.WithLabelValues(realm, charName, "max").Set(float64(d2sChar.Attributes.MaxMana))
+	CharacterStamina.WithLabelValues(realm, charName, "current").Set(float64(d2sChar.Attributes.CurrentStamina))
+	CharacterStamina.WithLabelValues(realm, charName, "max").Set(float64(d2sChar.Attributes.MaxStamina))
 
 	// Unused points
-	CharacterUnusedPoints.WithLabelValues(charName, "stats").Set(float64(d2sChar.Attributes.UnusedStats))
-	CharacterUnusedPoints.WithLabelValues(charName, "skills").Set(float64(d2sChar.Attributes.UnusedSkillPoints))
+	CharacterUnusedPoints.WithLabelValues(realm, charName, "stats").Set(float64(d2sChar.Attributes.UnusedStats))
+	CharacterUnusedPoints.WithLabelValues(realm, charName, "skills").Set(float64(d2sChar.Attributes.UnusedSkillPoints))
 
 	// Is character dead?
-	CharacterIsDead.WithLabelValues(charName).Set(float64(d2sChar.IsDead))
+	CharacterIsDead.WithLabelValues(realm, charName).Set(float64(d2sChar.IsDead))
 
 	// Item analysis
-	updateItemMetrics(charName, d2sChar)
+	updateItemMetrics(realm, charName, d2sChar)
 }
 
-func updateItemMetrics(charName string, d2sChar *d2s.Character) {
+func updateItemMetrics(realm string, charName string, d2sChar *d2s.Character) {
 	socketedCount := 0
 
 	// Count socketed items in main inventory
@@ -172,8 +212,66 @@ func updateItemMetrics(charName string, d2sChar *d2s.Character) {
 		}
 	}
 
-	CharacterSocketedItemCount.WithLabelValues(charName).Set(float64(socketedCount))
-	CharacterItemCount.WithLabelValues(charName, "inventory").Set(float64(len(d2sChar.Items)))
-	CharacterItemCount.WithLabelValues(charName, "corpse").Set(float64(len(d2sChar.CorpseItems)))
-	CharacterItemCount.WithLabelValues(charName, "merc").Set(float64(len(d2sChar.MercItems)))
+	CharacterSocketedItemCount.WithLabelValues(realm, charName).Set(float64(socketedCount))
+	CharacterItemCount.WithLabelValues(realm, charName, "inventory").Set(float64(len(d2sChar.Items)))
+	CharacterItemCount.WithLabelValues(realm, charName, "corpse").Set(float64(len(d2sChar.CorpseItems)))
+	CharacterItemCount.WithLabelValues(realm, charName, "merc").Set(float64(len(d2sChar.MercItems)))
+}
+
+// UpdateProgressMetrics updates the experience rates and level estimates of a
+// character, estimates that can't be made are removed.
+func UpdateProgressMetrics(realm string, progress *domain.Progress) {
+	if progress == nil {
+		return
+	}
+
+	for _, rate := range progress.Rates {
+		CharacterExperienceRate.WithLabelValues(realm, progress.Character, rate.Window).Set(rate.PerHour)
+	}
+
+	for target, estimate := range map[string]*domain.LevelEstimate{
+		"next": progress.NextLevel,
+		"99":   progress.MaxLevel,
+	} {
+		if estimate == nil || estimate.Seconds == nil {
+			CharacterTimeToLevel.DeleteLabelValues(realm, progress.Character, target)
+			continue
+		}
+
+		CharacterTimeToLevel.WithLabelValues(realm, progress.Character, target).Set(float64(*estimate.Seconds))
+	}
+}
+
+// partialDeleter is implemented by every metric vector labelled by character.
+type partialDeleter interface {
+	DeletePartialMatch(labels prometheus.Labels) int
+}
+
+// RemoveCharacterMetrics removes every series of the character in the realm, so
+// a character that no longer exists stops being reported.
+func RemoveCharacterMetrics(realm string, charName string) {
+	vectors := []partialDeleter{
+		CharacterLevel,
+		CharacterExperience,
+		CharacterGold,
+		CharacterStats,
+		CharacterHP,
+		CharacterMana,
+		CharacterStamina,
+		CharacterUnusedPoints,
+		CharacterSocketedItemCount,
+		CharacterItemCount,
+		CharacterParsesTotal,
+		CharacterParsesCoalescedTotal,
+		CharacterParseFailuresTotal,
+		CharacterConsecutiveParseFailures,
+		CharacterLastParsed,
+		CharacterIsDead,
+		CharacterExperienceRate,
+		CharacterTimeToLevel,
+	}
+
+	for _, vector := range vectors {
+		vector.DeletePartialMatch(prometheus.Labels{"realm": realm, "character": charName})
+	}
 }
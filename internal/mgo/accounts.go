@@ -0,0 +1,60 @@
+package mgo
+
+import (
+	"context"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// accountCollectionName is the name of the collection we'll use for all queries.
+	accountCollectionName = "account"
+)
+
+// AccountRepository handles all operations on the accounts of a realm.
+type AccountRepository struct {
+	db     string
+	realm  string
+	client *mongo.Client
+}
+
+// Find will find an account by name.
+func (r *AccountRepository) Find(ctx context.Context, name string) (*domain.Account, error) {
+	var account domain.Account
+
+	err := r.client.Database(r.db).Collection(accountCollectionName).
+		FindOne(ctx, bson.M{"realm": r.realm, "name": name}).Decode(&account)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	return &account, nil
+}
+
+// Link will add the character to the account, creating the account if it doesn't exist.
+func (r *AccountRepository) Link(ctx context.Context, name string, character string) error {
+	change := bson.M{
+		"$addToSet": bson.M{"characters": character},
+	}
+
+	_, err := r.client.Database(r.db).Collection(accountCollectionName).
+		UpdateOne(ctx, bson.M{"realm": r.realm, "name": name}, change, options.Update().SetUpsert(true))
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// NewAccountRepository returns a new instance of a MongoDB account repository
+// scoped to the given realm.
+func NewAccountRepository(db string, realm string, client *mongo.Client) *AccountRepository {
+	return &AccountRepository{
+		db:     db,
+		realm:  realm,
+		client: client,
+	}
+}
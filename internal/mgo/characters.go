@@ -7,6 +7,7 @@ import (
 	"github.com/nokka/d2-armory-api/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -14,9 +15,10 @@ var (
 	characterCollectionName = "character"
 )
 
-// CharacterRepository handles all operations on characters.
+// CharacterRepository handles all operations on the characters of a realm.
 type CharacterRepository struct {
 	db     string
+	realm  string
 	client *mongo.Client
 }
 
@@ -27,7 +29,7 @@ func (r *CharacterRepository) Find(ctx context.Context, id string) (*domain.Char
 
 	// Find the character by id in the collection.
 	err := r.client.Database(r.db).Collection(characterCollectionName).
-		FindOne(ctx, bson.M{"id": id}).Decode(&char)
+		FindOne(ctx, r.filter(id)).Decode(&char)
 	if err != nil {
 		return nil, mongoErr(err)
 	}
@@ -37,16 +39,22 @@ func (r *CharacterRepository) Find(ctx context.Context, id string) (*domain.Char
 
 // Update will update the given resource.
 func (r *CharacterRepository) Update(ctx context.Context, character *domain.Character) error {
-	// Changeset, update the binary and time of parsing.
+	// Changeset, update the binary, its fingerprint, origin and time of parsing.
 	change := bson.M{
 		"$set": bson.M{
-			"d2s":        character.D2s,
-			"lastparsed": time.Now(),
+			"d2s":         character.D2s,
+			"fingerprint": character.Fingerprint,
+			"uploaded":    character.Uploaded,
+			"lastparsed":  time.Now(),
+		},
+		// A binary to parse means the character exists again.
+		"$unset": bson.M{
+			"deleted_at": "",
 		},
 	}
 
 	_, err := r.client.Database(r.db).Collection(characterCollectionName).
-		UpdateOne(ctx, bson.M{"id": character.ID}, change)
+		UpdateOne(ctx, r.filter(character.ID), change)
 	if err != nil {
 		return mongoErr(err)
 	}
@@ -54,10 +62,72 @@ func (r *CharacterRepository) Update(ctx context.Context, character *domain.Char
 	return nil
 }
 
-// Store will store the new resource.
+// Store will store the new resource, if another instance stored the character
+// before us it's replaced rather than duplicated.
 func (r *CharacterRepository) Store(ctx context.Context, character *domain.Character) error {
+	character.Realm = r.realm
+
+	_, err := r.client.Database(r.db).Collection(characterCollectionName).
+		ReplaceOne(ctx, r.filter(character.ID), character, options.Replace().SetUpsert(true))
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// Names will return the names of all characters parsed from the d2s directory
+// that haven't been deleted.
+func (r *CharacterRepository) Names(ctx context.Context) ([]string, error) {
+	return r.names(ctx, bson.M{
+		"realm":      r.realm,
+		"uploaded":   bson.M{"$ne": true},
+		"deleted_at": bson.M{"$exists": false},
+	})
+}
+
+// AllNames will return the names of all characters that haven't been deleted,
+// including the characters that were uploaded.
+func (r *CharacterRepository) AllNames(ctx context.Context) ([]string, error) {
+	return r.names(ctx, bson.M{
+		"realm":      r.realm,
+		"deleted_at": bson.M{"$exists": false},
+	})
+}
+
+// names will return the names of the characters matching the filter.
+func (r *CharacterRepository) names(ctx context.Context, filter bson.M) ([]string, error) {
+	cur, err := r.client.Database(r.db).Collection(characterCollectionName).
+		Find(ctx, filter, options.Find().SetProjection(bson.M{"id": 1}))
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var docs []struct {
+		ID string `bson:"id"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	names := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		names = append(names, doc.ID)
+	}
+
+	return names, nil
+}
+
+// MarkDeleted will mark the character as deleted at the given time.
+func (r *CharacterRepository) MarkDeleted(ctx context.Context, id string, at time.Time) error {
+	change := bson.M{
+		"$set": bson.M{
+			"deleted_at": at,
+		},
+	}
+
 	_, err := r.client.Database(r.db).Collection(characterCollectionName).
-		InsertOne(ctx, character)
+		UpdateOne(ctx, r.filter(id), change)
 	if err != nil {
 		return mongoErr(err)
 	}
@@ -65,10 +135,35 @@ func (r *CharacterRepository) Store(ctx context.Context, character *domain.Chara
 	return nil
 }
 
-// NewCharacterRepository returns a new instance of a MongoDB character repository.
-func NewCharacterRepository(db string, client *mongo.Client) *CharacterRepository {
+// Touch will set the time the character was last parsed, used when its binary
+// hasn't changed since the stored record was parsed.
+func (r *CharacterRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	change := bson.M{
+		"$set": bson.M{
+			"lastparsed": at,
+		},
+	}
+
+	_, err := r.client.Database(r.db).Collection(characterCollectionName).
+		UpdateOne(ctx, r.filter(id), change)
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// filter matches the character by name within the realm.
+func (r *CharacterRepository) filter(id string) bson.M {
+	return bson.M{"realm": r.realm, "id": id}
+}
+
+// NewCharacterRepository returns a new instance of a MongoDB character repository
+// scoped to the given realm.
+func NewCharacterRepository(db string, realm string, client *mongo.Client) *CharacterRepository {
 	return &CharacterRepository{
 		db:     db,
+		realm:  realm,
 		client: client,
 	}
 }
@@ -4,6 +4,7 @@ package mgo
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -40,7 +41,7 @@ func TestCharacterRepository(t *testing.T) {
 		return
 	}
 
-	characterRepository := NewCharacterRepository("armory", client)
+	characterRepository := NewCharacterRepository("armory", "default", client)
 
 	t.Run("store character", func(t *testing.T) {
 		err := characterRepository.Store(mgoCtx, &domain.Character{
@@ -73,5 +74,16 @@ func TestCharacterRepository(t *testing.T) {
 		if character.ID != "nokka" {
 			t.Error("failed to get character by the ID")
 		}
+
+		if character.Realm != "default" {
+			t.Error("failed to store the realm of the character")
+		}
+	})
+
+	t.Run("find character in another realm", func(t *testing.T) {
+		_, err := NewCharacterRepository("armory", "hardcore", client).Find(mgoCtx, "nokka")
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Error("expected character to be scoped to its realm")
+		}
 	})
 }
@@ -0,0 +1,113 @@
+package mgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// graveyardCollectionName is the name of the collection we'll use for all queries.
+	graveyardCollectionName = "graveyard"
+)
+
+// deathDocument is the stored representation of a death, the object id
+// is used as pagination cursor since it increases with every insert.
+type deathDocument struct {
+	ObjectID     primitive.ObjectID `bson:"_id,omitempty"`
+	domain.Death `bson:",inline"`
+}
+
+// GraveyardRepository handles all operations on the deaths of a realm.
+type GraveyardRepository struct {
+	db     string
+	realm  string
+	client *mongo.Client
+}
+
+// Store will store the new death.
+func (r *GraveyardRepository) Store(ctx context.Context, death *domain.Death) error {
+	death.Realm = r.realm
+
+	_, err := r.client.Database(r.db).Collection(graveyardCollectionName).
+		InsertOne(ctx, deathDocument{Death: *death})
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// List will return a page of deaths, most recent first.
+func (r *GraveyardRepository) List(ctx context.Context, query domain.GraveyardQuery) (*domain.GraveyardPage, error) {
+	filter := bson.M{"realm": r.realm}
+
+	if query.Class != "" {
+		filter["class"] = query.Class
+	}
+
+	level := bson.M{}
+	if query.MinLevel > 0 {
+		level["$gte"] = query.MinLevel
+	}
+	if query.MaxLevel > 0 {
+		level["$lte"] = query.MaxLevel
+	}
+	if len(level) > 0 {
+		filter["level"] = level
+	}
+
+	if query.Cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", domain.ErrRequest)
+		}
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+
+	// Fetch one more than requested to know if there's another page.
+	opts := options.Find().
+		SetSort(bson.M{"_id": -1}).
+		SetLimit(int64(query.Limit + 1))
+
+	cur, err := r.client.Database(r.db).Collection(graveyardCollectionName).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var docs []deathDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	page := &domain.GraveyardPage{
+		Deaths: make([]domain.Death, 0, len(docs)),
+	}
+
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		page.NextCursor = docs[len(docs)-1].ObjectID.Hex()
+	}
+
+	for _, doc := range docs {
+		page.Deaths = append(page.Deaths, doc.Death)
+	}
+
+	return page, nil
+}
+
+// NewGraveyardRepository returns a new instance of a MongoDB graveyard repository
+// scoped to the given realm.
+func NewGraveyardRepository(db string, realm string, client *mongo.Client) *GraveyardRepository {
+	return &GraveyardRepository{
+		db:     db,
+		realm:  realm,
+		client: client,
+	}
+}
@@ -0,0 +1,184 @@
+package mgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// historyCollectionName is the name of the collection we'll use for all queries.
+	historyCollectionName = "character_history"
+)
+
+// snapshotDocument is the stored representation of a snapshot, the object id
+// is used as pagination cursor since it increases with every insert.
+type snapshotDocument struct {
+	ObjectID        primitive.ObjectID `bson:"_id,omitempty"`
+	domain.Snapshot `bson:",inline"`
+}
+
+// HistoryRepository handles all operations on character snapshots of a realm.
+type HistoryRepository struct {
+	db     string
+	realm  string
+	client *mongo.Client
+}
+
+// Latest will find the most recent snapshot of the character.
+func (r *HistoryRepository) Latest(ctx context.Context, character string) (*domain.Snapshot, error) {
+	var doc snapshotDocument
+
+	opts := options.FindOne().SetSort(bson.M{"_id": -1})
+
+	err := r.client.Database(r.db).Collection(historyCollectionName).
+		FindOne(ctx, r.filter(character), opts).Decode(&doc)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	return &doc.Snapshot, nil
+}
+
+// Nearest will find the snapshot of the character that was current at the given
+// time, or the first snapshot if the character didn't exist yet at that time.
+func (r *HistoryRepository) Nearest(ctx context.Context, character string, at time.Time) (*domain.Snapshot, error) {
+	var doc snapshotDocument
+
+	collection := r.client.Database(r.db).Collection(historyCollectionName)
+
+	filter := r.filter(character)
+	filter["parsed_at"] = bson.M{"$lte": at}
+
+	err := collection.FindOne(ctx,
+		filter,
+		options.FindOne().SetSort(bson.M{"parsed_at": -1}),
+	).Decode(&doc)
+	if err == nil {
+		return &doc.Snapshot, nil
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, mongoErr(err)
+	}
+
+	err = collection.FindOne(ctx,
+		r.filter(character),
+		options.FindOne().SetSort(bson.M{"parsed_at": 1}),
+	).Decode(&doc)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	return &doc.Snapshot, nil
+}
+
+// Progress will return the level and experience of every snapshot of the character, oldest first.
+func (r *HistoryRepository) Progress(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+	opts := options.Find().
+		SetSort(bson.M{"parsed_at": 1}).
+		SetProjection(bson.M{"parsed_at": 1, "level": 1, "experience": 1})
+
+	cur, err := r.client.Database(r.db).Collection(historyCollectionName).
+		Find(ctx, r.filter(character), opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var points []domain.ProgressPoint
+	if err := cur.All(ctx, &points); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	return points, nil
+}
+
+// Store will store the new snapshot.
+func (r *HistoryRepository) Store(ctx context.Context, snapshot *domain.Snapshot) error {
+	snapshot.Realm = r.realm
+
+	_, err := r.client.Database(r.db).Collection(historyCollectionName).
+		InsertOne(ctx, snapshotDocument{Snapshot: *snapshot})
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// List will return a page of snapshots of the character, newest first.
+func (r *HistoryRepository) List(ctx context.Context, character string, query domain.HistoryQuery) (*domain.HistoryPage, error) {
+	filter := r.filter(character)
+
+	parsedAt := bson.M{}
+	if !query.From.IsZero() {
+		parsedAt["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		parsedAt["$lte"] = query.To
+	}
+	if len(parsedAt) > 0 {
+		filter["parsed_at"] = parsedAt
+	}
+
+	if query.Cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", domain.ErrRequest)
+		}
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+
+	// Fetch one more than requested to know if there's another page.
+	opts := options.Find().
+		SetSort(bson.M{"_id": -1}).
+		SetLimit(int64(query.Limit + 1))
+
+	cur, err := r.client.Database(r.db).Collection(historyCollectionName).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var docs []snapshotDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	page := &domain.HistoryPage{
+		Snapshots: make([]domain.Snapshot, 0, len(docs)),
+	}
+
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		page.NextCursor = docs[len(docs)-1].ObjectID.Hex()
+	}
+
+	for _, doc := range docs {
+		page.Snapshots = append(page.Snapshots, doc.Snapshot)
+	}
+
+	return page, nil
+}
+
+// filter matches the snapshots of the character within the realm.
+func (r *HistoryRepository) filter(character string) bson.M {
+	return bson.M{"realm": r.realm, "character": character}
+}
+
+// NewHistoryRepository returns a new instance of a MongoDB history repository
+// scoped to the given realm.
+func NewHistoryRepository(db string, realm string, client *mongo.Client) *HistoryRepository {
+	return &HistoryRepository{
+		db:     db,
+		realm:  realm,
+		client: client,
+	}
+}
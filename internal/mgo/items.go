@@ -0,0 +1,218 @@
+package mgo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// itemCollectionName is the name of the collection we'll use for all queries.
+	itemCollectionName = "items"
+)
+
+// attributeOperators maps the operators of attribute filters to their query operator.
+var attributeOperators = map[string]string{
+	"=":  "$eq",
+	">":  "$gt",
+	">=": "$gte",
+	"<":  "$lt",
+	"<=": "$lte",
+}
+
+// itemDocument is the stored representation of an indexed item, the object id
+// is used as pagination cursor.
+type itemDocument struct {
+	ObjectID           primitive.ObjectID `bson:"_id,omitempty"`
+	domain.IndexedItem `bson:",inline"`
+	Keys               itemKeys `bson:"keys"`
+}
+
+// itemKeys are lowercase copies of the names of an item, so names can be
+// matched regardless of case by an equality query that uses an index.
+type itemKeys struct {
+	Name       string `bson:"name"`
+	TypeName   string `bson:"type_name"`
+	SetName    string `bson:"set_name"`
+	UniqueName string `bson:"unique_name"`
+	Runeword   string `bson:"runeword"`
+}
+
+// ItemRepository handles all operations on the item index of a realm.
+type ItemRepository struct {
+	db     string
+	realm  string
+	client *mongo.Client
+	locks  [32]sync.Mutex
+}
+
+// Replace will replace the indexed items of the character. The new items are
+// inserted before the old ones are removed, so a search never misses the items
+// of the character in between.
+func (r *ItemRepository) Replace(ctx context.Context, character string, items []domain.IndexedItem) error {
+	// Replaces of the same character must not interleave, or they'd remove
+	// each other's items.
+	lock := r.lock(character)
+	lock.Lock()
+	defer lock.Unlock()
+
+	collection := r.client.Database(r.db).Collection(itemCollectionName)
+	filter := bson.M{"realm": r.realm, "character": character}
+
+	if len(items) > 0 {
+		docs := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			item.Realm = r.realm
+			docs = append(docs, itemDocument{
+				IndexedItem: item,
+				Keys: itemKeys{
+					Name:       strings.ToLower(item.Name),
+					TypeName:   strings.ToLower(item.TypeName),
+					SetName:    strings.ToLower(item.SetName),
+					UniqueName: strings.ToLower(item.UniqueName),
+					Runeword:   strings.ToLower(item.Runeword),
+				},
+			})
+		}
+
+		res, err := collection.InsertMany(ctx, docs)
+		if err != nil {
+			return mongoErr(err)
+		}
+
+		filter["_id"] = bson.M{"$nin": res.InsertedIDs}
+	}
+
+	if _, err := collection.DeleteMany(ctx, filter); err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// Indexed will return whether the character has items in the index, items
+// indexed before their names were stored in lowercase don't count.
+func (r *ItemRepository) Indexed(ctx context.Context, character string) (bool, error) {
+	count, err := r.client.Database(r.db).Collection(itemCollectionName).
+		CountDocuments(ctx, bson.M{
+			"realm":     r.realm,
+			"character": character,
+			"keys":      bson.M{"$exists": true},
+		}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, mongoErr(err)
+	}
+
+	return count > 0, nil
+}
+
+// lock returns the lock guarding the items of the character.
+func (r *ItemRepository) lock(character string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(character))
+
+	return &r.locks[h.Sum32()%uint32(len(r.locks))]
+}
+
+// Search will return a page of the items matching the query.
+func (r *ItemRepository) Search(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	filter := bson.M{"realm": r.realm}
+
+	// Names are matched in full regardless of case.
+	for field, value := range map[string]string{
+		"keys.name":        query.Name,
+		"keys.type_name":   query.Type,
+		"keys.set_name":    query.SetName,
+		"keys.unique_name": query.UniqueName,
+		"keys.runeword":    query.Runeword,
+	} {
+		if value != "" {
+			filter[field] = strings.ToLower(value)
+		}
+	}
+
+	if query.Quality != "" {
+		filter["quality"] = domain.ItemQualities[query.Quality]
+	}
+
+	if query.Ethereal != nil {
+		filter["ethereal"] = *query.Ethereal
+	}
+
+	if query.MinSockets > 0 {
+		filter["sockets"] = bson.M{"$gte": query.MinSockets}
+	}
+
+	if len(query.Attributes) > 0 {
+		conditions := make(bson.A, 0, len(query.Attributes))
+		for _, attribute := range query.Attributes {
+			operator, ok := attributeOperators[attribute.Operator]
+			if !ok {
+				return nil, fmt.Errorf("invalid operator %s: %w", attribute.Operator, domain.ErrRequest)
+			}
+
+			conditions = append(conditions, bson.M{"attributes": bson.M{"$elemMatch": bson.M{
+				"id":    attribute.ID,
+				"value": bson.M{operator: attribute.Value},
+			}}})
+		}
+		filter["$and"] = conditions
+	}
+
+	if query.Cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", domain.ErrRequest)
+		}
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+
+	// Fetch one more than requested to know if there's another page.
+	opts := options.Find().
+		SetSort(bson.M{"_id": -1}).
+		SetLimit(int64(query.Limit + 1))
+
+	cur, err := r.client.Database(r.db).Collection(itemCollectionName).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var docs []itemDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	page := &domain.ItemPage{
+		Items: make([]domain.IndexedItem, 0, len(docs)),
+	}
+
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		page.NextCursor = docs[len(docs)-1].ObjectID.Hex()
+	}
+
+	for _, doc := range docs {
+		page.Items = append(page.Items, doc.IndexedItem)
+	}
+
+	return page, nil
+}
+
+// NewItemRepository returns a new instance of a MongoDB item repository
+// scoped to the given realm.
+func NewItemRepository(db string, realm string, client *mongo.Client) *ItemRepository {
+	return &ItemRepository{
+		db:     db,
+		realm:  realm,
+		client: client,
+	}
+}
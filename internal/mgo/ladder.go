@@ -0,0 +1,304 @@
+package mgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ladderSnapshotCollectionName is the name of the collection the daily standings are kept in.
+	ladderSnapshotCollectionName = "ladder_snapshot"
+)
+
+// Bits of the character status in the d2s header.
+const (
+	statusHardcore  = 1 << 2
+	statusExpansion = 1 << 5
+)
+
+// ladderSnapshot is the standing of a character the last time it was kept on a day.
+type ladderSnapshot struct {
+	Realm      string    `bson:"realm"`
+	Day        string    `bson:"day"`
+	Character  string    `bson:"character"`
+	Class      uint8     `bson:"class"`
+	Status     uint8     `bson:"status"`
+	Level      uint64    `bson:"level"`
+	Experience uint64    `bson:"experience"`
+	TakenAt    time.Time `bson:"taken_at"`
+}
+
+// LadderRepository ranks the characters of a realm.
+type LadderRepository struct {
+	db     string
+	realm  string
+	client *mongo.Client
+}
+
+// Standings will return a page of the characters matching the query, ranked
+// by level and experience, together with the number of matching characters.
+func (r *LadderRepository) Standings(ctx context.Context, query domain.LadderQuery) ([]domain.LadderEntry, int64, error) {
+	collection := r.client.Database(r.db).Collection(characterCollectionName)
+
+	filter := ladderFilter(query, "d2s.header.class", "d2s.header.status")
+	filter["realm"] = r.realm
+	filter["d2s"] = bson.M{"$ne": nil}
+	filter["deleted_at"] = bson.M{"$exists": false}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, mongoErr(err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: "d2s.attributes.level", Value: -1},
+			{Key: "d2s.attributes.experience", Value: -1},
+			{Key: "id", Value: 1},
+		}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit)).
+		SetProjection(bson.M{
+			"id":                        1,
+			"lastparsed":                1,
+			"d2s.header.class":          1,
+			"d2s.header.status":         1,
+			"d2s.attributes.level":      1,
+			"d2s.attributes.experience": 1,
+		})
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, mongoErr(err)
+	}
+
+	var characters []domain.Character
+	if err := cur.All(ctx, &characters); err != nil {
+		return nil, 0, mongoErr(err)
+	}
+
+	entries := make([]domain.LadderEntry, 0, len(characters))
+	for i, c := range characters {
+		entries = append(entries, domain.LadderEntry{
+			Rank:       query.Offset + i + 1,
+			Character:  c.Summary(),
+			Experience: c.D2s.Attributes.Experience,
+			Expansion:  c.D2s.Header.Status.Readable().Expansion,
+		})
+	}
+
+	return entries, total, nil
+}
+
+// PreviousRanks will return the ranks the characters had among the characters
+// matching the query in the last standings kept before the given day,
+// characters that weren't ranked then are left out.
+func (r *LadderRepository) PreviousRanks(ctx context.Context, before string, query domain.LadderQuery, characters []string) (map[string]int, error) {
+	collection := r.client.Database(r.db).Collection(ladderSnapshotCollectionName)
+	ranks := make(map[string]int, len(characters))
+
+	var last ladderSnapshot
+	err := collection.FindOne(ctx,
+		bson.M{"realm": r.realm, "day": bson.M{"$lt": before}},
+		options.FindOne().SetSort(bson.M{"day": -1}).SetProjection(bson.M{"day": 1}),
+	).Decode(&last)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ranks, nil
+		}
+		return nil, mongoErr(err)
+	}
+
+	filter := ladderFilter(query, "class", "status")
+	filter["realm"] = r.realm
+	filter["day"] = last.Day
+
+	own := bson.M{"character": bson.M{"$in": characters}}
+	for k, v := range filter {
+		own[k] = v
+	}
+
+	cur, err := collection.Find(ctx, own)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var snapshots []ladderSnapshot
+	if err := cur.All(ctx, &snapshots); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	if len(snapshots) == 0 {
+		return ranks, nil
+	}
+
+	// Only the standings down to the lowest ranked of the characters are
+	// needed to rank them, ties are ranked by name.
+	lowest := snapshots[0]
+	for _, s := range snapshots[1:] {
+		if s.Level < lowest.Level ||
+			s.Level == lowest.Level && s.Experience < lowest.Experience ||
+			s.Level == lowest.Level && s.Experience == lowest.Experience && s.Character > lowest.Character {
+			lowest = s
+		}
+	}
+
+	filter["$or"] = bson.A{
+		bson.M{"level": bson.M{"$gt": lowest.Level}},
+		bson.M{"level": lowest.Level, "experience": bson.M{"$gt": lowest.Experience}},
+		bson.M{"level": lowest.Level, "experience": lowest.Experience, "character": bson.M{"$lte": lowest.Character}},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: "level", Value: -1},
+			{Key: "experience", Value: -1},
+			{Key: "character", Value: 1},
+		}).
+		SetProjection(bson.M{"character": 1})
+
+	cur, err = collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var standings []ladderSnapshot
+	if err := cur.All(ctx, &standings); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	wanted := make(map[string]bool, len(snapshots))
+	for _, s := range snapshots {
+		wanted[s.Character] = true
+	}
+
+	for i, s := range standings {
+		if wanted[s.Character] {
+			ranks[s.Character] = i + 1
+		}
+	}
+
+	return ranks, nil
+}
+
+// Snapshot will store the standings of all characters for the given day,
+// replacing the standings stored earlier that day.
+func (r *LadderRepository) Snapshot(ctx context.Context, day string) error {
+	filter := bson.M{
+		"realm":      r.realm,
+		"d2s":        bson.M{"$ne": nil},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	opts := options.Find().SetProjection(bson.M{
+		"id":                        1,
+		"d2s.header.class":          1,
+		"d2s.header.status":         1,
+		"d2s.attributes.level":      1,
+		"d2s.attributes.experience": 1,
+	})
+
+	cur, err := r.client.Database(r.db).Collection(characterCollectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	var characters []domain.Character
+	if err := cur.All(ctx, &characters); err != nil {
+		return mongoErr(err)
+	}
+
+	if len(characters) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(characters))
+
+	for _, c := range characters {
+		snapshot := ladderSnapshot{
+			Realm:      r.realm,
+			Day:        day,
+			Character:  c.ID,
+			Class:      uint8(c.D2s.Header.Class),
+			Status:     uint8(c.D2s.Header.Status),
+			Level:      c.D2s.Attributes.Level,
+			Experience: c.D2s.Attributes.Experience,
+			TakenAt:    now,
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"realm": r.realm, "day": day, "character": c.ID}).
+			SetUpdate(bson.M{"$set": snapshot}).
+			SetUpsert(true))
+	}
+
+	collection := r.client.Database(r.db).Collection(ladderSnapshotCollectionName)
+
+	if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return mongoErr(err)
+	}
+
+	// Characters that are no longer ranked, such as deleted ones, weren't replaced.
+	_, err = collection.DeleteMany(ctx, bson.M{"realm": r.realm, "day": day, "taken_at": bson.M{"$lt": now}})
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// ladderFilter returns the filter of the ladder query, the fields holding the
+// class and status differ between characters and snapshots.
+func ladderFilter(query domain.LadderQuery, classField string, statusField string) bson.M {
+	filter := bson.M{}
+
+	if query.Class != "" {
+		filter[classField] = domain.CharacterClasses[query.Class]
+	}
+
+	var set, unset int
+	if query.Hardcore != nil {
+		if *query.Hardcore {
+			set |= statusHardcore
+		} else {
+			unset |= statusHardcore
+		}
+	}
+	if query.Expansion != nil {
+		if *query.Expansion {
+			set |= statusExpansion
+		} else {
+			unset |= statusExpansion
+		}
+	}
+
+	status := bson.M{}
+	if set != 0 {
+		status["$bitsAllSet"] = set
+	}
+	if unset != 0 {
+		status["$bitsAllClear"] = unset
+	}
+	if len(status) > 0 {
+		filter[statusField] = status
+	}
+
+	return filter
+}
+
+// NewLadderRepository returns a new instance of a MongoDB ladder repository
+// scoped to the given realm.
+func NewLadderRepository(db string, realm string, client *mongo.Client) *LadderRepository {
+	return &LadderRepository{
+		db:     db,
+		realm:  realm,
+		client: client,
+	}
+}
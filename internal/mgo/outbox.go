@@ -0,0 +1,106 @@
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// outboxCollectionName is the name of the collection we'll use for all queries.
+	outboxCollectionName = "webhook_outbox"
+)
+
+// OutboxRepository handles all operations on webhook deliveries that haven't been delivered yet.
+type OutboxRepository struct {
+	db     string
+	client *mongo.Client
+}
+
+// Enqueue will store the deliveries.
+func (r *OutboxRepository) Enqueue(ctx context.Context, deliveries []domain.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		docs = append(docs, delivery)
+	}
+
+	_, err := r.client.Database(r.db).Collection(outboxCollectionName).
+		InsertMany(ctx, docs)
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// Due will find the deliveries that should be attempted at the given time, oldest first.
+func (r *OutboxRepository) Due(ctx context.Context, at time.Time, limit int) ([]domain.Delivery, error) {
+	filter := bson.M{
+		"failed":       false,
+		"next_attempt": bson.M{"$lte": at},
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"next_attempt": 1}).
+		SetLimit(int64(limit))
+
+	cur, err := r.client.Database(r.db).Collection(outboxCollectionName).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+
+	var deliveries []domain.Delivery
+	if err := cur.All(ctx, &deliveries); err != nil {
+		return nil, mongoErr(err)
+	}
+
+	return deliveries, nil
+}
+
+// Update will store the outcome of a failed attempt.
+func (r *OutboxRepository) Update(ctx context.Context, delivery domain.Delivery) error {
+	change := bson.M{
+		"$set": bson.M{
+			"attempts":     delivery.Attempts,
+			"next_attempt": delivery.NextAttempt,
+			"last_error":   delivery.LastError,
+			"failed":       delivery.Failed,
+		},
+	}
+
+	_, err := r.client.Database(r.db).Collection(outboxCollectionName).
+		UpdateOne(ctx, bson.M{"id": delivery.ID}, change)
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// Delete will remove a delivery that has been delivered.
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.Database(r.db).Collection(outboxCollectionName).
+		DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return mongoErr(err)
+	}
+
+	return nil
+}
+
+// NewOutboxRepository returns a new instance of a MongoDB outbox repository.
+func NewOutboxRepository(db string, client *mongo.Client) *OutboxRepository {
+	return &OutboxRepository{
+		db:     db,
+		client: client,
+	}
+}
@@ -0,0 +1,24 @@
+package mgo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AssignRealm moves all documents stored before realms were introduced into
+// the given realm, so they keep being found by the realm scoped repositories.
+func AssignRealm(ctx context.Context, db string, realm string, client *mongo.Client) error {
+	filter := bson.M{"realm": bson.M{"$exists": false}}
+	change := bson.M{"$set": bson.M{"realm": realm}}
+
+	for _, collection := range []string{characterCollectionName, historyCollectionName} {
+		_, err := client.Database(db).Collection(collection).UpdateMany(ctx, filter, change)
+		if err != nil {
+			return mongoErr(err)
+		}
+	}
+
+	return nil
+}
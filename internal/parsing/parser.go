@@ -1,48 +1,152 @@
 package parsing
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/nokka/d2-armory-api/internal/domain"
 	"github.com/nokka/d2s"
 )
 
-// Parser performs all parsing from d2s data to our domain model.
+// Parser performs all parsing from d2s data of a realm to our domain model.
 type Parser struct {
+	realm   string
 	d2spath string
 }
 
 // Parse will parse the given character on disk into a character in our domain model.
 func (p Parser) Parse(name string) (*domain.Character, error) {
-	// Character path on disk.
-	file, err := os.Open(fmt.Sprintf("%s/%s", p.d2spath, name))
+	data, fingerprint, err := p.read(name)
 	if err != nil {
-		return nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+		return nil, err
 	}
 
-	// Close the file when we're done.
-	defer file.Close()
-
 	// Parse the actual .d2s binary file.
-	d2schar, err := d2s.Parse(file)
+	d2schar, err := decode(data)
 	if err != nil {
 		return nil, fmt.Errorf("binary parse error: %w", err)
 	}
 
 	character := domain.Character{
-		ID:         name,
+		ID:          name,
+		Realm:       p.realm,
+		D2s:         d2schar,
+		LastParsed:  time.Now(),
+		Fingerprint: *fingerprint,
+	}
+
+	return &character, nil
+}
+
+// ParseContent will parse an uploaded binary into a character in our domain model,
+// the character is named by the name in its header since there's no file name.
+func (p Parser) ParseContent(data []byte) (*domain.Character, error) {
+	d2schar, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("binary parse error, %s: %w", err, domain.ErrRequest)
+	}
+
+	sum := sha256.Sum256(data)
+
+	character := domain.Character{
+		ID:         d2schar.Header.Name.String(),
+		Realm:      p.realm,
 		D2s:        d2schar,
 		LastParsed: time.Now(),
+		Fingerprint: domain.Fingerprint{
+			Hash:     hex.EncodeToString(sum[:]),
+			Size:     int64(len(data)),
+			Modified: time.Now().UTC(),
+		},
+		Uploaded: true,
 	}
 
 	return &character, nil
 }
 
-// NewParser constructs a new parser with dependencies.
-func NewParser(d2spath string) *Parser {
+// decode decodes the d2s binary.
+func decode(data []byte) (*d2s.Character, error) {
+	return decodeFrom(bufio.NewReader(bytes.NewReader(data)))
+}
+
+// decodeFrom decodes a d2s binary from the reader, a malformed binary must not
+// take the server down so a panic in the decoder is returned as an error.
+func decodeFrom(r *bufio.Reader) (char *d2s.Character, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			char, err = nil, fmt.Errorf("malformed binary: %v", r)
+		}
+	}()
+
+	// A buffered reader of the default size is used by d2s as is.
+	return d2s.Parse(r)
+}
+
+// Fingerprint will read the given character on disk and fingerprint its content
+// without decoding it.
+func (p Parser) Fingerprint(name string) (*domain.Fingerprint, error) {
+	_, fingerprint, err := p.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fingerprint, nil
+}
+
+// Names will return the names of all character binaries on disk, character
+// binaries have no extension unlike the PlugY stashes next to them.
+func (p Parser) Names() ([]string, error) {
+	entries, err := os.ReadDir(p.d2spath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read d2s directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != "" {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// read returns the raw binary of the character together with its fingerprint.
+func (p Parser) read(name string) ([]byte, *domain.Fingerprint, error) {
+	// Character path on disk.
+	path := fmt.Sprintf("%s/%s", p.d2spath, name)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("character binary does not exist: %w", domain.ErrNotFound)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return data, &domain.Fingerprint{
+		Hash:     hex.EncodeToString(sum[:]),
+		Size:     int64(len(data)),
+		Modified: info.ModTime().UTC(),
+	}, nil
+}
+
+// NewParser constructs a new parser of the realm with dependencies.
+func NewParser(realm domain.Realm) *Parser {
 	return &Parser{
-		d2spath: d2spath,
+		realm:   realm.Name,
+		d2spath: realm.Path,
 	}
 }
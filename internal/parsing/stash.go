@@ -0,0 +1,257 @@
+package parsing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// PlugY keeps the personal stash of a character next to its binary, and the
+// shared stash of an account in a file named after the account.
+const (
+	personalStashExtension = ".d2x"
+	sharedStashExtension   = ".sss"
+)
+
+// Tags identifying the sections of a PlugY stash file.
+const (
+	personalStashTag = "CSTM"
+	sharedStashTag   = "SSS\x00"
+	stashPageTag     = "ST"
+	itemListTag      = "JM"
+)
+
+// itemEnvelope is the start of a minimal character binary the item list of a
+// stash page is wrapped in, since d2s only decodes items as part of a character.
+// It's a zeroed header of a classic amazon, followed by an attribute section
+// that ends right away and a skill section without any points.
+var itemEnvelope = func() []byte {
+	envelope := make([]byte, 767)
+	envelope = append(envelope, 0xff, 0xff)
+	envelope = append(envelope, "if"...)
+	return append(envelope, make([]byte, 30)...)
+}()
+
+// emptyItemList ends the envelope in place of the corpse item list.
+var emptyItemList = []byte{'J', 'M', 0, 0}
+
+// ParseStash will parse the personal PlugY stash of the given character.
+func (p Parser) ParseStash(name string) (*domain.Stash, error) {
+	data, _, err := p.read(name + personalStashExtension)
+	if err != nil {
+		return nil, fmt.Errorf("stash does not exist: %w", domain.ErrNotFound)
+	}
+
+	stash, err := decodeStash(data, false)
+	if err != nil {
+		return nil, fmt.Errorf("stash parse error: %w", err)
+	}
+
+	stash.Owner = name
+
+	return stash, nil
+}
+
+// ParseSharedStash will parse the PlugY stash shared by the characters of the given account.
+func (p Parser) ParseSharedStash(account string) (*domain.Stash, error) {
+	data, _, err := p.read(account + sharedStashExtension)
+	if err != nil {
+		return nil, fmt.Errorf("shared stash does not exist: %w", domain.ErrNotFound)
+	}
+
+	stash, err := decodeStash(data, true)
+	if err != nil {
+		return nil, fmt.Errorf("shared stash parse error: %w", err)
+	}
+
+	stash.Owner = account
+
+	return stash, nil
+}
+
+// stashReader reads the sections of a stash file in order.
+type stashReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *stashReader) next(n int) ([]byte, error) {
+	if r.offset+n > len(r.data) {
+		return nil, errors.New("unexpected end of stash")
+	}
+
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+
+	return b, nil
+}
+
+func (r *stashReader) peek(n int) []byte {
+	if r.offset+n > len(r.data) {
+		return nil
+	}
+
+	return r.data[r.offset : r.offset+n]
+}
+
+func (r *stashReader) uint32() (uint32, error) {
+	b, err := r.next(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *stashReader) expect(tag string) error {
+	b, err := r.next(len(tag))
+	if err != nil {
+		return err
+	}
+
+	if string(b) != tag {
+		return fmt.Errorf("expected tag %q at offset %d", tag, r.offset-len(tag))
+	}
+
+	return nil
+}
+
+// decodeStash decodes a PlugY stash file.
+//
+// A personal stash starts with "CSTM", the version "01" and 4 reserved bytes.
+// A shared stash starts with "SSS\0" and the version "01", or "02" followed by
+// the shared gold. Both continue with the number of pages and the pages.
+func decodeStash(data []byte, shared bool) (*domain.Stash, error) {
+	r := &stashReader{data: data}
+	stash := &domain.Stash{Shared: shared}
+
+	if shared {
+		if err := r.expect(sharedStashTag); err != nil {
+			return nil, err
+		}
+
+		version, err := r.next(2)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(version) == "02" {
+			if stash.Gold, err = r.uint32(); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if err := r.expect(personalStashTag); err != nil {
+			return nil, err
+		}
+
+		if _, err := r.next(6); err != nil {
+			return nil, err
+		}
+	}
+
+	pages, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < int(pages); i++ {
+		page, err := decodeStashPage(r)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", i+1, err)
+		}
+
+		page.Number = i + 1
+		stash.Pages = append(stash.Pages, *page)
+	}
+
+	return stash, nil
+}
+
+// decodeStashPage decodes a page, it starts with "ST" which is followed by the
+// item list in older versions of PlugY, and by the page flags and a null
+// terminated page name in later ones.
+func decodeStashPage(r *stashReader) (*domain.StashPage, error) {
+	if err := r.expect(stashPageTag); err != nil {
+		return nil, err
+	}
+
+	page := &domain.StashPage{}
+
+	if string(r.peek(2)) != itemListTag {
+		flags, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		page.Flags = flags
+
+		end := bytes.IndexByte(r.data[r.offset:], 0)
+		if end < 0 {
+			return nil, errors.New("unterminated page name")
+		}
+		page.Name = string(r.data[r.offset : r.offset+end])
+		r.offset += end + 1
+	}
+
+	items, size, err := decodeItems(r.data[r.offset:])
+	if err != nil {
+		return nil, err
+	}
+	r.offset += size
+
+	page.Items = items
+
+	return page, nil
+}
+
+// decodeItems decodes the item list at the start of the data and returns the
+// items together with the size of the list in bytes.
+//
+// d2s only decodes items as part of a character and doesn't tell where the list
+// ends, so the list is wrapped in an envelope ending at each place the next page
+// could start. Only the right end leaves the empty corpse list of the envelope
+// where d2s expects it, the shortest end that decodes is the size of the list.
+func decodeItems(data []byte) ([]d2s.Item, int, error) {
+	ends := stashPageEnds(data)
+
+	var err error
+	for _, end := range ends {
+		var char *d2s.Character
+		if char, err = decode(envelop(data[:end])); err == nil {
+			return char.Items, end, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("malformed item list: %w", err)
+}
+
+// stashPageEnds returns the offsets the item list at the start of the data can
+// end at, in order, which is before each page tag or at the end of the data.
+func stashPageEnds(data []byte) []int {
+	var ends []int
+
+	for offset := len(itemListTag); offset < len(data); {
+		i := bytes.Index(data[offset:], []byte(stashPageTag))
+		if i < 0 {
+			break
+		}
+
+		ends = append(ends, offset+i)
+		offset += i + 1
+	}
+
+	return append(ends, len(data))
+}
+
+// envelop wraps an item list in a minimal character binary.
+func envelop(items []byte) []byte {
+	data := make([]byte, 0, len(itemEnvelope)+len(items)+len(emptyItemList))
+	data = append(data, itemEnvelope...)
+	data = append(data, items...)
+
+	return append(data, emptyItemList...)
+}
@@ -0,0 +1,216 @@
+package parsing
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// bitWriter writes values least significant bit first, the way items are stored.
+type bitWriter struct {
+	data []byte
+	bits int
+}
+
+func (w *bitWriter) write(value uint64, bits int) {
+	for i := 0; i < bits; i++ {
+		if w.bits%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if value&(1<<uint(i)) != 0 {
+			w.data[len(w.data)-1] |= 1 << uint(w.bits%8)
+		}
+		w.bits++
+	}
+}
+
+// simpleItem encodes a simple item of the given type stored in the stash.
+func simpleItem(code string, x uint64, y uint64) []byte {
+	w := &bitWriter{}
+	w.write('J', 8)
+	w.write('M', 8)
+	w.write(0, 21)
+	w.write(1, 1) // Simple item.
+	w.write(0, 10)
+	w.write(101, 8) // Version.
+	w.write(0, 2)
+	w.write(0, 3) // Stored.
+	w.write(0, 4)
+	w.write(x, 4)
+	w.write(y, 3)
+	w.write(0, 1)
+	w.write(5, 3) // Stash.
+	for i := 0; i < 4; i++ {
+		w.write(uint64(code[i]), 8)
+	}
+	w.write(0, 3)
+
+	return w.data
+}
+
+// itemList encodes an item list with the given items.
+func itemList(items ...[]byte) []byte {
+	list := []byte{'J', 'M', 0, 0}
+	binary.LittleEndian.PutUint16(list[2:], uint16(len(items)))
+
+	for _, item := range items {
+		list = append(list, item...)
+	}
+
+	return list
+}
+
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func TestDecodeStash(t *testing.T) {
+	concat := func(parts ...[]byte) []byte {
+		var data []byte
+		for _, part := range parts {
+			data = append(data, part...)
+		}
+		return data
+	}
+
+	personal := concat(
+		[]byte("CSTM01"), uint32Bytes(0), uint32Bytes(2),
+		// A page of an older PlugY without flags and name.
+		[]byte("ST"), itemList(simpleItem("r01 ", 0, 0), simpleItem("r02 ", 1, 0)),
+		[]byte("ST"), uint32Bytes(1), []byte("runes\x00"), itemList(simpleItem("gcv ", 2, 3)),
+	)
+
+	shared := concat(
+		[]byte("SSS\x0002"), uint32Bytes(150000), uint32Bytes(1),
+		[]byte("ST"), uint32Bytes(0), []byte("\x00"), itemList(),
+	)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		shared    bool
+		gold      uint32
+		pageNames []string
+		items     [][]string
+		wantError bool
+	}{
+		{
+			name:      "personal stash",
+			data:      personal,
+			pageNames: []string{"", "runes"},
+			items:     [][]string{{"r01", "r02"}, {"gcv"}},
+		},
+		{
+			name:      "shared stash",
+			data:      shared,
+			shared:    true,
+			gold:      150000,
+			pageNames: []string{""},
+			items:     [][]string{{}},
+		},
+		{
+			name:      "wrong kind of stash",
+			data:      shared,
+			wantError: true,
+		},
+		{
+			name:      "truncated stash",
+			data:      personal[:len(personal)-5],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stash, err := decodeStash(tt.data, tt.shared)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if stash.Gold != tt.gold {
+				t.Errorf("expected gold %d, got = %d", tt.gold, stash.Gold)
+			}
+
+			if len(stash.Pages) != len(tt.pageNames) {
+				t.Fatalf("expected %d pages, got = %d", len(tt.pageNames), len(stash.Pages))
+			}
+
+			for i, page := range stash.Pages {
+				if page.Number != i+1 || page.Name != tt.pageNames[i] {
+					t.Errorf("expected page %d named %q, got = %d %q", i+1, tt.pageNames[i], page.Number, page.Name)
+				}
+
+				if len(page.Items) != len(tt.items[i]) {
+					t.Fatalf("expected %d items on page %d, got = %d", len(tt.items[i]), i+1, len(page.Items))
+				}
+
+				for j, item := range page.Items {
+					if item.Type != tt.items[i][j] {
+						t.Errorf("expected item %s, got = %s", tt.items[i][j], item.Type)
+					}
+				}
+			}
+		})
+	}
+}
+
+// The shared stash fixture holds the item list of a real character on its
+// first page, named "Gear", and an empty second page.
+func TestParseSharedStashFixture(t *testing.T) {
+	p := NewParser(domain.Realm{Name: "default", Path: "testdata"})
+
+	stash, err := p.ParseSharedStash("shared")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if !stash.Shared || stash.Owner != "shared" || stash.Gold != 2500000 {
+		t.Errorf("expected the shared stash of shared with 2500000 gold, got = %t %s %d", stash.Shared, stash.Owner, stash.Gold)
+	}
+
+	if len(stash.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got = %d", len(stash.Pages))
+	}
+
+	gear, empty := stash.Pages[0], stash.Pages[1]
+
+	if gear.Name != "Gear" || gear.Flags != 1 || len(gear.Items) != 60 {
+		t.Fatalf("expected 60 items on the page Gear, got = %d on %q", len(gear.Items), gear.Name)
+	}
+
+	if len(empty.Items) != 0 {
+		t.Errorf("expected the second page to be empty, got = %d items", len(empty.Items))
+	}
+
+	found := map[string]int{}
+	for _, item := range gear.Items {
+		found[item.UniqueName+item.SetName+item.RunewordName] = len(item.SocketedItems)
+	}
+
+	for name, sockets := range map[string]int{
+		"Mara's Kaleidoscope": 0,
+		"Aldur's Advance":     0,
+		"Nightwing's Veil":    1,
+		"Chains of Honor":     4,
+	} {
+		got, ok := found[name]
+		if !ok {
+			t.Errorf("expected to find %s", name)
+			continue
+		}
+
+		if got != sockets {
+			t.Errorf("expected %s to have %d socketed items, got = %d", name, sockets, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2-armory-api/internal/metrics"
+)
+
+//go:generate moq -out ./service_mocks.go . historyRepository
+
+// maxWindows is the max number of windows the rate can be computed over at once.
+const maxWindows = 8
+
+// DefaultWindows are the windows the rate is computed over unless configured otherwise.
+var DefaultWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// milestones are the levels we keep track of how long it took to reach.
+var milestones = []uint64{30, 60, 80, 90, 99}
+
+// historyRepository is the interface representation of the data layer
+// the service depend on.
+type historyRepository interface {
+	Progress(ctx context.Context, character string) ([]domain.ProgressPoint, error)
+}
+
+// Service computes how fast characters gain experience from their history.
+type Service struct {
+	realm      string
+	repository historyRepository
+	windows    []time.Duration
+	now        func() time.Time
+}
+
+// Option is used to configure the service.
+type Option func(s *Service)
+
+// WithRealm sets the realm the characters belong to, their metrics are labelled with it.
+func WithRealm(realm string) Option {
+	return func(s *Service) {
+		s.realm = realm
+	}
+}
+
+// WithWindows sets the windows the rate is computed over by default.
+func WithWindows(windows ...time.Duration) Option {
+	return func(s *Service) {
+		s.windows = windows
+	}
+}
+
+// CharacterParsed updates the progress metrics of the character every time it has been parsed.
+func (s Service) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if err := s.UpdateMetrics(ctx, current.ID); err != nil {
+		log.Printf("failed to compute progress of character %s: %v", current.ID, err)
+	}
+}
+
+// UpdateMetrics recomputes the progress metrics of the character, the rates
+// are relative to now so they have to be recomputed even when nothing changed.
+// A character without history yet has no progress, which isn't an error.
+func (s Service) UpdateMetrics(ctx context.Context, character string) error {
+	progress, err := s.Progress(ctx, character)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	metrics.UpdateProgressMetrics(s.realm, progress)
+	return nil
+}
+
+// Progress returns the experience rate of the character over the given windows,
+// or the default ones when none are given, along with when it will level up.
+func (s Service) Progress(ctx context.Context, character string, windows ...time.Duration) (*domain.Progress, error) {
+	if character == "" {
+		return nil, fmt.Errorf("character name is required: %w", domain.ErrRequest)
+	}
+
+	if len(windows) == 0 {
+		windows = s.windows
+	}
+
+	if len(windows) > maxWindows {
+		return nil, fmt.Errorf("at most %d windows are allowed: %w", maxWindows, domain.ErrRequest)
+	}
+
+	for _, window := range windows {
+		if window <= 0 {
+			return nil, fmt.Errorf("windows must be positive: %w", domain.ErrRequest)
+		}
+	}
+
+	points, err := s.repository.Progress(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) == 0 {
+		return nil, fmt.Errorf("character has no history: %w", domain.ErrNotFound)
+	}
+
+	var (
+		now    = s.now()
+		latest = points[len(points)-1]
+	)
+
+	progress := &domain.Progress{
+		Character:  character,
+		Level:      latest.Level,
+		Experience: latest.Experience,
+		LastParsed: latest.ParsedAt,
+		Rates:      make([]domain.ExperienceRate, 0, len(windows)),
+		Milestones: make([]domain.Milestone, 0, len(milestones)),
+	}
+
+	// Estimates use the longest window with any experience gained, since it's the least
+	// affected by the breaks taken between sessions.
+	var (
+		longest time.Duration
+		rate    float64
+	)
+
+	for _, window := range windows {
+		perHour := experienceRate(points, now, window)
+		progress.Rates = append(progress.Rates, domain.ExperienceRate{
+			Window:  domain.FormatWindow(window),
+			PerHour: perHour,
+		})
+
+		if perHour > 0 && window > longest {
+			longest, rate = window, perHour
+		}
+	}
+
+	if latest.Level < domain.MaxLevel {
+		progress.NextLevel = estimate(latest, latest.Level+1, rate, now)
+		progress.MaxLevel = estimate(latest, domain.MaxLevel, rate, now)
+	}
+
+	for _, level := range milestones {
+		progress.Milestones = append(progress.Milestones, milestone(points, level))
+	}
+
+	return progress, nil
+}
+
+// experienceRate returns the experience gained per hour within the window. The
+// experience at the start of the window is interpolated between the points
+// around it, or taken from the first point when the character is younger than
+// the window. Without any point within the window no experience was gained.
+func experienceRate(points []domain.ProgressPoint, now time.Time, window time.Duration) float64 {
+	start := now.Add(-window)
+
+	// Index of the first point after the start of the window.
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].ParsedAt.After(start)
+	})
+
+	if i == len(points) {
+		return 0
+	}
+
+	from, base := points[0].ParsedAt, float64(points[0].Experience)
+	if i > 0 {
+		before, after := points[i-1], points[i]
+		share := float64(start.Sub(before.ParsedAt)) / float64(after.ParsedAt.Sub(before.ParsedAt))
+		from, base = start, float64(before.Experience)+share*(float64(after.Experience)-float64(before.Experience))
+	}
+
+	elapsed := now.Sub(from)
+	if elapsed <= 0 {
+		return 0
+	}
+
+	// Experience is lost when dying, so the rate can be negative.
+	return (float64(points[len(points)-1].Experience) - base) / elapsed.Hours()
+}
+
+// estimate returns the experience remaining until the level, and when it will
+// be reached at the rate.
+func estimate(latest domain.ProgressPoint, level uint64, rate float64, now time.Time) *domain.LevelEstimate {
+	e := &domain.LevelEstimate{Level: level}
+
+	if required := domain.LevelExperience(level); required > latest.Experience {
+		e.Remaining = required - latest.Experience
+	}
+
+	if rate > 0 {
+		seconds := int64(float64(e.Remaining) / rate * time.Hour.Seconds())
+		at := now.Add(time.Duration(seconds) * time.Second)
+		e.Seconds = &seconds
+		e.At = &at
+	}
+
+	return e
+}
+
+// milestone returns when the level was first reached and how long it took since
+// the character was first seen, unless it was reached before that.
+func milestone(points []domain.ProgressPoint, level uint64) domain.Milestone {
+	m := domain.Milestone{Level: level}
+
+	for i, point := range points {
+		if point.Level < level {
+			continue
+		}
+
+		if i > 0 {
+			reached := point.ParsedAt
+			seconds := int64(reached.Sub(points[0].ParsedAt).Seconds())
+			m.ReachedAt = &reached
+			m.Seconds = &seconds
+		}
+
+		break
+	}
+
+	return m
+}
+
+// NewService constructs a new progress service with all the dependencies.
+func NewService(repository historyRepository, opts ...Option) *Service {
+	s := &Service{
+		repository: repository,
+		windows:    DefaultWindows,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
@@ -0,0 +1,206 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2-armory-api/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestProgress(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(48 * time.Hour)
+
+	repository := &historyRepositoryMock{
+		ProgressFunc: func(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+			return []domain.ProgressPoint{
+				{ParsedAt: start, Level: 1, Experience: 0},
+				{ParsedAt: start.Add(10 * time.Hour), Level: 30, Experience: 4663553},
+				{ParsedAt: start.Add(24 * time.Hour), Level: 32, Experience: 6400000},
+				{ParsedAt: now.Add(-30 * time.Minute), Level: 33, Experience: 7400000},
+			}, nil
+		},
+	}
+
+	s := NewService(repository)
+	s.now = func() time.Time { return now }
+
+	progress, err := s.Progress(context.TODO(), "nokka", time.Hour, 24*time.Hour, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if progress.Level != 33 || progress.Experience != 7400000 {
+		t.Errorf("expected the latest level and experience, got = %d, %d", progress.Level, progress.Experience)
+	}
+
+	expected := []domain.ExperienceRate{
+		// The experience at the start of the window is interpolated between the
+		// points around it, so a day old point doesn't spread over the last hour.
+		{Window: "1h", PerHour: 1000000.0 / 47},
+		// Measured from the point at the start of the window.
+		{Window: "1d", PerHour: 1000000.0 / 24},
+		// The character is younger than the window, so it's measured from the first point.
+		{Window: "7d", PerHour: 7400000.0 / 48},
+	}
+	for i, rate := range expected {
+		if got := progress.Rates[i]; got.Window != rate.Window || math.Abs(got.PerHour-rate.PerHour) > 1e-6 {
+			t.Errorf("unexpected rate, expected = %+v, got = %+v", rate, got)
+		}
+	}
+
+	next := progress.NextLevel
+	if next.Level != 34 || next.Remaining != 8458379-7400000 {
+		t.Errorf("unexpected next level, got = %+v", next)
+	}
+
+	// Estimates are based on the longest window.
+	if seconds := int64(24714); next.Seconds == nil || *next.Seconds != seconds {
+		t.Errorf("expected the time to the next level to be %d, got = %v", seconds, next.Seconds)
+	}
+
+	if max := progress.MaxLevel; max.Level != 99 || max.Remaining != 3520485254-7400000 || max.At == nil {
+		t.Errorf("unexpected max level, got = %+v", max)
+	}
+
+	reached := start.Add(10 * time.Hour)
+	if m := progress.Milestones[0]; m.Level != 30 || !m.ReachedAt.Equal(reached) || *m.Seconds != 36000 {
+		t.Errorf("unexpected milestone, got = %+v", m)
+	}
+
+	if m := progress.Milestones[1]; m.ReachedAt != nil || m.Seconds != nil {
+		t.Errorf("expected a milestone not reached yet to be empty, got = %+v", m)
+	}
+}
+
+func TestProgressWithoutExperience(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repository := &historyRepositoryMock{
+		ProgressFunc: func(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+			return []domain.ProgressPoint{
+				{ParsedAt: now.Add(-2 * time.Hour), Level: 85, Experience: 1100000000},
+			}, nil
+		},
+	}
+
+	s := NewService(repository, WithWindows(time.Hour))
+	s.now = func() time.Time { return now }
+
+	progress, err := s.Progress(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if len(progress.Rates) != 1 || progress.Rates[0].PerHour != 0 {
+		t.Errorf("expected no experience gained in the configured window, got = %+v", progress.Rates)
+	}
+
+	if progress.NextLevel.Seconds != nil || progress.NextLevel.At != nil {
+		t.Errorf("expected no estimate without experience gained, got = %+v", progress.NextLevel)
+	}
+
+	// Milestones reached before the character was first seen are unknown.
+	if m := progress.Milestones[0]; m.ReachedAt != nil {
+		t.Errorf("expected an unknown milestone, got = %+v", m)
+	}
+}
+
+func TestUpdateMetrics(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repository := &historyRepositoryMock{
+		ProgressFunc: func(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+			return []domain.ProgressPoint{
+				{ParsedAt: now.Add(-2 * time.Hour), Level: 85, Experience: 1100000000},
+				{ParsedAt: now.Add(-time.Hour), Level: 85, Experience: 1100000000},
+				{ParsedAt: now.Add(-30 * time.Minute), Level: 85, Experience: 1100100000},
+			}, nil
+		},
+	}
+
+	s := NewService(repository, WithRealm("hardcore"), WithWindows(time.Hour))
+
+	s.now = func() time.Time { return now }
+	if err := s.UpdateMetrics(context.TODO(), "metricsnokka"); err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	rate := metrics.CharacterExperienceRate.WithLabelValues("hardcore", "metricsnokka", "1h")
+	if got := gaugeValue(t, rate); got != 100000 {
+		t.Errorf("expected the rate to be labelled by realm, got = %v", got)
+	}
+
+	// Once the last point falls out of the window the rate is recomputed as time passes.
+	s.now = func() time.Time { return now.Add(time.Hour) }
+	if err := s.UpdateMetrics(context.TODO(), "metricsnokka"); err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if got := gaugeValue(t, rate); got != 0 {
+		t.Errorf("expected no experience gained in the window, got = %v", got)
+	}
+
+	metrics.RemoveCharacterMetrics("hardcore", "metricsnokka")
+
+	if metrics.CharacterExperienceRate.DeleteLabelValues("hardcore", "metricsnokka", "1h") {
+		t.Error("expected the rates of the character to be removed")
+	}
+}
+
+// gaugeValue returns the current value of the gauge.
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	var m dto.Metric
+	if err := gauge.Write(&m); err != nil {
+		t.Fatalf("failed to read gauge: %v", err)
+	}
+
+	return m.GetGauge().GetValue()
+}
+
+func TestUpdateMetricsWithoutHistory(t *testing.T) {
+	s := NewService(&historyRepositoryMock{
+		ProgressFunc: func(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+			return nil, nil
+		},
+	})
+
+	if err := s.UpdateMetrics(context.TODO(), "nokka"); err != nil {
+		t.Errorf("didn't expect a character without history to be an error, got = %v", err)
+	}
+}
+
+func TestProgressErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		character string
+		windows   []time.Duration
+		points    []domain.ProgressPoint
+		expected  error
+	}{
+		{"missing character", "", nil, nil, domain.ErrRequest},
+		{"negative window", "nokka", []time.Duration{-time.Hour}, nil, domain.ErrRequest},
+		{"too many windows", "nokka", make([]time.Duration, maxWindows+1), nil, domain.ErrRequest},
+		{"no history", "nokka", nil, nil, domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(&historyRepositoryMock{
+				ProgressFunc: func(ctx context.Context, character string) ([]domain.ProgressPoint, error) {
+					return tt.points, nil
+				},
+			})
+
+			if _, err := s.Progress(context.TODO(), tt.character, tt.windows...); !errors.Is(err, tt.expected) {
+				t.Errorf("expected error %v, got = %v", tt.expected, err)
+			}
+		})
+	}
+}
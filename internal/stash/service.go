@@ -0,0 +1,55 @@
+package stash
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./service_mocks.go . parser
+
+// The name regexp required for character names, the same strict diablo rules
+// the character service enforces.
+var nameRegexp = regexp.MustCompile("^[a-zA-Z]+[_-]?[a-zA-Z]+$")
+
+// The account regexp allows the characters a battle.net account name may
+// contain, it never allows a path separator or a leading dot.
+var accountRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-\[\]][a-zA-Z0-9_\-\.\[\]]*$`)
+
+// parser is the interface representation of the PlugY stash parser the service depend on.
+type parser interface {
+	ParseStash(name string) (*domain.Stash, error)
+	ParseSharedStash(account string) (*domain.Stash, error)
+}
+
+// Service performs all operations on PlugY stashes.
+type Service struct {
+	parser parser
+}
+
+// Stash will parse the personal stash of the character.
+func (s Service) Stash(ctx context.Context, name string) (*domain.Stash, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid character name: %w", domain.ErrRequest)
+	}
+
+	return s.parser.ParseStash(name)
+}
+
+// SharedStash will parse the stash shared by the characters of the account.
+func (s Service) SharedStash(ctx context.Context, account string) (*domain.Stash, error) {
+	if !accountRegexp.MatchString(account) {
+		return nil, fmt.Errorf("invalid account name: %w", domain.ErrRequest)
+	}
+
+	return s.parser.ParseSharedStash(account)
+}
+
+// NewService constructs a new stash service with all the dependencies.
+func NewService(parser parser) *Service {
+	return &Service{
+		parser: parser,
+	}
+}
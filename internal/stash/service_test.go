@@ -0,0 +1,96 @@
+package stash
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestStash(t *testing.T) {
+	tests := []struct {
+		name          string
+		character     string
+		parseCalls    int
+		expectedError error
+	}{
+		{
+			name:       "valid character name",
+			character:  "nokka",
+			parseCalls: 1,
+		},
+		{
+			name:          "invalid character name",
+			character:     "../nokka",
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &parserMock{
+				ParseStashFunc: func(name string) (*domain.Stash, error) {
+					return &domain.Stash{Owner: name}, nil
+				},
+			}
+
+			s := NewService(parser)
+
+			_, err := s.Stash(context.TODO(), tt.character)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if len(parser.ParseStashCalls()) != tt.parseCalls {
+				t.Errorf("expected parser.ParseStash() to be called exactly %d times but was called %d times", tt.parseCalls, len(parser.ParseStashCalls()))
+			}
+		})
+	}
+}
+
+func TestSharedStash(t *testing.T) {
+	tests := []struct {
+		name          string
+		account       string
+		parseCalls    int
+		expectedError error
+	}{
+		{
+			name:       "valid account name",
+			account:    "[SD]nokka-2",
+			parseCalls: 1,
+		},
+		{
+			name:          "account with path separator",
+			account:       "nokka/../secret",
+			expectedError: domain.ErrRequest,
+		},
+		{
+			name:          "account starting with a dot",
+			account:       "..",
+			expectedError: domain.ErrRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &parserMock{
+				ParseSharedStashFunc: func(account string) (*domain.Stash, error) {
+					return &domain.Stash{Owner: account, Shared: true}, nil
+				},
+			}
+
+			s := NewService(parser)
+
+			_, err := s.SharedStash(context.TODO(), tt.account)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got = %v", tt.expectedError, err)
+			}
+
+			if len(parser.ParseSharedStashCalls()) != tt.parseCalls {
+				t.Errorf("expected parser.ParseSharedStash() to be called exactly %d times but was called %d times", tt.parseCalls, len(parser.ParseSharedStashCalls()))
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 
@@ -16,7 +17,7 @@ var validDifficulties = map[string]struct{}{
 	domain.DifficultyHell:      {},
 }
 
-//go:generate moq -out ./service_mocks.go . statisticsRepository
+//go:generate moq -out ./service_mocks.go . statisticsRepository accountLinker listener
 
 // Max data points is used to limit number of data points being returned
 // since areas for example can host 138 entries.
@@ -30,9 +31,38 @@ type statisticsRepository interface {
 	Delete(ctx context.Context, character string) error
 }
 
+// accountLinker links characters to the account they were played on.
+type accountLinker interface {
+	Link(ctx context.Context, account string, character string) error
+}
+
+// listener is notified every time statistics of a character have been stored.
+type listener interface {
+	StatisticsPosted(ctx context.Context, stat domain.StatisticsRequest)
+}
+
 // Service performs all operations on statistics.
 type Service struct {
 	repository statisticsRepository
+	accounts   accountLinker
+	listeners  []listener
+}
+
+// Option is used to configure optional behaviour of the service.
+type Option func(s *Service)
+
+// WithAccountLinker links the character of every statistics request to its account.
+func WithAccountLinker(l accountLinker) Option {
+	return func(s *Service) {
+		s.accounts = l
+	}
+}
+
+// WithListener registers a listener that is notified when statistics have been stored.
+func WithListener(l listener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, l)
+	}
 }
 
 // GetCharacter will get the statistics on a specific character.
@@ -149,6 +179,9 @@ func (s Service) Parse(ctx context.Context, stats []domain.StatisticsRequest) er
 			return fmt.Errorf("difficulty %s supplied for character %s, %w", req.Difficulty, req.Character, domain.ErrRequest)
 		}
 
+		// Accounts link the exact name, the binary of the character is named by it.
+		character := req.Character
+
 		// Lower case the character name to keep consistency.
 		req.Account = strings.ToLower(req.Account)
 		req.Character = strings.ToLower(req.Character)
@@ -158,6 +191,18 @@ func (s Service) Parse(ctx context.Context, stats []domain.StatisticsRequest) er
 		if err != nil {
 			return err
 		}
+
+		// The statistics are already counted, so failing to link the account
+		// mustn't fail the request and have it counted again on a retry.
+		if s.accounts != nil && req.Account != "" {
+			if err := s.accounts.Link(ctx, req.Account, character); err != nil {
+				log.Printf("failed to link character %s to account %s: %v", character, req.Account, err)
+			}
+		}
+
+		for _, l := range s.listeners {
+			l.StatisticsPosted(ctx, req)
+		}
 	}
 
 	return nil
@@ -172,8 +217,14 @@ func (s Service) DeleteStats(ctx context.Context, character string) error {
 }
 
 // NewService constructs a new statistics service with all the dependencies.
-func NewService(repository statisticsRepository) *Service {
-	return &Service{
+func NewService(repository statisticsRepository, opts ...Option) *Service {
+	s := &Service{
 		repository: repository,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
@@ -177,3 +177,151 @@ func (mock *statisticsRepositoryMock) UpsertCalls() []struct {
 	mock.lockUpsert.RUnlock()
 	return calls
 }
+
+// Ensure, that accountLinkerMock does implement accountLinker.
+// If this is not the case, regenerate this file with moq.
+var _ accountLinker = &accountLinkerMock{}
+
+// accountLinkerMock is a mock implementation of accountLinker.
+//
+// 	func TestSomethingThatUsesaccountLinker(t *testing.T) {
+//
+// 		// make and configure a mocked accountLinker
+// 		mockedaccountLinker := &accountLinkerMock{
+// 			LinkFunc: func(ctx context.Context, account string, character string) error {
+// 				panic("mock out the Link method")
+// 			},
+// 		}
+//
+// 		// use mockedaccountLinker in code that requires accountLinker
+// 		// and then make assertions.
+//
+// 	}
+type accountLinkerMock struct {
+	// LinkFunc mocks the Link method.
+	LinkFunc func(ctx context.Context, account string, character string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Link holds details about calls to the Link method.
+		Link []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account string
+			// Character is the character argument value.
+			Character string
+		}
+	}
+	lockLink sync.RWMutex
+}
+
+// Link calls LinkFunc.
+func (mock *accountLinkerMock) Link(ctx context.Context, account string, character string) error {
+	if mock.LinkFunc == nil {
+		panic("accountLinkerMock.LinkFunc: method is nil but accountLinker.Link was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Account   string
+		Character string
+	}{
+		Ctx:       ctx,
+		Account:   account,
+		Character: character,
+	}
+	mock.lockLink.Lock()
+	mock.calls.Link = append(mock.calls.Link, callInfo)
+	mock.lockLink.Unlock()
+	return mock.LinkFunc(ctx, account, character)
+}
+
+// LinkCalls gets all the calls that were made to Link.
+// Check the length with:
+//     len(mockedaccountLinker.LinkCalls())
+func (mock *accountLinkerMock) LinkCalls() []struct {
+	Ctx       context.Context
+	Account   string
+	Character string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Account   string
+		Character string
+	}
+	mock.lockLink.RLock()
+	calls = mock.calls.Link
+	mock.lockLink.RUnlock()
+	return calls
+}
+
+// Ensure, that listenerMock does implement listener.
+// If this is not the case, regenerate this file with moq.
+var _ listener = &listenerMock{}
+
+// listenerMock is a mock implementation of listener.
+//
+// 	func TestSomethingThatUseslistener(t *testing.T) {
+//
+// 		// make and configure a mocked listener
+// 		mockedlistener := &listenerMock{
+// 			StatisticsPostedFunc: func(ctx context.Context, stat domain.StatisticsRequest)  {
+// 				panic("mock out the StatisticsPosted method")
+// 			},
+// 		}
+//
+// 		// use mockedlistener in code that requires listener
+// 		// and then make assertions.
+//
+// 	}
+type listenerMock struct {
+	// StatisticsPostedFunc mocks the StatisticsPosted method.
+	StatisticsPostedFunc func(ctx context.Context, stat domain.StatisticsRequest)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// StatisticsPosted holds details about calls to the StatisticsPosted method.
+		StatisticsPosted []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Stat is the stat argument value.
+			Stat domain.StatisticsRequest
+		}
+	}
+	lockStatisticsPosted sync.RWMutex
+}
+
+// StatisticsPosted calls StatisticsPostedFunc.
+func (mock *listenerMock) StatisticsPosted(ctx context.Context, stat domain.StatisticsRequest) {
+	if mock.StatisticsPostedFunc == nil {
+		panic("listenerMock.StatisticsPostedFunc: method is nil but listener.StatisticsPosted was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Stat domain.StatisticsRequest
+	}{
+		Ctx:  ctx,
+		Stat: stat,
+	}
+	mock.lockStatisticsPosted.Lock()
+	mock.calls.StatisticsPosted = append(mock.calls.StatisticsPosted, callInfo)
+	mock.lockStatisticsPosted.Unlock()
+	mock.StatisticsPostedFunc(ctx, stat)
+}
+
+// StatisticsPostedCalls gets all the calls that were made to StatisticsPosted.
+// Check the length with:
+//     len(mockedlistener.StatisticsPostedCalls())
+func (mock *listenerMock) StatisticsPostedCalls() []struct {
+	Ctx  context.Context
+	Stat domain.StatisticsRequest
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Stat domain.StatisticsRequest
+	}
+	mock.lockStatisticsPosted.RLock()
+	calls = mock.calls.StatisticsPosted
+	mock.lockStatisticsPosted.RUnlock()
+	return calls
+}
@@ -106,6 +106,70 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLinksAccounts(t *testing.T) {
+	repository := &statisticsRepositoryMock{
+		UpsertFunc: func(ctx context.Context, stat domain.StatisticsRequest) error {
+			return nil
+		},
+	}
+
+	linker := &accountLinkerMock{
+		LinkFunc: func(ctx context.Context, account string, character string) error {
+			return errors.New("something went wrong")
+		},
+	}
+
+	s := NewService(repository, WithAccountLinker(linker))
+
+	err := s.Parse(context.TODO(), []domain.StatisticsRequest{
+		{Account: "Nokka", Character: "Nokka", Difficulty: domain.DifficultyHell},
+		{Character: "wheelz", Difficulty: domain.DifficultyHell},
+	})
+	if err != nil {
+		t.Errorf("didn't expect failing to link an account to fail the request, got = %v", err)
+	}
+
+	calls := linker.LinkCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected accountLinker.Link() to be called exactly 1 time but was called %d times", len(calls))
+	}
+
+	if calls[0].Account != "nokka" || calls[0].Character != "Nokka" {
+		t.Errorf("expected a lower cased account and the exact character, got = %s %s", calls[0].Account, calls[0].Character)
+	}
+}
+
+func TestParseNotifiesListeners(t *testing.T) {
+	repository := &statisticsRepositoryMock{
+		UpsertFunc: func(ctx context.Context, stat domain.StatisticsRequest) error {
+			if stat.Character == "wheelz" {
+				return errors.New("something went wrong")
+			}
+			return nil
+		},
+	}
+
+	l := &listenerMock{
+		StatisticsPostedFunc: func(ctx context.Context, stat domain.StatisticsRequest) {},
+	}
+
+	s := NewService(repository, WithListener(l))
+
+	_ = s.Parse(context.TODO(), []domain.StatisticsRequest{
+		{Character: "Nokka", Difficulty: domain.DifficultyHell},
+		{Character: "wheelz", Difficulty: domain.DifficultyHell},
+	})
+
+	calls := l.StatisticsPostedCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected listener.StatisticsPosted() to be called only for stored statistics, was called %d times", len(calls))
+	}
+
+	if calls[0].Stat.Character != "nokka" {
+		t.Errorf("expected lower cased character, got = %s", calls[0].Stat.Character)
+	}
+}
+
 func TestGetCharacter(t *testing.T) {
 	type args struct {
 		ctx  context.Context
@@ -0,0 +1,202 @@
+package stats
+
+import (
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// Forms the breakpoints apply to, the human form applies to every class.
+const (
+	formHuman          = "Human"
+	formWerewolf       = "Werewolf"
+	formWerebear       = "Werebear"
+	formLightning      = "Lightning"
+	formHolyShield     = "Holy Shield"
+	formStandardAttack = "Standard Attack"
+)
+
+// table is the least of a stat needed for every tier, the first tier takes
+// the most frames and every tier after it a frame less.
+type table struct {
+	form   string
+	frames int64
+	values []int64
+}
+
+// tiers returns the frames of every tier of the table.
+func (t table) tiers() []domain.BreakpointTier {
+	tiers := make([]domain.BreakpointTier, 0, len(t.values))
+	for i, value := range t.values {
+		tiers = append(tiers, domain.BreakpointTier{Value: value, Frames: t.frames - int64(i)})
+	}
+
+	return tiers
+}
+
+// castRates are the faster cast rate tables of every class, the sorceress
+// casts Lightning and Chain Lightning slower than her other spells.
+var castRates = map[uint64][]table{
+	d2s.Amazon:      {{formHuman, 19, []int64{0, 7, 14, 22, 32, 48, 68, 99, 152}}},
+	d2s.Sorceress:   {{formHuman, 13, []int64{0, 9, 20, 37, 63, 105, 200}}, {formLightning, 19, []int64{0, 7, 15, 23, 35, 52, 78, 117, 194}}},
+	d2s.Necromancer: {{formHuman, 15, []int64{0, 9, 18, 30, 48, 75, 125}}},
+	d2s.Paladin:     {{formHuman, 15, []int64{0, 9, 18, 30, 48, 75, 125}}},
+	d2s.Barbarian:   {{formHuman, 13, []int64{0, 9, 20, 37, 63, 105, 200}}},
+	d2s.Druid: {
+		{formHuman, 18, []int64{0, 4, 10, 19, 30, 46, 68, 99, 163}},
+		{formWerewolf, 16, []int64{0, 6, 14, 26, 40, 60, 95, 157}},
+		{formWerebear, 16, []int64{0, 7, 15, 26, 40, 63, 99, 163}},
+	},
+	d2s.Assassin: {{formHuman, 16, []int64{0, 8, 16, 27, 42, 65, 102, 174}}},
+}
+
+// hitRecoveries are the faster hit recovery tables of every class.
+var hitRecoveries = map[uint64][]table{
+	d2s.Amazon:      {{formHuman, 11, []int64{0, 6, 13, 20, 32, 52, 86, 174, 600}}},
+	d2s.Sorceress:   {{formHuman, 15, []int64{0, 5, 9, 14, 20, 30, 42, 60, 86, 142, 280}}},
+	d2s.Necromancer: {{formHuman, 13, []int64{0, 5, 10, 16, 26, 39, 56, 86, 152, 377}}},
+	d2s.Paladin:     {{formHuman, 9, []int64{0, 7, 15, 27, 48, 86, 200}}},
+	d2s.Barbarian:   {{formHuman, 9, []int64{0, 7, 15, 27, 48, 86, 200}}},
+	d2s.Druid: {
+		{formHuman, 13, []int64{0, 3, 7, 13, 19, 29, 42, 63, 99, 174, 456}},
+		{formWerewolf, 7, []int64{0, 9, 20, 42, 86, 280}},
+		{formWerebear, 13, []int64{0, 5, 10, 16, 26, 39, 56, 86, 152, 377}},
+	},
+	d2s.Assassin: {{formHuman, 9, []int64{0, 7, 15, 27, 48, 86, 200}}},
+}
+
+// blockRates are the faster block rate tables of every class, blocking with a shield.
+var blockRates = map[uint64][]table{
+	d2s.Amazon:      {{formHuman, 5, []int64{0, 13, 32, 86, 600}}},
+	d2s.Sorceress:   {{formHuman, 9, []int64{0, 7, 15, 27, 48, 86, 200}}},
+	d2s.Necromancer: {{formHuman, 11, []int64{0, 6, 13, 20, 32, 52, 86, 174, 600}}},
+	d2s.Paladin:     {{formHuman, 5, []int64{0, 13, 32, 86, 600}}, {formHolyShield, 2, []int64{0, 86}}},
+	d2s.Barbarian:   {{formHuman, 7, []int64{0, 9, 20, 42, 86, 280}}},
+	d2s.Druid: {
+		{formHuman, 11, []int64{0, 6, 13, 20, 32, 52, 86, 174, 600}},
+		{formWerewolf, 9, []int64{0, 7, 15, 27, 48, 86, 200}},
+		{formWerebear, 12, []int64{0, 7, 15, 27, 48, 86, 200}},
+	},
+	d2s.Assassin: {{formHuman, 5, []int64{0, 13, 32, 86, 600}}},
+}
+
+// attackFrames are the frames of the standard attack of every class with
+// every animation class of weapons.
+var attackFrames = map[uint64]map[string]int64{
+	d2s.Amazon:      {animHandToHand: 13, animOneHandSwing: 16, animOneHandThrust: 15, animTwoHandSwing: 20, animTwoHandThrust: 18, animStaff: 20, animBow: 14, animCrossbow: 20},
+	d2s.Sorceress:   {animHandToHand: 16, animOneHandSwing: 20, animOneHandThrust: 19, animTwoHandSwing: 24, animTwoHandThrust: 23, animStaff: 18, animBow: 17, animCrossbow: 20},
+	d2s.Necromancer: {animHandToHand: 15, animOneHandSwing: 19, animOneHandThrust: 17, animTwoHandSwing: 23, animTwoHandThrust: 24, animStaff: 20, animBow: 18, animCrossbow: 20},
+	d2s.Paladin:     {animHandToHand: 14, animOneHandSwing: 15, animOneHandThrust: 16, animTwoHandSwing: 16, animTwoHandThrust: 18, animStaff: 16, animBow: 16, animCrossbow: 20},
+	d2s.Barbarian:   {animHandToHand: 12, animOneHandSwing: 16, animOneHandThrust: 16, animTwoHandSwing: 18, animTwoHandThrust: 19, animStaff: 19, animBow: 15, animCrossbow: 20},
+	d2s.Druid:       {animHandToHand: 16, animOneHandSwing: 19, animOneHandThrust: 19, animTwoHandSwing: 21, animTwoHandThrust: 23, animStaff: 17, animBow: 16, animCrossbow: 20},
+	d2s.Assassin:    {animHandToHand: 11, animOneHandSwing: 15, animOneHandThrust: 15, animTwoHandSwing: 23, animTwoHandThrust: 23, animStaff: 19, animBow: 16, animCrossbow: 21},
+}
+
+// Increased attack speed has diminishing returns and the speed of an attack is capped.
+const (
+	maxAttackSpeed = 75
+	maxIAS         = 600
+)
+
+// Breakpoints returns the tiers of the speed stats the character is at with its gear applied.
+func Breakpoints(character *d2s.Character) *domain.Breakpoints {
+	class := uint64(character.Header.Class)
+	stats := Compute(character)
+
+	tiers := &domain.Breakpoints{
+		Class:                character.Header.Class.String(),
+		FasterCastRate:       breakpoints(castRates[class], stats.FasterCastRate),
+		FasterHitRecovery:    breakpoints(hitRecoveries[class], stats.FasterHitRecovery),
+		FasterBlockRate:      breakpoints(blockRates[class], stats.FasterBlockRate),
+		IncreasedAttackSpeed: []domain.Breakpoint{},
+	}
+
+	name, w := primaryWeapon(character)
+	tiers.Weapon = name
+
+	if frames, ok := attackFrames[class][w.anim]; ok {
+		tiers.IncreasedAttackSpeed = []domain.Breakpoint{
+			breakpoint(formStandardAttack, attackTiers(frames, w.speed), stats.IncreasedAttackSpeed),
+		}
+	}
+
+	return tiers
+}
+
+// attackTiers returns the tiers of attack speed of the standard attack, they
+// depend on the weapon so they're found by going through the attack speeds
+// until the speed of the attack is capped.
+func attackTiers(frames int64, weaponSpeed int64) []domain.BreakpointTier {
+	var tiers []domain.BreakpointTier
+	for ias := int64(0); ias <= maxIAS; ias++ {
+		f := attackFrame(frames, weaponSpeed, ias)
+		if len(tiers) == 0 || f < tiers[len(tiers)-1].Frames {
+			tiers = append(tiers, domain.BreakpointTier{Value: ias, Frames: f})
+		}
+	}
+
+	return tiers
+}
+
+// attackFrame returns the frames of a standard attack with the attack speed.
+func attackFrame(frames int64, weaponSpeed int64, ias int64) int64 {
+	effective := 120 * ias / (120 + ias)
+	speed := 100 + min(effective-weaponSpeed, maxAttackSpeed)
+
+	increment := 256 * speed / 100
+	return (256*frames+increment-1)/increment - 1
+}
+
+// primaryWeapon returns the name and weapon the character attacks with, the
+// weapon in the right hand of the active weapon set, or the left hand when
+// the right hand holds none. Characters without a weapon attack with their hands.
+func primaryWeapon(character *d2s.Character) (string, weapon) {
+	hands := []uint64{slotRightHand, slotLeftHand}
+	if character.Header.ActiveArms == 1 {
+		hands = []uint64{slotRightHandSwap, slotLeftHandSwap}
+	}
+
+	for _, hand := range hands {
+		for _, item := range character.Items {
+			if domain.ItemLocation(item) != domain.LocationEquipped || item.EquippedID != hand {
+				continue
+			}
+
+			if w, ok := weaponOf(item.Type); ok {
+				return item.TypeName, w
+			}
+		}
+	}
+
+	return "", weapon{anim: animHandToHand}
+}
+
+// breakpoints returns the tier of the stat in every table.
+func breakpoints(tables []table, value int64) []domain.Breakpoint {
+	list := make([]domain.Breakpoint, 0, len(tables))
+	for _, t := range tables {
+		list = append(list, breakpoint(t.form, t.tiers(), value))
+	}
+
+	return list
+}
+
+// breakpoint returns the tier the stat is at, and the next tier when there's one.
+func breakpoint(form string, tiers []domain.BreakpointTier, value int64) domain.Breakpoint {
+	b := domain.Breakpoint{
+		Form:   form,
+		Value:  value,
+		Frames: tiers[0].Frames,
+		Tiers:  tiers,
+	}
+
+	for _, tier := range tiers {
+		if tier.Value > value {
+			b.Next = &domain.NextBreakpoint{BreakpointTier: tier, Needed: tier.Value - value}
+			break
+		}
+
+		b.Frames = tier.Frames
+	}
+
+	return b
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestBreakpoints(t *testing.T) {
+	sorceress := &d2s.Character{
+		Items: items(t, `[
+			{"type_name": "Amulet", "location_id": 1, "equipped_id": 2, "magic_attributes": [{"id": 105, "values": [105]}]}
+		]`),
+	}
+	sorceress.Header.Class = d2s.Sorceress
+
+	breakpoints := Breakpoints(sorceress)
+
+	tests := []struct {
+		form     string
+		frames   int64
+		expected *domain.NextBreakpoint
+	}{
+		{formHuman, 8, &domain.NextBreakpoint{BreakpointTier: domain.BreakpointTier{Value: 200, Frames: 7}, Needed: 95}},
+		{formLightning, 13, &domain.NextBreakpoint{BreakpointTier: domain.BreakpointTier{Value: 117, Frames: 12}, Needed: 12}},
+	}
+
+	if len(breakpoints.FasterCastRate) != len(tests) {
+		t.Fatalf("expected %d faster cast rate breakpoints, got = %d", len(tests), len(breakpoints.FasterCastRate))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.form, func(t *testing.T) {
+			breakpoint := breakpoints.FasterCastRate[i]
+
+			if breakpoint.Form != tt.form || breakpoint.Frames != tt.frames {
+				t.Errorf("expected %s at %d frames, got = %s at %d frames", tt.form, tt.frames, breakpoint.Form, breakpoint.Frames)
+			}
+
+			if breakpoint.Next == nil || *breakpoint.Next != *tt.expected {
+				t.Errorf("unexpected next breakpoint, expected = %+v, got = %+v", tt.expected, breakpoint.Next)
+			}
+		})
+	}
+}
+
+func TestBreakpointsShapeshifted(t *testing.T) {
+	druid := &d2s.Character{
+		Items: items(t, `[
+			{"type_name": "Amulet", "location_id": 1, "equipped_id": 2, "magic_attributes": [{"id": 105, "values": [63]}]}
+		]`),
+	}
+	druid.Header.Class = d2s.Druid
+
+	breakpoints := Breakpoints(druid)
+
+	if len(breakpoints.FasterCastRate) != 3 {
+		t.Fatalf("expected a faster cast rate breakpoint for every form, got = %d", len(breakpoints.FasterCastRate))
+	}
+
+	werebear := breakpoints.FasterCastRate[2]
+	if werebear.Form != formWerebear || werebear.Frames != 11 {
+		t.Errorf("expected a Werebear to cast in 11 frames, got = %s at %d frames", werebear.Form, werebear.Frames)
+	}
+}
+
+func TestBreakpointsLastTier(t *testing.T) {
+	tiers := breakpoint(formHuman, table{frames: 5, values: []int64{0, 13, 32, 86, 600}}.tiers(), 600)
+
+	if tiers.Frames != 1 || tiers.Next != nil {
+		t.Errorf("expected the last tier at 1 frame, got = %d frames, next = %+v", tiers.Frames, tiers.Next)
+	}
+}
+
+func TestBreakpointsAttackSpeed(t *testing.T) {
+	breakpoints := Breakpoints(barbarian(t))
+
+	if breakpoints.Weapon != "Berserker Axe" {
+		t.Errorf("expected the attack speed of the Berserker Axe, got = %s", breakpoints.Weapon)
+	}
+
+	if len(breakpoints.IncreasedAttackSpeed) != 1 {
+		t.Fatalf("expected a single attack speed breakpoint, got = %d", len(breakpoints.IncreasedAttackSpeed))
+	}
+
+	// 16 frames without attack speed, 20 attack speed is 17 effective attack speed.
+	attack := breakpoints.IncreasedAttackSpeed[0]
+	if attack.Tiers[0].Frames != 15 || attack.Frames != 13 {
+		t.Errorf("expected 15 frames without attack speed and 13 frames with it, got = %d and %d", attack.Tiers[0].Frames, attack.Frames)
+	}
+
+	for i := 1; i < len(attack.Tiers); i++ {
+		if attack.Tiers[i].Frames >= attack.Tiers[i-1].Frames || attack.Tiers[i].Value <= attack.Tiers[i-1].Value {
+			t.Errorf("expected every tier to take less frames with more attack speed, got = %+v", attack.Tiers)
+		}
+	}
+
+	// Without a weapon in the active weapon set the character attacks with its hands.
+	char := barbarian(t)
+	char.Header.ActiveArms = 1
+
+	if breakpoints := Breakpoints(char); breakpoints.Weapon != "" || breakpoints.IncreasedAttackSpeed[0].Tiers[0].Frames != 11 {
+		t.Errorf("expected a hand to hand attack, got = %+v", breakpoints.IncreasedAttackSpeed)
+	}
+}
@@ -0,0 +1,89 @@
+// Package stats computes the stats of characters with the attributes of their items added up.
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./service_mocks.go . characterService
+
+// characterService is the interface representation of the character
+// service the stats are computed from.
+type characterService interface {
+	Parse(ctx context.Context, name string) (*domain.Character, error)
+}
+
+// Service computes the effective stats of characters.
+type Service struct {
+	characterService characterService
+}
+
+// EffectiveStats returns the stats of the character with its gear applied.
+func (s Service) EffectiveStats(ctx context.Context, name string) (*domain.EffectiveStats, error) {
+	char, err := s.character(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := Compute(char.D2s)
+	stats.Character = char.ID
+
+	return stats, nil
+}
+
+// Breakpoints returns the tiers of the speed stats of the character with its gear applied.
+func (s Service) Breakpoints(ctx context.Context, name string) (*domain.Breakpoints, error) {
+	char, err := s.character(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	breakpoints := Breakpoints(char.D2s)
+	breakpoints.Character = char.ID
+
+	return breakpoints, nil
+}
+
+// View returns a view of the character with its skills decoded.
+func (s Service) View(ctx context.Context, name string) (*domain.CharacterView, error) {
+	char, err := s.character(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CharacterView{
+		Character: char.ID,
+		Class:     char.D2s.Header.Class.String(),
+		Level:     uint64(char.D2s.Header.Level),
+		Skills:    Skills(char.D2s),
+	}, nil
+}
+
+// character returns the parsed character, stats can't be computed for deleted
+// characters or characters without a binary.
+func (s Service) character(ctx context.Context, name string) (*domain.Character, error) {
+	if name == "" {
+		return nil, fmt.Errorf("character name is required: %w", domain.ErrRequest)
+	}
+
+	char, err := s.characterService.Parse(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := char.Decoded(); err != nil {
+		return nil, err
+	}
+
+	return char, nil
+}
+
+// NewService constructs a new stats service with all the dependencies.
+func NewService(characterService characterService) *Service {
+	return &Service{
+		characterService: characterService,
+	}
+}
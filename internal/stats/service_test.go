@@ -0,0 +1,256 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// items decodes items from their JSON representation, the attributes of d2s
+// items can't be constructed outside of the package.
+func items(t *testing.T, data string) []d2s.Item {
+	t.Helper()
+
+	var i []d2s.Item
+	if err := json.Unmarshal([]byte(data), &i); err != nil {
+		t.Fatalf("failed to decode items: %v", err)
+	}
+
+	return i
+}
+
+func barbarian(t *testing.T) *d2s.Character {
+	char := &d2s.Character{
+		Attributes: d2s.Attributes{Strength: 100, Dexterity: 50, Vitality: 200, Energy: 10, MaxHP: 500, MaxMana: 50},
+		Items: items(t, `[
+			{"type_name": "Shako", "location_id": 1, "equipped_id": 1, "magic_attributes": [
+				{"id": 7, "values": [20]},
+				{"id": 3, "values": [2]},
+				{"id": 39, "values": [30]},
+				{"id": 40, "values": [5]},
+				{"id": 216, "values": [4]}
+			]},
+			{"type_name": "Berserker Axe", "type": "7wa", "location_id": 1, "equipped_id": 4, "magic_attributes": [
+				{"id": 93, "values": [20]}
+			], "socketed_items": [
+				{"type": "jew", "magic_attributes": [{"id": 39, "values": [10]}]}
+			]},
+			{"type_name": "Crystal Sword", "location_id": 1, "equipped_id": 11, "magic_attributes": [
+				{"id": 105, "values": [20]}
+			]},
+			{"type_name": "Gothic Plate", "set_name": "Sigon's Shelter", "quality": 5, "set_id": 39,
+				"location_id": 1, "equipped_id": 3, "magic_attributes": [{"id": 76, "values": [10]}],
+				"set_attributes": [[{"id": 39, "values": [40]}], [{"id": 80, "values": [100]}]],
+				"set_attributes_num_req": [2, 3]
+			},
+			{"type_name": "Gauntlets", "set_name": "Sigon's Gage", "quality": 5, "set_id": 35,
+				"location_id": 1, "equipped_id": 10, "magic_attributes": [{"id": 0, "values": [10]}]
+			},
+			{"type_name": "Small Charm", "type": "cm1", "location_id": 0, "alt_position_id": 1, "magic_attributes": [
+				{"id": 43, "values": [5]},
+				{"id": 80, "values": [7]}
+			]},
+			{"type_name": "Small Charm", "type": "cm1", "location_id": 0, "alt_position_id": 5, "magic_attributes": [
+				{"id": 80, "values": [100]}
+			]}
+		]`),
+	}
+
+	char.Header.Class = d2s.Barbarian
+	char.Header.Level = 80
+	// The scroll of resistance has been read in normal.
+	char.Header.QuestsNormal.ActV.PrisonOfIce = [2]byte{0x80, 0}
+
+	return char
+}
+
+func TestCompute(t *testing.T) {
+	stats := Compute(barbarian(t))
+
+	expected := domain.EffectiveAttributes{Strength: 110, Dexterity: 50, Vitality: 202, Energy: 10}
+	if stats.Attributes != expected {
+		t.Errorf("unexpected attributes, expected = %+v, got = %+v", expected, stats.Attributes)
+	}
+
+	// 500 + 2 vitality * 4 + 20 + 80 * 4 / 8, with 10% more.
+	if stats.Life != 624 {
+		t.Errorf("expected life 624, got = %d", stats.Life)
+	}
+
+	if stats.IncreasedAttackSpeed != 20 {
+		t.Errorf("expected the attack speed of the active weapon, got = %d", stats.IncreasedAttackSpeed)
+	}
+
+	if stats.FasterCastRate != 0 {
+		t.Errorf("expected the swap weapon to be left out, got = %d faster cast rate", stats.FasterCastRate)
+	}
+
+	// Only the charm in the inventory counts, and only the two item set bonus is active.
+	if stats.MagicFind != 7 {
+		t.Errorf("expected magic find 7, got = %d", stats.MagicFind)
+	}
+
+	if len(stats.Sources) != 5 {
+		t.Errorf("expected 5 sources, got = %q", stats.Sources)
+	}
+
+	if len(stats.UnknownSetBonuses) != 1 || stats.UnknownSetBonuses[0] != "Sigon's Complete Steel" {
+		t.Errorf("expected the bonuses of Sigon's Complete Steel to be unknown, got = %q", stats.UnknownSetBonuses)
+	}
+
+	tests := []struct {
+		difficulty string
+		fire       domain.Resistance
+		cold       domain.Resistance
+	}{
+		{domain.DifficultyNormal, domain.Resistance{Value: 80, Uncapped: 90, Max: 80}, domain.Resistance{Value: 15, Uncapped: 15, Max: 75}},
+		{domain.DifficultyNightmare, domain.Resistance{Value: 50, Uncapped: 50, Max: 80}, domain.Resistance{Value: -25, Uncapped: -25, Max: 75}},
+		{domain.DifficultyHell, domain.Resistance{Value: -10, Uncapped: -10, Max: 80}, domain.Resistance{Value: -85, Uncapped: -85, Max: 75}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.difficulty, func(t *testing.T) {
+			resistances := stats.Resistances[tt.difficulty]
+
+			if resistances.Fire != tt.fire {
+				t.Errorf("unexpected fire resistance, expected = %+v, got = %+v", tt.fire, resistances.Fire)
+			}
+
+			if resistances.Cold != tt.cold {
+				t.Errorf("unexpected cold resistance, expected = %+v, got = %+v", tt.cold, resistances.Cold)
+			}
+		})
+	}
+}
+
+func TestComputeSwapWeapons(t *testing.T) {
+	char := barbarian(t)
+	char.Header.ActiveArms = 1
+
+	stats := Compute(char)
+
+	if stats.FasterCastRate != 20 || stats.IncreasedAttackSpeed != 0 {
+		t.Errorf("expected the swap weapon to be active, got = %d faster cast rate, %d attack speed", stats.FasterCastRate, stats.IncreasedAttackSpeed)
+	}
+}
+
+func TestComputeSetBonuses(t *testing.T) {
+	// Tal Rasha's Wrappings, the ids of its five items are 76 up to 80.
+	tals := items(t, `[
+		{"type_name": "Lacquered Plate", "quality": 5, "set_id": 76, "location_id": 1, "equipped_id": 3},
+		{"type_name": "Amulet", "quality": 5, "set_id": 77, "location_id": 1, "equipped_id": 2},
+		{"type_name": "Mesh Belt", "quality": 5, "set_id": 78, "location_id": 1, "equipped_id": 8},
+		{"type_name": "Swirling Crystal", "quality": 5, "set_id": 79, "location_id": 1, "equipped_id": 4},
+		{"type_name": "Death Mask", "quality": 5, "set_id": 80, "location_id": 1, "equipped_id": 1}
+	]`)
+
+	tests := []struct {
+		name        string
+		items       []d2s.Item
+		allSkills   int64
+		magicFind   int64
+		hitRecovery int64
+		fireResist  int64
+		lifeStolen  int64
+	}{
+		{"complete set", tals, 3, 65, 25, 50, 0},
+		{"one item short of the complete set", tals[:4], 0, 0, 0, 0, 0},
+		{"partial set", items(t, `[
+			{"type_name": "Tiara", "quality": 5, "set_id": 47, "location_id": 1, "equipped_id": 1},
+			{"type_name": "Buckler", "quality": 5, "set_id": 48, "location_id": 1, "equipped_id": 5}
+		]`), 0, 0, 0, 0, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			char := &d2s.Character{Items: tt.items}
+			char.Header.Class = d2s.Sorceress
+
+			stats := Compute(char)
+
+			if stats.AllSkills != tt.allSkills || stats.MagicFind != tt.magicFind || stats.FasterHitRecovery != tt.hitRecovery || stats.LifeStolen != tt.lifeStolen {
+				t.Errorf("unexpected set bonuses, got = %d all skills, %d magic find, %d faster hit recovery, %d life stolen", stats.AllSkills, stats.MagicFind, stats.FasterHitRecovery, stats.LifeStolen)
+			}
+
+			if fire := stats.Resistances[domain.DifficultyNormal].Fire.Uncapped; fire != tt.fireResist {
+				t.Errorf("expected fire resistance %d, got = %d", tt.fireResist, fire)
+			}
+		})
+	}
+}
+
+func TestEffectiveStats(t *testing.T) {
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		character *domain.Character
+		err       error
+		expected  error
+	}{
+		{
+			name:      "effective stats",
+			character: &domain.Character{ID: "nokka", D2s: barbarian(t)},
+		},
+		{
+			name:      "deleted character",
+			character: &domain.Character{ID: "nokka", D2s: barbarian(t), DeletedAt: &deletedAt},
+			expected:  domain.ErrGone,
+		},
+		{
+			name:      "character without binary",
+			character: &domain.Character{ID: "nokka"},
+			expected:  domain.ErrNotFound,
+		},
+		{
+			name:     "character service error",
+			err:      domain.ErrNotFound,
+			expected: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(&characterServiceMock{
+				ParseFunc: func(ctx context.Context, name string) (*domain.Character, error) {
+					return tt.character, tt.err
+				},
+			})
+
+			stats, err := s.EffectiveStats(context.TODO(), "nokka")
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("expected error %v, got = %v", tt.expected, err)
+			}
+
+			if tt.expected == nil && stats.Character != "nokka" {
+				t.Errorf("expected the stats of nokka, got = %s", stats.Character)
+			}
+		})
+	}
+}
+
+func TestView(t *testing.T) {
+	s := NewService(&characterServiceMock{
+		ParseFunc: func(ctx context.Context, name string) (*domain.Character, error) {
+			return &domain.Character{ID: name, D2s: barbarian(t)}, nil
+		},
+	})
+
+	view, err := s.View(context.TODO(), "nokka")
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if view.Character != "nokka" || view.Class != "Barbarian" || view.Level != 80 {
+		t.Errorf("unexpected view, got = %+v", view)
+	}
+
+	if len(view.Skills) != skillsPerClass {
+		t.Errorf("expected the %d skills of the class, got = %d", skillsPerClass, len(view.Skills))
+	}
+}
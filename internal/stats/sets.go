@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// setRanges are the last set item id of every set, set items are numbered
+// by set in the game data so a set item belongs to the first set ending at
+// or after its id.
+var setRanges = []struct {
+	last uint64
+	name string
+}{
+	{2, "Civerb's Vestments"},
+	{5, "Hsarus' Defense"},
+	{8, "Cleglaw's Brace"},
+	{12, "Iratha's Finery"},
+	{16, "Isenhart's Armory"},
+	{20, "Vidala's Rig"},
+	{24, "Milabrega's Regalia"},
+	{29, "Cathan's Traps"},
+	{34, "Tancred's Battlegear"},
+	{40, "Sigon's Complete Steel"},
+	{43, "Infernal Tools"},
+	{46, "Berserker's Garb"},
+	{49, "Death's Disguise"},
+	{53, "Angelic Raiment"},
+	{57, "Arctic Gear"},
+	{61, "Arcanna's Tricks"},
+	{65, "Natalya's Odium"},
+	{69, "Aldur's Watchtower"},
+	{75, "Immortal King"},
+	{80, "Tal Rasha's Wrappings"},
+	{84, "Griswold's Legacy"},
+	{89, "Trang-Oul's Avatar"},
+	{94, "M'avina's Battle Hymn"},
+	{99, "The Disciple"},
+	{103, "Heaven's Brethren"},
+	{107, "Orphan's Call"},
+	{111, "Hwanin's Majesty"},
+	{114, "Sazabi's Grand Tribute"},
+	{116, "Bul-Kathos' Children"},
+	{119, "Cow King's Leathers"},
+	{122, "Naj's Ancient Vestige"},
+	{126, "Sander's Folly"},
+}
+
+// setOf returns the name of the set the set item belongs to.
+func setOf(setItem uint64) string {
+	for _, set := range setRanges {
+		if setItem <= set.last {
+			return set.name
+		}
+	}
+
+	return ""
+}
+
+// unknownSets returns the sets with enough items worn for set bonuses whose
+// bonuses aren't in the table, so they're missing from the stats.
+func unknownSets(worn []d2s.Item) []string {
+	count := make(map[string]int)
+	for _, item := range worn {
+		if item.Quality == domain.ItemQualitySet && domain.ItemLocation(item) == domain.LocationEquipped {
+			count[setOf(item.SetID)]++
+		}
+	}
+
+	unknown := []string{}
+	for set, n := range count {
+		if _, ok := setBonuses[set]; !ok && n >= 2 {
+			unknown = append(unknown, set)
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// setBonus is an attribute granted by a set rather than by one of its items.
+type setBonus struct {
+	id    uint64
+	value int64
+}
+
+// setBonuses are the bonuses of sets that aren't stored in the binary, keyed by
+// set name and the number of items of the set worn. Partial bonuses add up as
+// more items are worn, the bonuses keyed by the size of the set are those of
+// the complete set. Only the bonuses of the stats we compute are kept, and not
+// every set is in the table yet, see unknownSets.
+var setBonuses = map[string]map[int][]setBonus{
+	"Civerb's Vestments": {
+		2: {{attrStrength, 15}},
+	},
+	"Isenhart's Armory": {
+		2: {{attrStrength, 10}},
+		3: {{attrDexterity, 10}},
+	},
+	"Death's Disguise": {
+		2: {{attrLifeStolen, 8}},
+	},
+	"Angelic Raiment": {
+		4: append(allResistances(25), setBonus{attrMagicFind, 40}),
+	},
+	"Natalya's Odium": {
+		4: allResistances(50),
+	},
+	"Aldur's Watchtower": {
+		4: allResistances(50),
+	},
+	"Immortal King": {
+		6: allResistances(50),
+	},
+	"Tal Rasha's Wrappings": {
+		5: append(allResistances(50), setBonus{attrAllSkills, 3}, setBonus{attrMagicFind, 65}, setBonus{attrHitRecovery, 25}),
+	},
+	"Griswold's Legacy": {
+		4: allResistances(50),
+	},
+	"Trang-Oul's Avatar": {
+		5: allResistances(50),
+	},
+	"M'avina's Battle Hymn": {
+		5: allResistances(50),
+	},
+	"The Disciple": {
+		5: append(allResistances(50), setBonus{attrAllSkills, 2}),
+	},
+	"Cow King's Leathers": {
+		3: {{attrMagicFind, 100}},
+	},
+}
+
+// allResistances returns the bonuses adding the value to every resistance.
+func allResistances(value int64) []setBonus {
+	return []setBonus{
+		{attrFireResist, value},
+		{attrColdResist, value},
+		{attrLightningResist, value},
+		{attrPoisonResist, value},
+	}
+}
@@ -0,0 +1,329 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// Attribute ids of the attributes adding to skills.
+const (
+	attrClassSkills    = 83
+	attrSkill          = 97
+	attrClassSkill     = 107
+	attrElementSkills  = 126
+	attrTabSkills      = 188
+	attrDruidSkills    = 179
+	attrAssassinSkills = 180
+)
+
+// Every class has a block of 30 skills, the classes of the expansion come after
+// the skills of monsters.
+const (
+	firstSkillPerClass  = 6
+	skillsPerClass      = 30
+	firstExpansionSkill = 221
+)
+
+// treeSkill is where a skill is in the skill tree of its class, the skills it
+// requires are the skills right above it in the tree.
+type treeSkill struct {
+	tab      uint64
+	level    uint64
+	requires []uint64
+}
+
+// skillTree are the tabs, required levels and prerequisites of the skills of every class.
+var skillTree = map[uint64]treeSkill{
+	// Amazon.
+	6: {0, 1, nil}, 7: {0, 1, nil}, 8: {1, 1, nil}, 9: {1, 1, nil}, 10: {2, 1, nil},
+	11: {0, 6, nil}, 12: {0, 6, []uint64{6}}, 13: {1, 6, nil}, 14: {2, 6, []uint64{10}}, 15: {2, 6, nil},
+	16: {0, 12, []uint64{7, 12}}, 17: {1, 12, []uint64{8}}, 18: {1, 12, []uint64{13}}, 19: {2, 12, []uint64{14}}, 20: {2, 12, []uint64{15}},
+	21: {0, 18, []uint64{11}}, 22: {0, 18, []uint64{11, 12}}, 23: {1, 18, []uint64{9}}, 24: {2, 18, []uint64{14, 20}}, 25: {2, 18, []uint64{20}},
+	26: {0, 24, []uint64{22}}, 27: {0, 24, []uint64{16}}, 28: {1, 24, []uint64{17}}, 29: {1, 24, []uint64{18}}, 30: {2, 24, []uint64{19}},
+	31: {0, 30, []uint64{21}}, 32: {1, 30, []uint64{28, 29}}, 33: {1, 30, []uint64{23}}, 34: {2, 30, []uint64{24}}, 35: {2, 30, []uint64{25}},
+	// Sorceress.
+	36: {0, 1, nil}, 37: {0, 1, nil}, 38: {1, 1, nil}, 39: {2, 1, nil}, 40: {2, 1, nil},
+	41: {0, 6, nil}, 42: {1, 6, nil}, 43: {1, 6, nil}, 44: {2, 6, nil}, 45: {2, 6, []uint64{39}},
+	46: {0, 12, []uint64{41}}, 47: {0, 12, []uint64{36}}, 48: {1, 12, []uint64{42}}, 49: {1, 12, []uint64{38}}, 50: {2, 12, []uint64{40, 45}},
+	51: {0, 18, []uint64{46}}, 52: {0, 18, []uint64{37, 47}}, 53: {1, 18, []uint64{49}}, 54: {1, 18, []uint64{43}}, 55: {2, 18, []uint64{45}},
+	56: {0, 24, []uint64{47, 51}}, 57: {1, 24, []uint64{48, 53}}, 58: {1, 24, []uint64{53, 54}}, 59: {2, 24, []uint64{44, 55}}, 60: {2, 24, []uint64{50}},
+	61: {0, 30, nil}, 62: {0, 30, []uint64{52}}, 63: {1, 30, nil}, 64: {2, 30, []uint64{59}}, 65: {2, 30, nil},
+	// Necromancer.
+	66: {0, 1, nil}, 67: {1, 1, nil}, 68: {1, 1, nil}, 69: {2, 1, nil}, 70: {2, 1, nil},
+	71: {0, 6, []uint64{66}}, 72: {0, 6, []uint64{66}}, 73: {1, 6, nil}, 74: {1, 6, []uint64{67}}, 75: {2, 6, nil},
+	76: {0, 12, []uint64{66}}, 77: {0, 12, []uint64{72}}, 78: {1, 12, []uint64{68}}, 79: {2, 12, []uint64{75}}, 80: {2, 12, []uint64{70}},
+	81: {0, 18, []uint64{71}}, 82: {0, 18, []uint64{76}}, 83: {1, 18, []uint64{73, 74}}, 84: {1, 18, []uint64{74}}, 85: {2, 18, []uint64{75}},
+	86: {0, 24, []uint64{81}}, 87: {0, 24, []uint64{77}}, 88: {1, 24, []uint64{78, 84}}, 89: {2, 24, []uint64{79}}, 90: {2, 24, []uint64{85}},
+	91: {0, 30, []uint64{82}}, 92: {1, 30, []uint64{83}}, 93: {1, 30, []uint64{84}}, 94: {2, 30, []uint64{90}}, 95: {2, 30, []uint64{80, 90}},
+	// Paladin.
+	96: {0, 1, nil}, 97: {0, 1, nil}, 98: {1, 1, nil}, 99: {2, 1, nil}, 100: {2, 1, nil},
+	101: {0, 6, nil}, 102: {1, 6, []uint64{98}}, 103: {1, 6, []uint64{98}}, 104: {2, 6, nil}, 105: {2, 6, nil},
+	106: {0, 12, []uint64{96}}, 107: {0, 12, []uint64{97}}, 108: {1, 12, []uint64{98}}, 109: {2, 12, []uint64{99}}, 110: {2, 12, nil},
+	111: {0, 18, []uint64{106}}, 112: {0, 18, []uint64{101}}, 113: {1, 18, []uint64{108}}, 114: {1, 18, []uint64{102}}, 115: {2, 18, []uint64{104, 109}},
+	116: {0, 24, []uint64{111}}, 117: {0, 24, []uint64{107, 112}}, 118: {1, 24, []uint64{114}}, 119: {1, 24, []uint64{103, 114}}, 120: {2, 24, []uint64{109}},
+	121: {0, 30, []uint64{112, 116}}, 122: {1, 30, []uint64{113}}, 123: {1, 30, []uint64{119}}, 124: {2, 30, []uint64{115}}, 125: {2, 30, []uint64{100, 105, 110}},
+	// Barbarian.
+	126: {0, 1, nil}, 127: {1, 1, nil}, 128: {1, 1, nil}, 129: {1, 1, nil}, 130: {2, 1, nil}, 131: {2, 1, nil},
+	132: {0, 6, nil}, 133: {0, 6, []uint64{126}}, 134: {1, 6, nil}, 135: {1, 6, nil}, 136: {1, 6, nil}, 137: {2, 6, []uint64{130}}, 138: {2, 6, []uint64{130}},
+	139: {0, 12, []uint64{126}}, 140: {0, 12, []uint64{133}}, 141: {1, 12, nil}, 142: {2, 12, []uint64{131}},
+	143: {0, 18, []uint64{132}}, 144: {0, 18, []uint64{139}}, 145: {1, 18, nil}, 146: {2, 18, []uint64{137}},
+	147: {0, 24, []uint64{140}}, 148: {1, 24, []uint64{141}}, 149: {2, 24, []uint64{138}}, 150: {2, 24, []uint64{142}},
+	151: {0, 30, []uint64{143, 144}}, 152: {0, 30, []uint64{144}}, 153: {1, 30, []uint64{145}}, 154: {2, 30, []uint64{146, 149}}, 155: {2, 30, []uint64{149}},
+	// Druid.
+	221: {0, 1, nil}, 222: {0, 1, nil}, 223: {1, 1, nil}, 224: {1, 1, nil}, 225: {2, 1, nil},
+	226: {0, 6, nil}, 227: {0, 6, []uint64{221}}, 228: {1, 6, nil}, 229: {2, 6, []uint64{225}}, 230: {2, 6, nil},
+	231: {0, 12, []uint64{222}}, 232: {1, 12, []uint64{223}}, 233: {1, 12, []uint64{228}}, 234: {2, 12, []uint64{229}}, 235: {2, 12, []uint64{230}},
+	236: {0, 18, []uint64{226}}, 237: {0, 18, []uint64{227}}, 238: {1, 18, []uint64{232}}, 239: {1, 18, []uint64{232, 233}}, 240: {2, 18, []uint64{235}},
+	241: {0, 24, []uint64{231}}, 242: {1, 24, []uint64{239}}, 243: {1, 24, []uint64{233}}, 244: {2, 24, []uint64{234}}, 245: {2, 24, []uint64{240}},
+	246: {0, 30, []uint64{236}}, 247: {0, 30, []uint64{237}}, 248: {1, 30, []uint64{238}}, 249: {2, 30, []uint64{244, 250}}, 250: {2, 30, []uint64{245}},
+	// Assassin.
+	251: {0, 1, nil}, 252: {1, 1, nil}, 253: {1, 1, nil}, 254: {2, 1, nil}, 255: {2, 1, nil},
+	256: {0, 6, []uint64{251}}, 257: {0, 6, nil}, 258: {1, 6, []uint64{252}}, 259: {2, 6, nil}, 260: {2, 6, []uint64{255}},
+	261: {0, 12, []uint64{256}}, 262: {0, 12, []uint64{256}}, 263: {1, 12, []uint64{252}}, 264: {1, 12, []uint64{253}}, 265: {2, 12, []uint64{254}},
+	266: {0, 18, []uint64{257, 262}}, 267: {1, 18, []uint64{258}}, 268: {1, 18, []uint64{263, 264}}, 269: {2, 18, []uint64{259}}, 270: {2, 18, []uint64{260}},
+	271: {0, 24, []uint64{261}}, 272: {0, 24, []uint64{262}}, 273: {1, 24, []uint64{264}}, 274: {2, 24, []uint64{269}}, 275: {2, 24, []uint64{270}},
+	276: {0, 30, []uint64{271}}, 277: {0, 30, []uint64{266}}, 278: {1, 30, []uint64{267}}, 279: {1, 30, []uint64{268}}, 280: {2, 30, []uint64{274}},
+}
+
+// synergy is a skill adding a percentage of damage to another skill for every hard point spent on it.
+type synergy struct {
+	skill    uint64
+	perPoint int64
+}
+
+// synergies are the damage synergies of the main damage dealing skills.
+var synergies = map[uint64][]synergy{
+	// Amazon, Lightning Fury.
+	35: {{14, 1}, {20, 1}, {24, 1}, {34, 1}},
+	// Sorceress, Fire Bolt, Fire Ball, Meteor, Hydra, Lightning, Chain Lightning,
+	// Ice Bolt, Blizzard and Frozen Orb.
+	36: {{47, 16}, {56, 16}},
+	47: {{36, 14}, {56, 14}},
+	56: {{36, 5}, {47, 5}},
+	62: {{36, 3}, {47, 3}},
+	49: {{38, 8}, {48, 8}, {53, 8}},
+	53: {{38, 4}, {48, 4}, {49, 4}},
+	39: {{45, 15}, {55, 15}, {59, 15}, {64, 15}},
+	59: {{39, 5}, {45, 5}, {55, 5}},
+	64: {{39, 2}},
+	// Necromancer, Bone Spear and Poison Nova.
+	84: {{67, 8}, {78, 8}, {88, 8}, {93, 8}},
+	92: {{73, 10}, {83, 10}},
+	// Paladin, Holy Bolt and Blessed Hammer.
+	101: {{112, 50}, {121, 50}},
+	112: {{108, 14}, {115, 14}},
+	// Druid, Volcano, Tornado and Hurricane.
+	244: {{229, 12}, {234, 12}},
+	245: {{235, 9}, {240, 9}, {250, 9}},
+	250: {{235, 7}, {240, 7}, {245, 7}},
+	// Assassin, Wake of Fire and Lightning Sentry.
+	262: {{251, 8}, {272, 8}},
+	271: {{256, 12}, {261, 12}, {276, 12}},
+}
+
+// elementFire is the element of the attribute adding to elemental skills, fire
+// is the only element the game has items for.
+const elementFire = 1
+
+// fireSkills are the skills dealing fire damage of every class, which are the
+// skills the attribute adding to fire skills adds to.
+var fireSkills = map[uint64]bool{
+	// Amazon, Fire Arrow, Exploding Arrow and Immolation Arrow.
+	7: true, 16: true, 27: true,
+	// Sorceress, Fire Bolt, Inferno, Blaze, Fire Ball, Fire Wall, Enchant, Meteor and Hydra.
+	36: true, 41: true, 46: true, 47: true, 51: true, 52: true, 56: true, 62: true,
+	// Paladin, Holy Fire.
+	102: true,
+	// Druid, Firestorm, Molten Boulder, Fissure, Fire Claws, Volcano and Armageddon.
+	225: true, 229: true, 234: true, 239: true, 244: true, 249: true,
+	// Assassin, Fire Blast, Fists of Fire, Wake of Fire, Dragon Tail and Wake of Inferno.
+	251: true, 259: true, 262: true, 270: true, 272: true,
+}
+
+// classSkillAttributes maps the attributes adding to all skills of a single class to the class.
+var classSkillAttributes = map[uint64]uint64{
+	attrDruidSkills:    d2s.Druid,
+	attrAssassinSkills: d2s.Assassin,
+}
+
+// Skills returns the skills of the class of the character, with the points spent
+// on them, the levels the gear worn adds to them and their synergies. Skills of
+// other classes the gear worn grants are added after them.
+func Skills(character *d2s.Character) []domain.Skill {
+	class := uint64(character.Header.Class)
+
+	hard := make(map[uint64]int64, len(character.Skills))
+	for _, s := range character.Skills {
+		hard[uint64(s.ID)] = int64(s.Points)
+	}
+
+	bonus := itemSkills(character, class)
+
+	own := classSkills(class)
+	skills := make([]domain.Skill, 0, len(own)+len(bonus.granted))
+	for _, id := range own {
+		skill := treeSkillOf(class, id)
+		skill.HardPoints = hard[id]
+		skill.ItemPoints = bonus.points(id, skillTree[id].tab)
+		skill.Level = skill.HardPoints + skill.ItemPoints
+
+		// Only points spent on a skill count towards its synergies.
+		if s, ok := synergies[id]; ok {
+			var percent int64
+			for _, syn := range s {
+				skill.Synergies = append(skill.Synergies, domain.Synergy{
+					Skill:    domain.SkillName(syn.skill),
+					PerPoint: syn.perPoint,
+					Points:   hard[syn.skill],
+				})
+				percent += syn.perPoint * hard[syn.skill]
+			}
+			skill.DamageMultiplier = float64(100+percent) / 100
+		}
+
+		skills = append(skills, skill)
+	}
+
+	// Skills granted by items can't have points spent on them, and only the
+	// levels added to all skills or to their element add to them.
+	granted := make([]uint64, 0, len(bonus.granted))
+	for id := range bonus.granted {
+		if c, ok := domain.SkillClass(id); !ok || c != class {
+			granted = append(granted, id)
+		}
+	}
+	sort.Slice(granted, func(i, j int) bool { return granted[i] < granted[j] })
+
+	for _, id := range granted {
+		c, _ := domain.SkillClass(id)
+		skill := treeSkillOf(c, id)
+		skill.ItemPoints = bonus.grantedPoints(id)
+		skill.Level = skill.ItemPoints
+
+		skills = append(skills, skill)
+	}
+
+	return skills
+}
+
+// treeSkillOf returns the skill of the class with its place in the skill tree,
+// skills that don't belong to a class aren't in a tree.
+func treeSkillOf(class uint64, id uint64) domain.Skill {
+	skill := domain.Skill{
+		ID:       id,
+		Name:     domain.SkillName(id),
+		Requires: []string{},
+	}
+
+	tree, ok := skillTree[id]
+	if !ok {
+		return skill
+	}
+
+	for _, r := range tree.requires {
+		skill.Requires = append(skill.Requires, domain.SkillName(r))
+	}
+	skill.Tab = domain.SkillTabName(class, tree.tab)
+	skill.RequiredLevel = tree.level
+
+	return skill
+}
+
+// classSkills returns the ids of the skills of the class.
+func classSkills(class uint64) []uint64 {
+	var first uint64
+	switch class {
+	case d2s.Druid:
+		first = firstExpansionSkill
+	case d2s.Assassin:
+		first = firstExpansionSkill + skillsPerClass
+	default:
+		if class > d2s.Barbarian {
+			return nil
+		}
+		first = firstSkillPerClass + class*skillsPerClass
+	}
+
+	ids := make([]uint64, 0, skillsPerClass)
+	for id := first; id < first+skillsPerClass; id++ {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// skillBonuses are the skill levels the gear worn adds.
+type skillBonuses struct {
+	all     int64
+	class   int64
+	fire    int64
+	tabs    map[uint64]int64
+	single  map[uint64]int64
+	granted map[uint64]int64
+}
+
+// points returns the levels added to a skill of the class by its id and tab.
+func (b skillBonuses) points(skill uint64, tab uint64) int64 {
+	return b.grantedPoints(skill) + b.class + b.tabs[tab] + b.single[skill]
+}
+
+// grantedPoints returns the levels added to a skill of any class, the levels
+// added to the skills of a class or a tab only add to the skills of the class.
+func (b skillBonuses) grantedPoints(skill uint64) int64 {
+	points := b.all + b.granted[skill]
+	if fireSkills[skill] {
+		points += b.fire
+	}
+
+	return points
+}
+
+// itemSkills adds up the skill levels the gear worn adds to the skills of the
+// class and the skills it grants.
+func itemSkills(character *d2s.Character, class uint64) skillBonuses {
+	b := skillBonuses{
+		tabs:    make(map[uint64]int64),
+		single:  make(map[uint64]int64),
+		granted: make(map[uint64]int64),
+	}
+
+	add := func(id uint64, values []int64) {
+		value := func(i int) int64 {
+			if i < len(values) {
+				return values[i]
+			}
+			return 0
+		}
+
+		switch id {
+		case attrAllSkills:
+			b.all += value(0)
+		case attrClassSkills:
+			if uint64(value(0)) == class {
+				b.class += value(1)
+			}
+		case attrDruidSkills, attrAssassinSkills:
+			if classSkillAttributes[id] == class {
+				b.class += value(0)
+			}
+		case attrTabSkills:
+			if uint64(value(1)) == class {
+				b.tabs[uint64(value(0))] += value(2)
+			}
+		case attrElementSkills:
+			if value(0) == elementFire {
+				b.fire += value(1)
+			}
+		case attrSkill:
+			b.granted[uint64(value(0))] += value(1)
+		case attrClassSkill:
+			b.single[uint64(value(0))] += value(1)
+		}
+	}
+
+	activeAttributes(character, add)
+
+	return b
+}
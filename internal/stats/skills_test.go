@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestSkills(t *testing.T) {
+	char := &d2s.Character{
+		Skills: []d2s.Skill{
+			{ID: 39, Points: 20, Name: "Ice Bolt"},
+			{ID: 45, Points: 1, Name: "Ice Blast"},
+			{ID: 55, Points: 10, Name: "Glacial Spike"},
+			{ID: 59, Points: 20, Name: "Blizzard"},
+		},
+		Items: items(t, `[
+			{"type_name": "Amulet", "location_id": 1, "equipped_id": 2, "magic_attributes": [
+				{"id": 83, "values": [1, 2]},
+				{"id": 83, "values": [4, 3]}
+			]},
+			{"type_name": "Circlet", "location_id": 1, "equipped_id": 1, "magic_attributes": [
+				{"id": 188, "values": [2, 1, 3]},
+				{"id": 107, "values": [64, 1]}
+			]},
+			{"type_name": "Grand Charm", "type": "cm3", "location_id": 0, "alt_position_id": 1, "magic_attributes": [
+				{"id": 188, "values": [0, 1, 1]}
+			]}
+		]`),
+	}
+	char.Header.Class = d2s.Sorceress
+
+	skills := Skills(char)
+	if len(skills) != skillsPerClass {
+		t.Fatalf("expected %d skills, got = %d", skillsPerClass, len(skills))
+	}
+
+	byName := make(map[string]domain.Skill, len(skills))
+	for _, skill := range skills {
+		byName[skill.Name] = skill
+	}
+
+	blizzard := byName["Blizzard"]
+	if blizzard.Tab != "Cold Skills" || blizzard.RequiredLevel != 24 || !reflect.DeepEqual(blizzard.Requires, []string{"Frost Nova", "Glacial Spike"}) {
+		t.Errorf("unexpected place of Blizzard in the tree, got = %+v", blizzard)
+	}
+
+	// Sorceress skills and cold skills are added, barbarian skills and fire skills aren't.
+	if blizzard.HardPoints != 20 || blizzard.ItemPoints != 5 || blizzard.Level != 25 {
+		t.Errorf("expected Blizzard at level 25 with 20 hard points, got = %d hard points, %d from items", blizzard.HardPoints, blizzard.ItemPoints)
+	}
+
+	// 5% per point in Ice Bolt, Ice Blast and Glacial Spike, points from items don't count.
+	if blizzard.DamageMultiplier != 2.55 {
+		t.Errorf("expected a damage multiplier of 2.55, got = %v", blizzard.DamageMultiplier)
+	}
+
+	orb := byName["Frozen Orb"]
+	if orb.HardPoints != 0 || orb.Level != 6 || orb.DamageMultiplier != 1.4 {
+		t.Errorf("expected Frozen Orb at level 6 with a damage multiplier of 1.4, got = %+v", orb)
+	}
+
+	if warmth := byName["Warmth"]; warmth.Level != 3 || warmth.Synergies != nil || warmth.DamageMultiplier != 0 {
+		t.Errorf("expected Warmth at level 3 without synergies, got = %+v", warmth)
+	}
+}
+
+func TestSkillsGrantedByItems(t *testing.T) {
+	char := &d2s.Character{
+		Skills: []d2s.Skill{{ID: 126, Points: 20, Name: "Bash"}},
+		Items: items(t, `[
+			{"type_name": "Mage Plate", "location_id": 1, "equipped_id": 3, "runeword_attributes": [
+				{"id": 127, "values": [2]},
+				{"id": 97, "values": [54, 1]}
+			]},
+			{"type_name": "Circlet", "location_id": 1, "equipped_id": 1, "magic_attributes": [
+				{"id": 83, "values": [4, 3]},
+				{"id": 126, "values": [1, 2]},
+				{"id": 97, "values": [47, 1]},
+				{"id": 97, "values": [126, 1]}
+			]}
+		]`),
+	}
+	char.Header.Class = d2s.Barbarian
+
+	skills := Skills(char)
+	if len(skills) != skillsPerClass+2 {
+		t.Fatalf("expected the skills of the class and the 2 granted skills, got = %d", len(skills))
+	}
+
+	// Skills of the class get the class skills, granted skills don't.
+	if bash := skills[0]; bash.Name != "Bash" || bash.HardPoints != 20 || bash.ItemPoints != 6 {
+		t.Errorf("expected Bash with 20 hard points and 6 from items, got = %+v", bash)
+	}
+
+	fireBall, teleport := skills[skillsPerClass], skills[skillsPerClass+1]
+
+	// Fire Ball is a fire skill, Teleport isn't.
+	if fireBall.Name != "Fire Ball" || fireBall.Tab != "Fire Skills" || fireBall.HardPoints != 0 || fireBall.Level != 5 {
+		t.Errorf("expected a granted Fire Ball at level 5, got = %+v", fireBall)
+	}
+
+	if teleport.Name != "Teleport" || teleport.HardPoints != 0 || teleport.Level != 3 {
+		t.Errorf("expected a granted Teleport at level 3, got = %+v", teleport)
+	}
+}
+
+func TestSkillsOfEveryClass(t *testing.T) {
+	amazon, barbarian, druid, assassin := &d2s.Character{}, &d2s.Character{}, &d2s.Character{}, &d2s.Character{}
+	amazon.Header.Class = d2s.Amazon
+	barbarian.Header.Class = d2s.Barbarian
+	druid.Header.Class = d2s.Druid
+	assassin.Header.Class = d2s.Assassin
+
+	for _, tt := range []struct {
+		char  *d2s.Character
+		first string
+		last  string
+	}{
+		{amazon, "Magic Arrow", "Lightning Fury"},
+		{barbarian, "Bash", "Battle Command"},
+		{druid, "Raven", "Hurricane"},
+		{assassin, "Fire Blast", "Phoenix Strike"},
+	} {
+		skills := Skills(tt.char)
+		if first, last := skills[0].Name, skills[len(skills)-1].Name; first != tt.first || last != tt.last {
+			t.Errorf("expected the skills of %s from %s to %s, got = %s to %s", tt.char.Header.Class, tt.first, tt.last, first, last)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package stats
+
+import (
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// Slots of the weapons and shields of both weapon sets, only the active set counts.
+const (
+	slotRightHand     = 4
+	slotLeftHand      = 5
+	slotRightHandSwap = 11
+	slotLeftHandSwap  = 12
+)
+
+// charms are the item codes of charms, which only count while kept in the inventory.
+var charms = map[string]struct{}{
+	"cm1": {},
+	"cm2": {},
+	"cm3": {},
+}
+
+// Attribute ids of the stats we add up.
+const (
+	attrStrength        = 0
+	attrEnergy          = 1
+	attrDexterity       = 2
+	attrVitality        = 3
+	attrLife            = 7
+	attrMana            = 9
+	attrDamageReduced   = 36
+	attrMagicResist     = 37
+	attrFireResist      = 39
+	attrMaxFireResist   = 40
+	attrLightningResist = 41
+	attrMaxLightResist  = 42
+	attrColdResist      = 43
+	attrMaxColdResist   = 44
+	attrPoisonResist    = 45
+	attrMaxPoisonResist = 46
+	attrLifeStolen      = 60
+	attrManaStolen      = 62
+	attrMaxLifePercent  = 76
+	attrMaxManaPercent  = 77
+	attrGoldFind        = 79
+	attrMagicFind       = 80
+	attrAttackSpeed     = 93
+	attrRunWalk         = 96
+	attrHitRecovery     = 99
+	attrBlockRate       = 102
+	attrCastRate        = 105
+	attrAllSkills       = 127
+	attrOpenWounds      = 135
+	attrCrushingBlow    = 136
+	attrDeadlyStrike    = 141
+)
+
+// perLevel maps the attributes that grow with the level of the character to
+// the attribute they add to, they add an eighth of a point per level.
+var perLevel = map[uint64]uint64{
+	216: attrLife,
+	217: attrMana,
+	220: attrStrength,
+	221: attrDexterity,
+	222: attrEnergy,
+	223: attrVitality,
+	230: attrColdResist,
+	231: attrFireResist,
+	232: attrLightningResist,
+	233: attrPoisonResist,
+	239: attrGoldFind,
+	240: attrMagicFind,
+	247: attrCrushingBlow,
+	248: attrOpenWounds,
+	250: attrDeadlyStrike,
+}
+
+// Life per point of vitality and mana per point of energy of every class, in quarter points.
+var (
+	lifePerVitality = map[uint64]int64{
+		d2s.Amazon: 12, d2s.Sorceress: 8, d2s.Necromancer: 8, d2s.Paladin: 12,
+		d2s.Barbarian: 16, d2s.Druid: 8, d2s.Assassin: 12,
+	}
+	manaPerEnergy = map[uint64]int64{
+		d2s.Amazon: 6, d2s.Sorceress: 8, d2s.Necromancer: 8, d2s.Paladin: 6,
+		d2s.Barbarian: 4, d2s.Druid: 8, d2s.Assassin: 7,
+	}
+)
+
+// Resistances are lowered in the later difficulties, and capped at 75% unless
+// the max is raised, which can't be raised past 95%.
+var penalties = map[string]int64{
+	domain.DifficultyNormal:    0,
+	domain.DifficultyNightmare: -40,
+	domain.DifficultyHell:      -100,
+}
+
+const (
+	baseMaxResist = 75
+	maxMaxResist  = 95
+)
+
+// Compute adds up the attributes of the equipped items of the active weapon set,
+// the charms in the inventory, their socketed items and the set bonuses that are
+// active with the set items worn.
+func Compute(character *d2s.Character) *domain.EffectiveStats {
+	var (
+		level  = uint64(character.Header.Level)
+		class  = uint64(character.Header.Class)
+		totals = make(map[uint64]int64)
+		stats  = &domain.EffectiveStats{
+			Class:       character.Header.Class.String(),
+			Level:       level,
+			Resistances: make(map[string]domain.Resistances, len(penalties)),
+			Sources:     []string{},
+		}
+	)
+
+	add := func(id uint64, values []int64) {
+		if len(values) == 0 {
+			return
+		}
+
+		value := values[len(values)-1]
+		if base, ok := perLevel[id]; ok {
+			id, value = base, value*int64(level)/8
+		}
+
+		totals[id] += value
+	}
+
+	worn := wornItems(character)
+	for _, item := range worn {
+		stats.Sources = append(stats.Sources, domain.ItemName(item))
+	}
+	stats.UnknownSetBonuses = unknownSets(worn)
+
+	activeAttributes(character, add)
+
+	attributes := character.Attributes
+	stats.Attributes = domain.EffectiveAttributes{
+		Strength:  int64(attributes.Strength) + totals[attrStrength],
+		Dexterity: int64(attributes.Dexterity) + totals[attrDexterity],
+		Vitality:  int64(attributes.Vitality) + totals[attrVitality],
+		Energy:    int64(attributes.Energy) + totals[attrEnergy],
+	}
+
+	// The life and mana of the character already hold the points it spent, the
+	// attributes of the items add to them before the percentages are applied.
+	life := int64(attributes.MaxHP) + totals[attrVitality]*lifePerVitality[class]/4 + totals[attrLife]
+	stats.Life = life * (100 + totals[attrMaxLifePercent]) / 100
+
+	mana := int64(attributes.MaxMana) + totals[attrEnergy]*manaPerEnergy[class]/4 + totals[attrMana]
+	stats.Mana = mana * (100 + totals[attrMaxManaPercent]) / 100
+
+	stats.AllSkills = totals[attrAllSkills]
+	stats.FasterCastRate = totals[attrCastRate]
+	stats.FasterHitRecovery = totals[attrHitRecovery]
+	stats.FasterBlockRate = totals[attrBlockRate]
+	stats.IncreasedAttackSpeed = totals[attrAttackSpeed]
+	stats.FasterRunWalk = totals[attrRunWalk]
+	stats.MagicFind = totals[attrMagicFind]
+	stats.GoldFind = totals[attrGoldFind]
+	stats.LifeStolen = totals[attrLifeStolen]
+	stats.ManaStolen = totals[attrManaStolen]
+	stats.CrushingBlow = totals[attrCrushingBlow]
+	stats.DeadlyStrike = totals[attrDeadlyStrike]
+	stats.OpenWounds = totals[attrOpenWounds]
+	stats.DamageReduced = totals[attrDamageReduced]
+	stats.MagicResist = totals[attrMagicResist]
+
+	// Every scroll of resistance read adds to the resistances for good.
+	base := 10 * scrollsOfResistance(character)
+
+	for difficulty, penalty := range penalties {
+		stats.Resistances[difficulty] = domain.Resistances{
+			Fire:      resistance(base+totals[attrFireResist]+penalty, totals[attrMaxFireResist]),
+			Cold:      resistance(base+totals[attrColdResist]+penalty, totals[attrMaxColdResist]),
+			Lightning: resistance(base+totals[attrLightningResist]+penalty, totals[attrMaxLightResist]),
+			Poison:    resistance(base+totals[attrPoisonResist]+penalty, totals[attrMaxPoisonResist]),
+		}
+	}
+
+	return stats
+}
+
+// activeAttributes visits the attributes of the gear worn, the attributes of the
+// items, their socketed items and the set bonuses of the items and of their
+// sets that are active with the set items worn.
+func activeAttributes(character *d2s.Character, visit func(id uint64, values []int64)) {
+	worn := wornItems(character)
+
+	// Set bonuses of an item are active with enough items of its set worn, or
+	// with specific items of the set worn.
+	var (
+		setItems = make(map[uint64]bool)
+		setCount = make(map[string]int)
+	)
+	for _, item := range worn {
+		if item.Quality == domain.ItemQualitySet && domain.ItemLocation(item) == domain.LocationEquipped {
+			setItems[item.SetID] = true
+			setCount[setOf(item.SetID)]++
+		}
+	}
+
+	for _, item := range worn {
+		for _, a := range item.MagicAttributes {
+			visit(a.ID, a.Values)
+		}
+		for _, a := range item.RunewordAttributes {
+			visit(a.ID, a.Values)
+		}
+		for _, socketed := range item.SocketedItems {
+			for _, a := range socketed.MagicAttributes {
+				visit(a.ID, a.Values)
+			}
+		}
+
+		if item.Quality != domain.ItemQualitySet || domain.ItemLocation(item) != domain.LocationEquipped {
+			continue
+		}
+
+		for i, bonuses := range item.SetAttributes {
+			active := false
+			switch {
+			case i < len(item.SetAttributesIDsReq):
+				active = setItems[item.SetAttributesIDsReq[i]]
+			case i < len(item.SetAttributesNumReq):
+				active = setCount[setOf(item.SetID)] >= int(item.SetAttributesNumReq[i])
+			}
+
+			if !active {
+				continue
+			}
+
+			for _, a := range bonuses {
+				visit(a.ID, a.Values)
+			}
+		}
+	}
+
+	// Bonuses of the set itself only depend on how many of its items are worn.
+	for set, count := range setCount {
+		for worn, bonuses := range setBonuses[set] {
+			if count < worn {
+				continue
+			}
+
+			for _, b := range bonuses {
+				visit(b.id, []int64{b.value})
+			}
+		}
+	}
+}
+
+// wornItems returns the items the attributes of count, the equipped items of the
+// active weapon set and the charms in the inventory.
+func wornItems(character *d2s.Character) []d2s.Item {
+	inactive := map[uint64]bool{slotRightHandSwap: true, slotLeftHandSwap: true}
+	if character.Header.ActiveArms == 1 {
+		inactive = map[uint64]bool{slotRightHand: true, slotLeftHand: true}
+	}
+
+	var worn []d2s.Item
+	for _, item := range character.Items {
+		switch domain.ItemLocation(item) {
+		case domain.LocationEquipped:
+			if !inactive[item.EquippedID] {
+				worn = append(worn, item)
+			}
+		case domain.LocationInventory:
+			if _, ok := charms[item.Type]; ok {
+				worn = append(worn, item)
+			}
+		}
+	}
+
+	return worn
+}
+
+// scrollsOfResistance returns how many difficulties the scroll of resistance
+// rewarded for rescuing Anya was read in.
+func scrollsOfResistance(character *d2s.Character) int64 {
+	var n int64
+	for _, prison := range [][2]byte{
+		character.Header.QuestsNormal.ActV.PrisonOfIce,
+		character.Header.QuestsNm.ActV.PrisonOfIce,
+		character.Header.QuestsHell.ActV.PrisonOfIce,
+	} {
+		if prison[0]>>7&1 > 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+func resistance(uncapped int64, maxBonus int64) domain.Resistance {
+	max := min(baseMaxResist+maxBonus, maxMaxResist)
+
+	return domain.Resistance{
+		Value:    min(uncapped, max),
+		Uncapped: uncapped,
+		Max:      max,
+	}
+}
@@ -0,0 +1,149 @@
+package stats
+
+import "slices"
+
+// Animation classes of weapons, the frames of an attack depend on the class
+// of the weapon the character attacks with.
+const (
+	animHandToHand    = "hth"
+	animOneHandSwing  = "1hs"
+	animOneHandThrust = "1ht"
+	animTwoHandSwing  = "2hs"
+	animTwoHandThrust = "2ht"
+	animStaff         = "stf"
+	animBow           = "bow"
+	animCrossbow      = "xbw"
+)
+
+// weapon is the animation class and speed modifier of a base weapon.
+type weapon struct {
+	anim  string
+	speed int64
+}
+
+// weapons are the animation class and weapon speed modifier of every base
+// weapon, by the item codes of its normal, exceptional and elite versions.
+var weapons = []struct {
+	codes []string
+	weapon
+}{
+	// Axes.
+	{[]string{"hax", "9ha", "7ha"}, weapon{animOneHandSwing, 0}},
+	{[]string{"axe", "9ax", "7ax"}, weapon{animOneHandSwing, 10}},
+	{[]string{"2ax", "92a", "72a"}, weapon{animOneHandSwing, 10}},
+	{[]string{"mpi", "9mp", "7mp"}, weapon{animOneHandSwing, -10}},
+	{[]string{"wax", "9wa", "7wa"}, weapon{animOneHandSwing, 0}},
+	{[]string{"lax", "9la", "7la"}, weapon{animTwoHandSwing, -10}},
+	{[]string{"bax", "9ba", "7ba"}, weapon{animTwoHandSwing, 0}},
+	{[]string{"btx", "9bt", "7bt"}, weapon{animTwoHandSwing, 10}},
+	{[]string{"gax", "9ga", "7ga"}, weapon{animTwoHandSwing, -10}},
+	{[]string{"gix", "9gi", "7gi"}, weapon{animTwoHandSwing, 10}},
+	// Wands.
+	{[]string{"wnd", "9wn", "7wn"}, weapon{animOneHandSwing, 0}},
+	{[]string{"ywn", "9yw", "7yw"}, weapon{animOneHandSwing, 0}},
+	{[]string{"bwn", "9bw", "7bw"}, weapon{animOneHandSwing, -10}},
+	{[]string{"gwn", "9gw", "7gw"}, weapon{animOneHandSwing, 0}},
+	// Clubs, scepters and maces.
+	{[]string{"clb", "9cl", "7cl"}, weapon{animOneHandSwing, -10}},
+	{[]string{"spc", "9sp", "7sp"}, weapon{animOneHandSwing, 0}},
+	{[]string{"scp", "9sc", "7sc"}, weapon{animOneHandSwing, 0}},
+	{[]string{"gsc", "9qs", "7qs"}, weapon{animOneHandSwing, 10}},
+	{[]string{"wsp", "9ws", "7ws"}, weapon{animOneHandSwing, -10}},
+	{[]string{"mac", "9ma", "7ma"}, weapon{animOneHandSwing, 0}},
+	{[]string{"mst", "9mt", "7mt"}, weapon{animOneHandSwing, 0}},
+	{[]string{"fla", "9fl", "7fl"}, weapon{animOneHandSwing, -10}},
+	{[]string{"whm", "9wh", "7wh"}, weapon{animOneHandSwing, 20}},
+	{[]string{"mau", "9m9", "7m7"}, weapon{animTwoHandSwing, 10}},
+	{[]string{"gma", "9gm", "7gm"}, weapon{animTwoHandSwing, 20}},
+	// Swords.
+	{[]string{"ssd", "9ss", "7ss"}, weapon{animOneHandSwing, 0}},
+	{[]string{"scm", "9sm", "7sm"}, weapon{animOneHandSwing, -20}},
+	{[]string{"sbr", "9sb", "7sb"}, weapon{animOneHandSwing, -10}},
+	{[]string{"flc", "9fc", "7fc"}, weapon{animOneHandSwing, 20}},
+	{[]string{"crs", "9cr", "7cr"}, weapon{animOneHandSwing, 10}},
+	{[]string{"bsd", "9bs", "7bs"}, weapon{animOneHandSwing, 0}},
+	{[]string{"lsd", "9ls", "7ls"}, weapon{animOneHandSwing, -10}},
+	{[]string{"wsd", "9wd", "7wd"}, weapon{animOneHandSwing, 0}},
+	{[]string{"2hs", "92h", "72h"}, weapon{animTwoHandSwing, 0}},
+	{[]string{"clm", "9cm", "7cm"}, weapon{animTwoHandSwing, 10}},
+	{[]string{"gis", "9gs", "7gs"}, weapon{animTwoHandSwing, 10}},
+	{[]string{"bsw", "9b9", "7b7"}, weapon{animTwoHandSwing, 10}},
+	{[]string{"flb", "9fb", "7fb"}, weapon{animTwoHandSwing, -10}},
+	{[]string{"gsd", "9gd", "7gd"}, weapon{animTwoHandSwing, 10}},
+	// Daggers and throwing weapons.
+	{[]string{"dgr", "9dg", "7dg"}, weapon{animOneHandThrust, -20}},
+	{[]string{"dir", "9di", "7di"}, weapon{animOneHandThrust, 0}},
+	{[]string{"kri", "9kr", "7kr"}, weapon{animOneHandThrust, -20}},
+	{[]string{"bld", "9bl", "7bl"}, weapon{animOneHandThrust, -10}},
+	{[]string{"tkf", "9tk", "7tk"}, weapon{animOneHandThrust, 0}},
+	{[]string{"tax", "9ta", "7ta"}, weapon{animOneHandSwing, 10}},
+	{[]string{"bkf", "9bk", "7bk"}, weapon{animOneHandThrust, -10}},
+	{[]string{"bal", "9b8", "7b8"}, weapon{animOneHandSwing, 0}},
+	{[]string{"jav", "9ja", "7ja"}, weapon{animOneHandThrust, -10}},
+	{[]string{"pil", "9pi", "7pi"}, weapon{animOneHandThrust, 0}},
+	{[]string{"ssp", "9s9", "7s7"}, weapon{animOneHandThrust, 10}},
+	{[]string{"glv", "9gl", "7gl"}, weapon{animOneHandThrust, 20}},
+	{[]string{"tsp", "9ts", "7ts"}, weapon{animOneHandThrust, -10}},
+	// Spears and polearms.
+	{[]string{"spr", "9sr", "7sr"}, weapon{animTwoHandThrust, -10}},
+	{[]string{"tri", "9tr", "7tr"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"brn", "9br", "7br"}, weapon{animTwoHandThrust, 20}},
+	{[]string{"spt", "9st", "7st"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"pik", "9p9", "7p7"}, weapon{animTwoHandThrust, 20}},
+	{[]string{"bar", "9b7", "7o7"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"vou", "9vo", "7vo"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"scy", "9s8", "7s8"}, weapon{animTwoHandThrust, -10}},
+	{[]string{"pax", "9pa", "7pa"}, weapon{animTwoHandThrust, 10}},
+	{[]string{"hal", "9h9", "7h7"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"wsc", "9wc", "7wc"}, weapon{animTwoHandThrust, 20}},
+	// Staves.
+	{[]string{"sst", "8ss", "6ss"}, weapon{animStaff, -10}},
+	{[]string{"lst", "8ls", "6ls"}, weapon{animStaff, 0}},
+	{[]string{"cst", "8cs", "6cs"}, weapon{animStaff, 0}},
+	{[]string{"bst", "8bs", "6bs"}, weapon{animStaff, 0}},
+	{[]string{"wst", "8ws", "6ws"}, weapon{animStaff, 20}},
+	// Bows and crossbows.
+	{[]string{"sbw", "8sb", "6sb"}, weapon{animBow, 0}},
+	{[]string{"hbw", "8hb", "6hb"}, weapon{animBow, -10}},
+	{[]string{"lbw", "8lb", "6lb"}, weapon{animBow, 0}},
+	{[]string{"cbw", "8cb", "6cb"}, weapon{animBow, -10}},
+	{[]string{"sbb", "8s8", "6s7"}, weapon{animBow, 0}},
+	{[]string{"lbb", "8l8", "6l7"}, weapon{animBow, 10}},
+	{[]string{"swb", "8sw", "6sw"}, weapon{animBow, 0}},
+	{[]string{"lwb", "8lw", "6lw"}, weapon{animBow, 10}},
+	{[]string{"lxb", "8lx", "6lx"}, weapon{animCrossbow, -10}},
+	{[]string{"mxb", "8mx", "6mx"}, weapon{animCrossbow, 0}},
+	{[]string{"hxb", "8hx", "6hx"}, weapon{animCrossbow, 10}},
+	{[]string{"rxb", "8rx", "6rx"}, weapon{animCrossbow, -40}},
+	// Assassin claws, attacking with claws uses the hand to hand animation.
+	{[]string{"ktr", "9ar", "7ar"}, weapon{animHandToHand, -10}},
+	{[]string{"wrb", "9wb", "7wb"}, weapon{animHandToHand, 0}},
+	{[]string{"axf", "9xf", "7xf"}, weapon{animHandToHand, 0}},
+	{[]string{"ces", "9cs", "7cs"}, weapon{animHandToHand, 0}},
+	{[]string{"clw", "9lw", "7lw"}, weapon{animHandToHand, -10}},
+	{[]string{"btl", "9tw", "7tw"}, weapon{animHandToHand, -30}},
+	{[]string{"skr", "9qr", "7qr"}, weapon{animHandToHand, -40}},
+	// Sorceress orbs.
+	{[]string{"ob1", "ob6", "obb"}, weapon{animOneHandSwing, -10}},
+	{[]string{"ob2", "ob7", "obc"}, weapon{animOneHandSwing, -10}},
+	{[]string{"ob3", "ob8", "obd"}, weapon{animOneHandSwing, 0}},
+	{[]string{"ob4", "ob9", "obe"}, weapon{animOneHandSwing, 0}},
+	{[]string{"ob5", "oba", "obf"}, weapon{animOneHandSwing, 10}},
+	// Amazon bows, spears and javelins.
+	{[]string{"am1", "am6", "amb"}, weapon{animBow, 0}},
+	{[]string{"am2", "am7", "amc"}, weapon{animBow, 10}},
+	{[]string{"am3", "am8", "amd"}, weapon{animTwoHandThrust, 0}},
+	{[]string{"am4", "am9", "ame"}, weapon{animTwoHandThrust, 10}},
+	{[]string{"am5", "ama", "amf"}, weapon{animOneHandThrust, -10}},
+}
+
+// weaponOf returns the weapon of the item code, items that aren't weapons have none.
+func weaponOf(code string) (weapon, bool) {
+	for _, w := range weapons {
+		if slices.Contains(w.codes, code) {
+			return w.weapon, true
+		}
+	}
+
+	return weapon{}, false
+}
@@ -0,0 +1,233 @@
+package tooltip
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// priorities is the order magic attributes are listed in on a tooltip, higher
+// first, it's the description priority of the attribute in the game data.
+var priorities = map[uint64]int{
+	0: 67, 1: 61, 2: 65, 3: 63, 7: 59, 9: 55, 11: 51,
+	16: 74, 17: 129, 19: 115, 20: 134, 21: 127, 22: 126, 23: 127, 24: 126,
+	27: 52, 28: 50, 31: 71, 32: 69, 33: 70, 34: 22, 35: 21, 36: 22,
+	37: 41, 38: 40, 39: 36, 40: 37, 41: 34, 42: 35, 43: 32, 44: 33, 45: 30, 46: 31,
+	48: 102, 49: 101, 50: 99, 52: 104, 54: 96, 57: 92, 60: 88, 62: 89,
+	73: 3, 74: 56, 75: 2, 76: 58, 77: 54, 78: 13, 79: 10, 80: 9, 81: 76,
+	83: 150, 85: 11, 86: 16, 87: 8, 89: 6, 91: 0, 93: 145, 96: 148, 97: 81,
+	99: 139, 102: 136, 105: 142, 107: 81, 108: 81, 110: 18, 111: 128, 112: 80,
+	113: 81, 114: 11, 115: 119, 116: 118, 117: 81, 118: 19, 119: 117, 120: 120,
+	121: 112, 122: 108, 123: 110, 124: 106, 125: 5, 126: 157, 127: 158, 128: 14,
+	134: 77, 135: 83, 136: 87, 137: 59, 138: 16, 139: 17, 141: 85,
+	142: 23, 143: 24, 144: 27, 145: 28, 146: 29, 147: 30, 148: 25, 149: 26,
+	150: 77, 151: 159, 152: 1, 153: 18, 154: 49, 155: 82, 156: 75, 157: 134, 158: 133,
+	159: 126, 160: 126, 179: 150, 180: 150, 188: 151,
+	195: 160, 196: 160, 197: 160, 198: 160, 199: 160, 200: 160, 201: 160, 202: 160, 203: 160,
+	214: 71, 215: 74, 216: 59, 217: 55, 218: 126, 219: 129, 220: 67, 221: 65, 222: 61, 223: 63,
+	224: 115, 225: 117, 226: 96, 227: 102, 228: 99, 229: 92, 230: 32, 231: 36, 232: 34, 233: 30,
+	234: 25, 235: 23, 236: 27, 237: 29, 238: 13, 239: 10, 240: 9, 241: 50, 242: 51,
+	243: 112, 244: 108, 245: 110, 246: 106, 247: 87, 248: 83, 249: 59, 250: 85,
+	329: 88, 330: 88, 331: 88, 332: 88, 333: 88, 334: 88, 335: 88, 336: 88,
+}
+
+// Groups of attributes shown as a single line when they're all on the item with the same value.
+var (
+	allAttributes     = []uint64{0, 1, 2, 3}
+	allResistances    = []uint64{39, 41, 43, 45}
+	allMaxResistances = []uint64{40, 42, 44, 46}
+)
+
+// templates overrides how the game describes attributes that d2s names differently.
+var templates = map[uint64]string{
+	19:  "+{0} to Attack Rating",
+	21:  "+{0} to Minimum Damage",
+	22:  "+{0} to Maximum Damage",
+	23:  "+{0} to Minimum Damage",
+	24:  "+{0} to Maximum Damage",
+	60:  "{0}% Life Stolen per Hit",
+	62:  "{0}% Mana Stolen per Hit",
+	81:  "Knockback",
+	91:  "Requirements {0}%",
+	108: "Slain Monsters Rest in Peace",
+	117: "Prevent Monster Heal",
+	156: "Piercing Attack",
+}
+
+// perLevel maps the attributes that grow with the level of the character to the
+// fraction of a point they add per level.
+var perLevel = map[uint64]int64{
+	214: 8, 215: 8, 216: 8, 217: 8, 218: 8, 219: 8, 220: 8, 221: 8, 222: 8, 223: 8,
+	224: 2, 225: 8, 226: 8, 227: 8, 228: 8, 229: 8, 230: 8, 231: 8, 232: 8, 233: 8,
+	234: 8, 235: 8, 236: 8, 237: 8, 238: 8, 239: 8, 240: 8, 241: 8, 242: 8,
+	243: 8, 244: 8, 245: 2, 246: 2, 247: 8, 248: 8, 249: 8, 250: 8,
+}
+
+// elements are the names of the elements of elemental skill bonuses.
+var elements = map[int64]string{
+	1: "Fire",
+	2: "Lightning",
+	3: "Cold",
+	4: "Poison",
+	5: "Magic",
+}
+
+// damageElements are the elements of the attributes adding damage to attacks.
+var damageElements = map[uint64]string{
+	48: "Fire",
+	50: "Lightning",
+	52: "Magic",
+	54: "Cold",
+}
+
+// classSkills maps the attributes adding to the skills of a single class to the class.
+var classSkills = map[uint64]uint64{
+	179: d2s.Druid,
+	180: d2s.Assassin,
+}
+
+// triggers describe when a chance to cast attribute casts its skill.
+var triggers = map[uint64]string{
+	195: "on attack",
+	196: "when you Kill an Enemy",
+	197: "when you Die",
+	198: "on striking",
+	199: "when you Level-Up",
+	201: "when struck",
+}
+
+// property is a magic attribute of an item, with the attributes of the same
+// kind from its sockets added to it.
+type property struct {
+	id     uint64
+	name   string
+	values []int64
+}
+
+// key identifies the properties that are added together, attributes that apply to
+// a skill or class are only added together when it's the same skill or class.
+func (p property) key() string {
+	switch {
+	case p.id == 83 || p.id == 97 || p.id == 107 || p.id == 126 || p.id == 151:
+		return fmt.Sprint(p.id, p.value(0))
+	case p.id == 188:
+		return fmt.Sprint(p.id, p.value(0), p.value(1))
+	case p.id >= 195 && p.id <= 213:
+		// Skills cast on a trigger and charged skills are listed separately.
+		return fmt.Sprint(p.id, p.values)
+	}
+
+	return fmt.Sprint(p.id)
+}
+
+// add adds the values of the other property, the duration of elemental damage
+// is the longest of the two rather than the sum.
+func (p *property) add(other property) {
+	for i := range p.values {
+		if i >= len(other.values) {
+			break
+		}
+
+		switch {
+		case (p.id == 54 || p.id == 57) && i == 2:
+			p.values[i] = max(p.values[i], other.values[i])
+		case p.id == 83 || p.id == 97 || p.id == 107 || p.id == 126 || p.id == 151 || p.id == 188:
+			// Only the level is added, the rest identifies the skill or class.
+			if i == len(p.values)-1 {
+				p.values[i] += other.values[i]
+			}
+		default:
+			p.values[i] += other.values[i]
+		}
+	}
+}
+
+func (p property) value(i int) int64 {
+	if i < len(p.values) {
+		return p.values[i]
+	}
+
+	return 0
+}
+
+// line describes the property the way the game does, properties that aren't
+// shown in game have no line.
+func (p property) line(level uint64) (string, bool) {
+	if strings.Contains(p.name, "Invisible") || strings.Contains(p.name, "char_class") || p.id == 98 {
+		return "", false
+	}
+
+	switch p.id {
+	case 17:
+		return fmt.Sprintf("+%d%% Enhanced Damage", p.value(len(p.values)-1)), true
+	case 48, 50, 52, 54:
+		return damage(p.value(0), p.value(1), damageElements[p.id]), true
+	case 57:
+		// Poison is dealt every frame over the duration, in 256ths of a point.
+		min, max := p.value(0)*p.value(2)/256, p.value(1)*p.value(2)/256
+		return fmt.Sprintf("%s over %d Seconds", damage(min, max, "Poison"), p.value(2)/25), true
+	case 83:
+		return fmt.Sprintf("+%d to %s Skill Levels", p.value(1), domain.ClassName(uint64(p.value(0)))), true
+	case 179, 180:
+		return fmt.Sprintf("+%d to %s Skill Levels", p.value(0), domain.ClassName(classSkills[p.id])), true
+	case 97:
+		return fmt.Sprintf("+%d to %s", p.value(1), domain.SkillName(uint64(p.value(0)))), true
+	case 107:
+		skill := uint64(p.value(0))
+		class, ok := domain.SkillClass(skill)
+		if !ok {
+			return fmt.Sprintf("+%d to %s", p.value(1), domain.SkillName(skill)), true
+		}
+		return fmt.Sprintf("+%d to %s (%s Only)", p.value(1), domain.SkillName(skill), domain.ClassName(class)), true
+	case 126:
+		return fmt.Sprintf("+%d to %s Skills", p.value(1), elements[p.value(0)]), true
+	case 151:
+		return fmt.Sprintf("Level %d %s Aura When Equipped", p.value(1), domain.SkillName(uint64(p.value(0)))), true
+	case 188:
+		class := uint64(p.value(1))
+		return fmt.Sprintf("+%d to %s (%s Only)", p.value(2), domain.SkillTabName(class, uint64(p.value(0))), domain.ClassName(class)), true
+	case 195, 196, 197, 198, 199, 200, 201, 202, 203:
+		trigger, ok := triggers[p.id]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%d%% Chance to cast level %d %s %s", p.value(2), p.value(0), domain.SkillName(uint64(p.value(1))), trigger), true
+	case 204, 205, 206, 207, 208, 209, 210, 211, 212, 213:
+		return fmt.Sprintf("Level %d %s (%d/%d Charges)", p.value(0), domain.SkillName(uint64(p.value(1))), p.value(2), p.value(3)), true
+	case 252:
+		if p.value(0) <= 0 {
+			return "", false
+		}
+		return fmt.Sprintf("Repairs 1 Durability in %d Seconds", 100/p.value(0)), true
+	}
+
+	template, ok := templates[p.id]
+	if !ok {
+		template = p.name
+	}
+
+	values := p.values
+	if fraction, ok := perLevel[p.id]; ok {
+		values = []int64{p.value(0) * int64(level) / fraction}
+	}
+
+	line := template
+	for i, v := range values {
+		line = strings.ReplaceAll(line, "{"+strconv.Itoa(i)+"}", strconv.FormatInt(v, 10))
+	}
+
+	// Templates are written for positive values.
+	return strings.ReplaceAll(line, "+-", "-"), true
+}
+
+// damage describes elemental damage added to attacks.
+func damage(min int64, max int64, element string) string {
+	if min == max {
+		return fmt.Sprintf("+%d %s Damage", min, element)
+	}
+
+	return fmt.Sprintf("Adds %d-%d %s Damage", min, max, element)
+}
@@ -0,0 +1,288 @@
+// Package tooltip describes items the way the game does when hovering them.
+package tooltip
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+// lowQualityNames are the prefixes of low quality items.
+var lowQualityNames = map[uint64]string{
+	0: "Crude",
+	1: "Cracked",
+	2: "Damaged",
+	3: "Low Quality",
+}
+
+// runeNames maps the item codes of runes to their name.
+var runeNames = map[string]string{
+	"r01": "El", "r02": "Eld", "r03": "Tir", "r04": "Nef", "r05": "Eth", "r06": "Ith",
+	"r07": "Tal", "r08": "Ral", "r09": "Ort", "r10": "Thul", "r11": "Amn", "r12": "Sol",
+	"r13": "Shael", "r14": "Dol", "r15": "Hel", "r16": "Io", "r17": "Lum", "r18": "Ko",
+	"r19": "Fal", "r20": "Lem", "r21": "Pul", "r22": "Um", "r23": "Mal", "r24": "Ist",
+	"r25": "Gul", "r26": "Vex", "r27": "Ohm", "r28": "Lo", "r29": "Sur", "r30": "Ber",
+	"r31": "Jah", "r32": "Cham", "r33": "Zod",
+}
+
+// ForCharacter returns the tooltips of all items of the character.
+func ForCharacter(character *d2s.Character) *domain.ItemTooltips {
+	level := uint64(character.Header.Level)
+
+	return &domain.ItemTooltips{
+		Items:       all(character.Items, level),
+		CorpseItems: all(character.CorpseItems, level),
+		MercItems:   all(character.MercItems, level),
+	}
+}
+
+func all(items []d2s.Item, level uint64) [][]string {
+	tooltips := make([][]string, 0, len(items))
+	for _, item := range items {
+		tooltips = append(tooltips, Lines(item, level))
+	}
+
+	return tooltips
+}
+
+// Lines returns the lines of the tooltip of the item, starting with its name.
+// Attributes that grow with the level of the character are computed for the level.
+func Lines(item d2s.Item, level uint64) []string {
+	lines := name(item)
+
+	if item.DefenseRating > 0 {
+		lines = append(lines, fmt.Sprintf("Defense: %d", item.DefenseRating))
+	}
+
+	if d := item.BaseDamage; d != nil {
+		if d.Max > 0 {
+			lines = append(lines, fmt.Sprintf("One-Hand Damage: %d to %d", d.Min, d.Max))
+		}
+		if d.TwoMax > 0 {
+			lines = append(lines, fmt.Sprintf("Two-Hand Damage: %d to %d", d.TwoMin, d.TwoMax))
+		}
+	}
+
+	if item.Quantity > 0 {
+		lines = append(lines, fmt.Sprintf("Quantity: %d", item.Quantity))
+	}
+
+	if item.MaxDurability > 0 {
+		lines = append(lines, fmt.Sprintf("Durability: %d of %d", item.CurrentDurability, item.MaxDurability))
+	}
+
+	var attributes []property
+	for _, a := range item.MagicAttributes {
+		attributes = append(attributes, newProperty(a.ID, a.Name, a.Values))
+	}
+	for _, a := range item.RunewordAttributes {
+		attributes = append(attributes, newProperty(a.ID, a.Name, a.Values))
+	}
+	// The attributes of socketed items are part of the attributes of the item.
+	for _, socketed := range item.SocketedItems {
+		for _, a := range socketed.MagicAttributes {
+			attributes = append(attributes, newProperty(a.ID, a.Name, a.Values))
+		}
+	}
+
+	lines = append(lines, describe(attributes, level)...)
+
+	var footer []string
+	if item.Ethereal == 1 {
+		footer = append(footer, "Ethereal (Cannot be Repaired)")
+	}
+	if item.TotalNrOfSockets > 0 {
+		footer = append(footer, fmt.Sprintf("Socketed (%d)", item.TotalNrOfSockets))
+	}
+	if len(footer) > 0 {
+		lines = append(lines, strings.Join(footer, ", "))
+	}
+
+	// Set bonuses of the item, the first list is active with two items of the set worn.
+	for i, bonuses := range item.SetAttributes {
+		var set []property
+		for _, a := range bonuses {
+			set = append(set, newProperty(a.ID, a.Name, a.Values))
+		}
+
+		for _, line := range describe(set, level) {
+			lines = append(lines, fmt.Sprintf("%s (%d Items)", line, i+2))
+		}
+	}
+
+	return lines
+}
+
+// name returns the lines naming the item, items with a name of their own are
+// named on top of the name of their base type.
+func name(item d2s.Item) []string {
+	var lines []string
+
+	switch {
+	case item.RunewordName != "":
+		lines = []string{item.RunewordName, item.TypeName}
+
+		var runes strings.Builder
+		for _, socketed := range item.SocketedItems {
+			runes.WriteString(runeNames[socketed.Type])
+		}
+		lines = append(lines, "'"+runes.String()+"'")
+	case item.UniqueName != "":
+		lines = []string{item.UniqueName, item.TypeName}
+	case item.SetName != "":
+		lines = []string{item.SetName, item.TypeName}
+	case item.RareName != "":
+		lines = []string{domain.ItemName(item), item.TypeName}
+	case item.Quality == domain.ItemQualityMagic:
+		lines = []string{join(item.MagicPrefixName, item.TypeName, item.MagicSuffixName)}
+	case item.Quality == domain.ItemQualitySuperior:
+		lines = []string{"Superior " + item.TypeName}
+	case item.Quality == domain.ItemQualityLow:
+		lines = []string{join(lowQualityNames[item.LowQualityID], item.TypeName)}
+	default:
+		lines = []string{item.TypeName}
+	}
+
+	if item.Personalized == 1 && item.PersonalizedName != "" {
+		lines[0] = item.PersonalizedName + "'s " + lines[0]
+	}
+
+	return lines
+}
+
+// join joins the non-empty parts of a name.
+func join(parts ...string) string {
+	var name []string
+	for _, part := range parts {
+		if part != "" {
+			name = append(name, part)
+		}
+	}
+
+	return strings.Join(name, " ")
+}
+
+// newProperty copies the values so adding to the property leaves the item untouched.
+func newProperty(id uint64, name string, values []int64) property {
+	return property{id: id, name: name, values: append([]int64(nil), values...)}
+}
+
+// describe returns the lines of the attributes in the order the game lists them,
+// attributes of the same kind are added together and groups of attributes with
+// the same value are shown as one line.
+func describe(attributes []property, level uint64) []string {
+	var (
+		merged []property
+		index  = make(map[string]int)
+	)
+
+	for _, attribute := range attributes {
+		if i, ok := index[attribute.key()]; ok {
+			merged[i].add(attribute)
+			continue
+		}
+
+		index[attribute.key()] = len(merged)
+		merged = append(merged, attribute)
+	}
+
+	type line struct {
+		text     string
+		priority int
+	}
+
+	var (
+		lines   []line
+		grouped = make(map[uint64]bool)
+	)
+
+	for _, group := range []struct {
+		ids  []uint64
+		text string
+	}{
+		{allAttributes, "+%d to all Attributes"},
+		{allResistances, "All Resistances +%d"},
+		{allMaxResistances, "+%d%% to all Maximum Resistances"},
+	} {
+		value, ok := sameValue(merged, index, group.ids)
+		if !ok {
+			continue
+		}
+
+		for _, id := range group.ids {
+			grouped[id] = true
+		}
+
+		lines = append(lines, line{
+			text:     strings.ReplaceAll(fmt.Sprintf(group.text, value), "+-", "-"),
+			priority: priorities[group.ids[0]],
+		})
+	}
+
+	// Items add the same two-handed damage as one-handed damage, it's only
+	// shown on its own for items that add no one-handed damage.
+	_, hasMin := index["21"]
+	_, hasMax := index["22"]
+	if hasMin || hasMax {
+		grouped[23], grouped[24] = true, true
+	}
+
+	// Minimum and maximum damage are shown as a range.
+	for _, ids := range [][2]uint64{{21, 22}, {23, 24}} {
+		lo, hasLo := index[fmt.Sprint(ids[0])]
+		hi, hasHi := index[fmt.Sprint(ids[1])]
+		if !hasLo || !hasHi || grouped[ids[0]] {
+			continue
+		}
+
+		grouped[ids[0]], grouped[ids[1]] = true, true
+		lines = append(lines, line{
+			text:     fmt.Sprintf("Adds %d-%d Damage", merged[lo].value(0), merged[hi].value(0)),
+			priority: priorities[ids[0]],
+		})
+	}
+
+	for _, attribute := range merged {
+		if grouped[attribute.id] {
+			continue
+		}
+
+		if text, ok := attribute.line(level); ok {
+			lines = append(lines, line{text: text, priority: priorities[attribute.id]})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].priority > lines[j].priority
+	})
+
+	texts := make([]string, 0, len(lines))
+	for _, l := range lines {
+		texts = append(texts, l.text)
+	}
+
+	return texts
+}
+
+// sameValue returns the value of the attributes when they're all present with the same value.
+func sameValue(attributes []property, index map[string]int, ids []uint64) (int64, bool) {
+	var value int64
+
+	for n, id := range ids {
+		i, ok := index[fmt.Sprint(id)]
+		if !ok {
+			return 0, false
+		}
+
+		v := attributes[i].value(0)
+		if n > 0 && v != value {
+			return 0, false
+		}
+		value = v
+	}
+
+	return value, true
+}
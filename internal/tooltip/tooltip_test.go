@@ -0,0 +1,175 @@
+package tooltip
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/nokka/d2s"
+)
+
+// item decodes an item from its JSON representation, the attributes of d2s
+// items can't be constructed outside of the package.
+func item(t *testing.T, data string) d2s.Item {
+	t.Helper()
+
+	var i d2s.Item
+	if err := json.Unmarshal([]byte(data), &i); err != nil {
+		t.Fatalf("failed to decode item: %v", err)
+	}
+
+	return i
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     string
+		level    uint64
+		expected []string
+	}{
+		{
+			name: "unique ordered by priority",
+			item: `{
+				"type_name": "Shako", "unique_name": "Harlequin Crest", "quality": 7,
+				"defense_rating": 141, "max_durability": 12, "current_durability": 10,
+				"magic_attributes": [
+					{"id": 80, "name": "{0}% Better Chance of Getting Magic Items", "values": [50]},
+					{"id": 36, "name": "Damage Reduced by {0}%", "values": [10]},
+					{"id": 216, "name": "+{0} to Life (Based on Character Level)", "values": [6]},
+					{"id": 127, "name": "+{0} to All Skill Levels", "values": [2]},
+					{"id": 0, "name": "+{0} to Strength", "values": [2]},
+					{"id": 1, "name": "+{0} to Energy", "values": [2]},
+					{"id": 2, "name": "+{0} to Dexterity", "values": [2]},
+					{"id": 3, "name": "+{0} to Vitality", "values": [2]}
+				]
+			}`,
+			level: 80,
+			expected: []string{
+				"Harlequin Crest",
+				"Shako",
+				"Defense: 141",
+				"Durability: 10 of 12",
+				"+2 to All Skill Levels",
+				"+2 to all Attributes",
+				"+60 to Life (Based on Character Level)",
+				"Damage Reduced by 10%",
+				"50% Better Chance of Getting Magic Items",
+			},
+		},
+		{
+			name: "runeword with socketed runes",
+			item: `{
+				"type_name": "Monarch", "runeword_name": "Spirit", "total_nr_of_sockets": 4,
+				"runeword_attributes": [
+					{"id": 127, "name": "+{0} to All Skill Levels", "values": [2]},
+					{"id": 105, "name": "{0}% Faster Cast Rate", "values": [35]},
+					{"id": 39, "name": "Fire Resist +{0}%", "values": [35]},
+					{"id": 41, "name": "Lightning Resist +{0}%", "values": [35]},
+					{"id": 43, "name": "Cold Resist +{0}%", "values": [35]},
+					{"id": 45, "name": "Poison Resist +{0}%", "values": [35]}
+				],
+				"socketed_items": [
+					{"type": "r07", "magic_attributes": [{"id": 45, "name": "Poison Resist +{0}%", "values": [35]}]},
+					{"type": "r10", "magic_attributes": [{"id": 43, "name": "Cold Resist +{0}%", "values": [35]}]},
+					{"type": "r09", "magic_attributes": [{"id": 41, "name": "Lightning Resist +{0}%", "values": [35]}]},
+					{"type": "r11", "magic_attributes": [{"id": 78, "name": "Attacker Takes Damage of {0}", "values": [14]}]}
+				]
+			}`,
+			expected: []string{
+				"Spirit",
+				"Monarch",
+				"'TalThulOrtAmn'",
+				"+2 to All Skill Levels",
+				"35% Faster Cast Rate",
+				"Fire Resist +35%",
+				"Lightning Resist +70%",
+				"Cold Resist +70%",
+				"Poison Resist +70%",
+				"Attacker Takes Damage of 14",
+				"Socketed (4)",
+			},
+		},
+		{
+			name: "magic skills and damage",
+			item: `{
+				"type_name": "Grand Charm", "quality": 4,
+				"magic_prefix_name": "Sparking", "magic_suffix_name": "of Life",
+				"magic_attributes": [
+					{"id": 188, "name": "+{2} to {0} Skills ({1} only)", "values": [1, 1, 1]},
+					{"id": 50, "name": "Adds {0}-{1} Lightning Damage", "values": [1, 15]},
+					{"id": 7, "name": "+{0} to Life", "values": [-5]},
+					{"id": 21, "name": "+{0} to Minimum 1-handed damage", "values": [3]},
+					{"id": 22, "name": "+{0} to Maximum 1-handed damage", "values": [7]},
+					{"id": 23, "name": "+{0} to Minimum 2-handed damage", "values": [3]},
+					{"id": 24, "name": "+{0} to Maximum 2-handed damage", "values": [7]},
+					{"id": 92, "name": "Level requirements +{0} (Invisible)", "values": [1]}
+				]
+			}`,
+			expected: []string{
+				"Sparking Grand Charm of Life",
+				"+1 to Lightning Skills (Sorceress Only)",
+				"Adds 3-7 Damage",
+				"Adds 1-15 Lightning Damage",
+				"-5 to Life",
+			},
+		},
+		{
+			name: "ethereal with charges and chance to cast",
+			item: `{
+				"type_name": "Cryptic Axe", "quality": 2, "ethereal": 1, "total_nr_of_sockets": 5,
+				"magic_attributes": [
+					{"id": 204, "name": "Level {0} {1} ({2}/{3} Charges)", "values": [3, 54, 30, 30]},
+					{"id": 198, "name": "{2}% Chance to Cast Level {0} {1} On Striking", "values": [15, 53, 10]},
+					{"id": 57, "name": "Adds {0}-{1} Poison Damage over {2} Seconds", "values": [154, 154, 125]}
+				]
+			}`,
+			expected: []string{
+				"Cryptic Axe",
+				"10% Chance to cast level 15 Chain Lightning on striking",
+				"+75 Poison Damage over 5 Seconds",
+				"Level 3 Teleport (30/30 Charges)",
+				"Ethereal (Cannot be Repaired), Socketed (5)",
+			},
+		},
+		{
+			name: "poison damage range and skill of no class",
+			item: `{
+				"type_name": "Small Charm", "quality": 4, "magic_prefix_name": "Pestilent",
+				"magic_attributes": [
+					{"id": 57, "name": "Adds {0}-{1} Poison Damage over {2} Seconds", "values": [128, 256, 100]},
+					{"id": 107, "name": "+{1} to {0}", "values": [190, 1]}
+				]
+			}`,
+			expected: []string{
+				"Pestilent Small Charm",
+				"Adds 50-100 Poison Damage over 4 Seconds",
+				"+1 to Skill 190",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := Lines(item(t, tt.item), tt.level)
+
+			if !reflect.DeepEqual(lines, tt.expected) {
+				t.Errorf("unexpected lines\nexpected = %q\ngot      = %q", tt.expected, lines)
+			}
+		})
+	}
+}
+
+func TestLinesLeavesItemUntouched(t *testing.T) {
+	i := item(t, `{
+		"type_name": "Jewel",
+		"magic_attributes": [{"id": 39, "name": "Fire Resist +{0}%", "values": [15]}],
+		"socketed_items": [{"magic_attributes": [{"id": 39, "name": "Fire Resist +{0}%", "values": [15]}]}]
+	}`)
+
+	Lines(i, 1)
+
+	if v := i.MagicAttributes[0].Values[0]; v != 15 {
+		t.Errorf("expected the attributes of the item to be left untouched, got = %d", v)
+	}
+}
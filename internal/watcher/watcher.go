@@ -0,0 +1,235 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./watcher_mocks.go . characterService
+
+// characterService is the interface representation of the service
+// the watcher uses to re-parse characters that changed on disk.
+type characterService interface {
+	Refresh(ctx context.Context, name string) (*domain.Character, error)
+}
+
+// Watcher watches the d2s directory and refreshes characters as soon as
+// their binary is written, instead of waiting for the cache to expire.
+type Watcher struct {
+	path             string
+	characterService characterService
+	debounce         time.Duration
+	pollInterval     time.Duration
+	polling          bool
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// fileState is what the poller compares between scans to detect writes.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Run will refresh all characters once and then watch the directory for
+// changes until the context is cancelled. Filesystem notifications are used
+// when available, otherwise the directory is polled.
+func (w *Watcher) Run(ctx context.Context) error {
+	if !w.polling {
+		fsw, err := fsnotify.NewWatcher()
+		if err == nil {
+			if err = fsw.Add(w.path); err == nil {
+				defer fsw.Close()
+				return w.notify(ctx, fsw)
+			}
+			fsw.Close()
+		}
+
+		log.Printf("failed to start filesystem notifications, falling back to polling: %v", err)
+	}
+
+	return w.poll(ctx)
+}
+
+// notify consumes filesystem notifications from the watched directory.
+func (w *Watcher) notify(ctx context.Context, fsw *fsnotify.Watcher) error {
+	log.Printf("watching %s for character changes", w.path)
+
+	// The notifications are already being queued, so anything written while
+	// we sync will be picked up afterwards.
+	if _, err := w.scan(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.stop()
+			return nil
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+
+			// The game server creates or writes the save, anything else is irrelevant.
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			w.schedule(ctx, filepath.Base(event.Name))
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+
+			log.Printf("filesystem watcher error: %v", err)
+		}
+	}
+}
+
+// poll compares the directory between scans, used when notifications aren't supported.
+func (w *Watcher) poll(ctx context.Context) error {
+	log.Printf("polling %s for character changes every %s", w.path, w.pollInterval)
+
+	previous, err := w.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.stop()
+			return nil
+		case <-ticker.C:
+			current, err := w.states()
+			if err != nil {
+				log.Printf("failed to poll d2s directory: %v", err)
+				continue
+			}
+
+			for name, state := range current {
+				if prev, ok := previous[name]; !ok || prev != state {
+					w.schedule(ctx, name)
+				}
+			}
+
+			previous = current
+		}
+	}
+}
+
+// scan refreshes every character in the directory and returns the state
+// of the files, it's used to sync with changes made while we weren't watching.
+func (w *Watcher) scan(ctx context.Context) (map[string]fileState, error) {
+	states, err := w.states()
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range states {
+		if ctx.Err() != nil {
+			break
+		}
+
+		w.refresh(ctx, name)
+	}
+
+	log.Printf("watcher synced %d characters", len(states))
+
+	return states, nil
+}
+
+// states returns the size and modification time of all files in the directory.
+func (w *Watcher) states() (map[string]fileState, error) {
+	files, err := os.ReadDir(w.path)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]fileState, len(files))
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			// The file was removed since we read the directory.
+			continue
+		}
+
+		states[file.Name()] = fileState{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+
+	return states, nil
+}
+
+// schedule debounces the refresh of a character, the game server writes
+// a save in multiple passes so we wait for it to settle before parsing.
+func (w *Watcher) schedule(ctx context.Context, name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if timer, ok := w.pending[name]; ok {
+		timer.Stop()
+	}
+
+	w.pending[name] = time.AfterFunc(w.debounce, func() {
+		w.mu.Lock()
+		delete(w.pending, name)
+		w.mu.Unlock()
+
+		w.refresh(ctx, name)
+	})
+}
+
+// stop cancels all pending refreshes.
+func (w *Watcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for name, timer := range w.pending {
+		timer.Stop()
+		delete(w.pending, name)
+	}
+}
+
+func (w *Watcher) refresh(ctx context.Context, name string) {
+	if _, err := w.characterService.Refresh(ctx, name); err != nil {
+		// Files that aren't characters live in the same directory, skip them quietly.
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			return
+		}
+
+		log.Printf("failed to refresh character %s: %v", name, err)
+	}
+}
+
+// NewWatcher returns a new watcher of the d2s directory with all dependencies.
+func NewWatcher(path string, characterService characterService, debounce time.Duration, pollInterval time.Duration, polling bool) *Watcher {
+	return &Watcher{
+		path:             path,
+		characterService: characterService,
+		debounce:         debounce,
+		pollInterval:     pollInterval,
+		polling:          polling,
+		pending:          make(map[string]*time.Timer),
+	}
+}
@@ -0,0 +1,87 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestWatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		polling bool
+	}{
+		{name: "filesystem notifications", polling: false},
+		{name: "polling", polling: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if err := os.WriteFile(filepath.Join(dir, "nokka"), []byte("initial"), 0o600); err != nil {
+				t.Fatalf("failed to write character: %v", err)
+			}
+
+			refreshed := make(chan string, 10)
+			service := &characterServiceMock{
+				RefreshFunc: func(ctx context.Context, name string) (*domain.Character, error) {
+					refreshed <- name
+					return &domain.Character{ID: name}, nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			w := NewWatcher(dir, service, 50*time.Millisecond, 20*time.Millisecond, tt.polling)
+
+			done := make(chan error)
+			go func() {
+				done <- w.Run(ctx)
+			}()
+
+			// The initial sync refreshes all characters on disk.
+			expectRefresh(t, refreshed, "nokka")
+
+			// Several writes in quick succession should only refresh once.
+			for i := 0; i < 3; i++ {
+				if err := os.WriteFile(filepath.Join(dir, "nokka"), []byte{byte(i), 1, 2, 3, 4, 5, 6, 7, 8}, 0o600); err != nil {
+					t.Fatalf("failed to write character: %v", err)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			expectRefresh(t, refreshed, "nokka")
+
+			select {
+			case name := <-refreshed:
+				t.Errorf("expected writes to be debounced, got another refresh of %s", name)
+			case <-time.After(200 * time.Millisecond):
+			}
+
+			cancel()
+
+			if err := <-done; err != nil {
+				t.Errorf("didn't expect an error, got = %v", err)
+			}
+		})
+	}
+}
+
+func expectRefresh(t *testing.T, refreshed chan string, want string) {
+	t.Helper()
+
+	select {
+	case name := <-refreshed:
+		if name != want {
+			t.Errorf("expected %s to be refreshed, got = %s", want, name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected %s to be refreshed", want)
+	}
+}
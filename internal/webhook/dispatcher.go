@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+// Headers sent with every delivery.
+const (
+	headerEvent     = "X-Armory-Event"
+	headerDelivery  = "X-Armory-Delivery"
+	headerSignature = "X-Armory-Signature"
+)
+
+const (
+	// batchSize is the max number of deliveries attempted on every tick.
+	batchSize = 50
+
+	// Backoff between attempts, doubled on every failed attempt.
+	initialBackoff = 10 * time.Second
+	maxBackoff     = time.Hour
+
+	// deliveryTimeout is how long a target has to respond.
+	deliveryTimeout = 10 * time.Second
+
+	// targetConcurrency is the max number of targets delivered to at once.
+	targetConcurrency = 8
+)
+
+// Dispatcher pushes the deliveries in the outbox to their targets. Failed
+// deliveries are retried with an exponential backoff until they run out of
+// attempts, after which they're kept in the outbox marked as failed.
+type Dispatcher struct {
+	outbox       outboxRepository
+	targets      map[string]domain.WebhookTarget
+	client       *http.Client
+	pollInterval time.Duration
+	maxAttempts  int
+}
+
+// Run dispatches deliveries every poll interval until the context is done.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(ctx, time.Now()); err != nil {
+			log.Printf("failed to dispatch webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch attempts the deliveries that are due at the given time. Deliveries
+// are grouped by target and the targets are delivered to concurrently, so a
+// slow or failing target doesn't hold up the deliveries to the others.
+func (d *Dispatcher) Dispatch(ctx context.Context, now time.Time) error {
+	deliveries, err := d.outbox.Due(ctx, now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	var order []string
+	byTarget := make(map[string][]domain.Delivery)
+
+	for _, delivery := range deliveries {
+		if _, ok := byTarget[delivery.Target]; !ok {
+			order = append(order, delivery.Target)
+		}
+		byTarget[delivery.Target] = append(byTarget[delivery.Target], delivery)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(targetConcurrency)
+
+	for _, target := range order {
+		deliveries := byTarget[target]
+
+		g.Go(func() error {
+			for _, delivery := range deliveries {
+				if err := d.attempt(ctx, now, delivery); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// attempt delivers to the target and removes the delivery from the outbox, or
+// schedules the next attempt if the delivery failed.
+func (d *Dispatcher) attempt(ctx context.Context, now time.Time, delivery domain.Delivery) error {
+	err := d.deliver(ctx, delivery)
+	if err == nil {
+		return d.outbox.Delete(ctx, delivery.ID)
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	delivery.NextAttempt = now.Add(backoff(delivery.Attempts))
+	delivery.Failed = delivery.Attempts >= d.maxAttempts
+
+	if delivery.Failed {
+		log.Printf("giving up on %s delivery %s to %s: %v", delivery.EventType, delivery.ID, delivery.Target, err)
+	}
+
+	return d.outbox.Update(ctx, delivery)
+}
+
+// deliver posts the payload to the target, signed with the secret of the target.
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.Delivery) error {
+	target, ok := d.targets[delivery.Target]
+	if !ok {
+		return errors.New("target is no longer configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerEvent, delivery.EventType)
+	req.Header.Set(headerDelivery, delivery.ID)
+
+	if target.Secret != "" {
+		req.Header.Set(headerSignature, Sign(target.Secret, delivery.Payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("target responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the signature of the payload sent in the X-Armory-Signature
+// header, the hex encoded HMAC-SHA256 of the payload keyed with the secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns how long to wait before the next attempt after the given
+// number of failed attempts.
+func backoff(attempts int) time.Duration {
+	wait := initialBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+
+	if wait > maxBackoff {
+		return maxBackoff
+	}
+
+	return wait
+}
+
+// NewDispatcher constructs a new dispatcher delivering to the targets.
+func NewDispatcher(outbox outboxRepository, targets []domain.WebhookTarget, pollInterval time.Duration, maxAttempts int) *Dispatcher {
+	byURL := make(map[string]domain.WebhookTarget, len(targets))
+	for _, target := range targets {
+		byURL[target.URL] = target
+	}
+
+	return &Dispatcher{
+		outbox:       outbox,
+		targets:      byURL,
+		client:       &http.Client{},
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+	}
+}
@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+func TestDispatch(t *testing.T) {
+	payload := []byte(`{"type":"character.died"}`)
+
+	tests := []struct {
+		name        string
+		status      int
+		attempts    int
+		deleted     bool
+		failed      bool
+		nextAttempt time.Duration
+	}{
+		{
+			name:    "delivered",
+			status:  http.StatusNoContent,
+			deleted: true,
+		},
+		{
+			name:        "retried with backoff",
+			status:      http.StatusInternalServerError,
+			attempts:    2,
+			nextAttempt: 4 * initialBackoff,
+		},
+		{
+			name:        "out of attempts",
+			status:      http.StatusInternalServerError,
+			attempts:    4,
+			failed:      true,
+			nextAttempt: 16 * initialBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			now := time.Now()
+
+			outbox := &outboxRepositoryMock{
+				DueFunc: func(ctx context.Context, at time.Time, limit int) ([]domain.Delivery, error) {
+					return []domain.Delivery{{
+						ID:          "d1",
+						Target:      srv.URL,
+						EventType:   domain.EventCharacterDied,
+						Payload:     payload,
+						Attempts:    tt.attempts,
+						NextAttempt: now,
+					}}, nil
+				},
+				DeleteFunc: func(ctx context.Context, id string) error {
+					return nil
+				},
+				UpdateFunc: func(ctx context.Context, delivery domain.Delivery) error {
+					return nil
+				},
+			}
+
+			d := NewDispatcher(outbox, []domain.WebhookTarget{{URL: srv.URL, Secret: "s3cr3t"}}, time.Second, 5)
+
+			if err := d.Dispatch(context.TODO(), now); err != nil {
+				t.Fatalf("didn't expect an error, got = %v", err)
+			}
+
+			if received == nil {
+				t.Fatal("expected the delivery to be posted")
+			}
+
+			if got := received.Header.Get(headerSignature); got != Sign("s3cr3t", body) {
+				t.Errorf("expected the payload to be signed, got = %s", got)
+			}
+
+			if received.Header.Get(headerEvent) != domain.EventCharacterDied || received.Header.Get(headerDelivery) != "d1" {
+				t.Errorf("expected event and delivery headers, got = %v", received.Header)
+			}
+
+			if deleted := len(outbox.DeleteCalls()) == 1; deleted != tt.deleted {
+				t.Errorf("expected deleted to be %t", tt.deleted)
+			}
+
+			if tt.deleted {
+				return
+			}
+
+			update := outbox.UpdateCalls()[0].Delivery
+			if update.Attempts != tt.attempts+1 || update.Failed != tt.failed || update.LastError == "" {
+				t.Errorf("unexpected delivery update, got = %+v", update)
+			}
+
+			if got := update.NextAttempt.Sub(now); got != tt.nextAttempt {
+				t.Errorf("expected next attempt in %s, got = %s", tt.nextAttempt, got)
+			}
+		})
+	}
+}
+
+func TestDispatchSlowTarget(t *testing.T) {
+	delivered := make(chan struct{})
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(delivered)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer fast.Close()
+
+	// The slow target only responds once the fast target got its delivery,
+	// or gives up waiting and fails the delivery.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-delivered:
+			w.WriteHeader(http.StatusNoContent)
+		case <-time.After(2 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer slow.Close()
+
+	outbox := &outboxRepositoryMock{
+		DueFunc: func(ctx context.Context, at time.Time, limit int) ([]domain.Delivery, error) {
+			return []domain.Delivery{
+				{ID: "d1", Target: slow.URL, EventType: domain.EventCharacterDied},
+				{ID: "d2", Target: fast.URL, EventType: domain.EventCharacterDied},
+			}, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			return nil
+		},
+		UpdateFunc: func(ctx context.Context, delivery domain.Delivery) error {
+			return nil
+		},
+	}
+
+	d := NewDispatcher(outbox, []domain.WebhookTarget{{URL: slow.URL}, {URL: fast.URL}}, time.Second, 5)
+
+	if err := d.Dispatch(context.TODO(), time.Now()); err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	if got := len(outbox.DeleteCalls()); got != 2 {
+		t.Errorf("expected the slow target not to hold up the fast one, got = %d deliveries, %d failures", got, len(outbox.UpdateCalls()))
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Signature of the payload as calculated by `openssl dgst -sha256 -hmac s3cr3t`.
+	want := "sha256=9747a46cf3eeff4c181f0e08bc0388aaf2e49e139bad03dd7fefec920b08b082"
+
+	if got := Sign("s3cr3t", []byte("payload")); got != want {
+		t.Errorf("expected signature %s, got = %s", want, got)
+	}
+}
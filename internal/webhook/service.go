@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/diff"
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+//go:generate moq -out ./service_mocks.go . outboxRepository
+
+// milestones are the levels that are celebrated with an event of their own.
+var milestones = []uint64{25, 50, 75, 90, 95, 99}
+
+// outboxRepository is the interface representation of the data layer
+// the service depend on.
+type outboxRepository interface {
+	Enqueue(ctx context.Context, deliveries []domain.Delivery) error
+	Due(ctx context.Context, at time.Time, limit int) ([]domain.Delivery, error)
+	Update(ctx context.Context, delivery domain.Delivery) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Service turns what happens to characters into events, and puts a delivery
+// of every event in the outbox for each target that accepts it.
+type Service struct {
+	outbox  outboxRepository
+	targets []domain.WebhookTarget
+}
+
+// CharacterParsed emits the events of what changed since the character was last parsed.
+func (s Service) CharacterParsed(ctx context.Context, previous *domain.Character, current *domain.Character) {
+	if current == nil || current.D2s == nil {
+		return
+	}
+
+	for _, event := range characterEvents(previous, current) {
+		if err := s.Emit(ctx, event); err != nil {
+			log.Printf("failed to emit %s event of character %s: %v", event.Type, current.ID, err)
+		}
+	}
+}
+
+// StatisticsPosted emits an event for statistics posted by the game server.
+func (s Service) StatisticsPosted(ctx context.Context, stat domain.StatisticsRequest) {
+	event := domain.Event{
+		Type:       domain.EventStatisticsPosted,
+		Character:  stat.Character,
+		Message:    fmt.Sprintf("%s posted statistics for %s", stat.Character, stat.Difficulty),
+		OccurredAt: time.Now(),
+		Data:       stat,
+	}
+
+	if err := s.Emit(ctx, event); err != nil {
+		log.Printf("failed to emit %s event of character %s: %v", event.Type, stat.Character, err)
+	}
+}
+
+// Emit puts a delivery of the event in the outbox for every target that accepts it.
+func (s Service) Emit(ctx context.Context, event domain.Event) error {
+	if event.ID == "" {
+		event.ID = newID()
+	}
+
+	var deliveries []domain.Delivery
+
+	for _, target := range s.targets {
+		if !target.Accepts(event.Type) {
+			continue
+		}
+
+		payload, err := encode(event, target.Format)
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, domain.Delivery{
+			ID:          newID(),
+			Target:      target.URL,
+			EventID:     event.ID,
+			EventType:   event.Type,
+			Payload:     payload,
+			NextAttempt: event.OccurredAt,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.outbox.Enqueue(ctx, deliveries)
+}
+
+// characterEvents returns the events of what changed between two versions of
+// a character, the character is new when there's no previous version.
+func characterEvents(previous *domain.Character, current *domain.Character) []domain.Event {
+	event := func(typ string, message string, data interface{}) domain.Event {
+		return domain.Event{
+			Type:       typ,
+			Realm:      current.Realm,
+			Character:  current.ID,
+			Message:    message,
+			OccurredAt: current.LastParsed,
+			Data:       data,
+		}
+	}
+
+	if previous == nil || previous.D2s == nil {
+		summary := current.Summary()
+		return []domain.Event{
+			event(domain.EventCharacterCreated, fmt.Sprintf("%s the %s was created", current.ID, summary.Class), summary),
+		}
+	}
+
+	var events []domain.Event
+
+	from, to := previous.D2s.Attributes.Level, current.D2s.Attributes.Level
+	if to > from {
+		events = append(events, event(domain.EventCharacterLevelUp,
+			fmt.Sprintf("%s reached level %d", current.ID, to),
+			domain.ValueChange{From: from, To: to, Change: int64(to - from)},
+		))
+
+		for _, level := range milestones {
+			if level > from && level <= to {
+				events = append(events, event(domain.EventCharacterMilestone,
+					fmt.Sprintf("%s reached the level %d milestone", current.ID, level),
+					struct {
+						Level uint64 `json:"level"`
+					}{Level: level},
+				))
+			}
+		}
+	}
+
+	for _, item := range diff.Compare(previous, current).Items.Added {
+		if item.Quality != domain.ItemQualityUnique && item.Quality != domain.ItemQualitySet {
+			continue
+		}
+
+		events = append(events, event(domain.EventItemFound,
+			fmt.Sprintf("%s found %s", current.ID, item.Name),
+			item,
+		))
+	}
+
+	if !previous.Dead() && current.Dead() {
+		events = append(events, event(domain.EventCharacterDied,
+			fmt.Sprintf("%s died at level %d", current.ID, to),
+			current.Summary(),
+		))
+	}
+
+	return events
+}
+
+// encode encodes the event in the format of the target.
+func encode(event domain.Event, format string) ([]byte, error) {
+	switch format {
+	case domain.WebhookFormatDiscord:
+		return json.Marshal(struct {
+			Content string `json:"content"`
+		}{
+			Content: event.Message,
+		})
+	default:
+		return json.Marshal(event)
+	}
+}
+
+// newID returns a random identifier for events and deliveries.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewService constructs a new webhook service with all the dependencies.
+func NewService(outbox outboxRepository, targets []domain.WebhookTarget) *Service {
+	return &Service{
+		outbox:  outbox,
+		targets: targets,
+	}
+}
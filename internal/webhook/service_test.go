@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+	"github.com/nokka/d2s"
+)
+
+func TestCharacterParsed(t *testing.T) {
+	character := func(level uint64, items ...d2s.Item) *domain.Character {
+		return &domain.Character{
+			ID:         "nokka",
+			LastParsed: time.Now(),
+			D2s: &d2s.Character{
+				Attributes: d2s.Attributes{Level: level},
+				Items:      items,
+			},
+		}
+	}
+
+	shako := d2s.Item{ID: 1, Type: "uap", UniqueName: "Harlequin Crest", Quality: domain.ItemQualityUnique, LocationID: 1}
+	rare := d2s.Item{ID: 2, Type: "rin", RareName: "Dread", RareName2: "Loop", Quality: domain.ItemQualityRare, LocationID: 1}
+
+	dead := character(60)
+	dead.D2s.Header.Status = 1<<2 | 1<<3
+
+	tests := []struct {
+		name     string
+		previous *domain.Character
+		current  *domain.Character
+		expected []string
+	}{
+		{
+			name:     "new character",
+			current:  character(1),
+			expected: []string{domain.EventCharacterCreated},
+		},
+		{
+			name:     "level up",
+			previous: character(23),
+			current:  character(24),
+			expected: []string{domain.EventCharacterLevelUp},
+		},
+		{
+			name:     "level up past milestones",
+			previous: character(74),
+			current:  character(91),
+			expected: []string{domain.EventCharacterLevelUp, domain.EventCharacterMilestone, domain.EventCharacterMilestone},
+		},
+		{
+			name:     "unique item found",
+			previous: character(60, rare),
+			current:  character(60, rare, shako),
+			expected: []string{domain.EventItemFound},
+		},
+		{
+			name:     "rare item found",
+			previous: character(60),
+			current:  character(60, rare),
+		},
+		{
+			name:     "hardcore character died",
+			previous: character(60),
+			current:  dead,
+			expected: []string{domain.EventCharacterDied},
+		},
+		{
+			name:     "nothing happened",
+			previous: character(60, shako),
+			current:  character(60, shako),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &outboxRepositoryMock{
+				EnqueueFunc: func(ctx context.Context, deliveries []domain.Delivery) error {
+					return nil
+				},
+			}
+
+			s := NewService(outbox, []domain.WebhookTarget{{URL: "http://localhost/hook"}})
+			s.CharacterParsed(context.TODO(), tt.previous, tt.current)
+
+			calls := outbox.EnqueueCalls()
+			if len(calls) != len(tt.expected) {
+				t.Fatalf("expected %d events, got = %d", len(tt.expected), len(calls))
+			}
+
+			for i, call := range calls {
+				if got := call.Deliveries[0].EventType; got != tt.expected[i] {
+					t.Errorf("expected event %d to be %s, got = %s", i, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestEmit(t *testing.T) {
+	outbox := &outboxRepositoryMock{
+		EnqueueFunc: func(ctx context.Context, deliveries []domain.Delivery) error {
+			return nil
+		},
+	}
+
+	s := NewService(outbox, []domain.WebhookTarget{
+		{URL: "http://localhost/all"},
+		{URL: "http://localhost/deaths", Events: []string{domain.EventCharacterDied}},
+		{URL: "http://localhost/discord", Events: []string{domain.EventCharacterLevelUp}, Format: domain.WebhookFormatDiscord},
+	})
+
+	err := s.Emit(context.TODO(), domain.Event{
+		Type:       domain.EventCharacterLevelUp,
+		Character:  "nokka",
+		Message:    "nokka reached level 2",
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("didn't expect an error, got = %v", err)
+	}
+
+	deliveries := outbox.EnqueueCalls()[0].Deliveries
+	if len(deliveries) != 2 {
+		t.Fatalf("expected deliveries to the targets accepting the event only, got = %d", len(deliveries))
+	}
+
+	var event domain.Event
+	if err := json.Unmarshal(deliveries[0].Payload, &event); err != nil || event.ID == "" || event.ID != deliveries[0].EventID {
+		t.Errorf("expected the event as payload, got = %s", deliveries[0].Payload)
+	}
+
+	if string(deliveries[1].Payload) != `{"content":"nokka reached level 2"}` {
+		t.Errorf("expected a discord message as payload, got = %s", deliveries[1].Payload)
+	}
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/nokka/d2-armory-api/internal/domain"
+)
+
+// eventTypes are the event types targets can filter on.
+var eventTypes = map[string]struct{}{
+	domain.EventCharacterCreated:   {},
+	domain.EventCharacterLevelUp:   {},
+	domain.EventCharacterMilestone: {},
+	domain.EventCharacterDied:      {},
+	domain.EventItemFound:          {},
+	domain.EventStatisticsPosted:   {},
+}
+
+// ReadTargets reads a JSON file listing the webhook targets.
+//
+//	[{"url": "https://example.com/hook", "secret": "s3cr3t", "events": ["character.died"]}]
+func ReadTargets(path string) ([]domain.WebhookTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook targets file: %w", err)
+	}
+
+	var targets []domain.WebhookTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook targets file: %w", err)
+	}
+
+	seen := make(map[string]bool, len(targets))
+
+	for _, target := range targets {
+		u, err := url.Parse(target.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook url %q", target.URL)
+		}
+
+		if seen[target.URL] {
+			return nil, fmt.Errorf("webhook url %q is configured more than once", target.URL)
+		}
+		seen[target.URL] = true
+
+		switch target.Format {
+		case "", domain.WebhookFormatJSON, domain.WebhookFormatDiscord:
+		default:
+			return nil, fmt.Errorf("unknown format %q of webhook %s", target.Format, target.URL)
+		}
+
+		for _, event := range target.Events {
+			if _, ok := eventTypes[event]; !ok {
+				return nil, fmt.Errorf("unknown event %q of webhook %s", event, target.URL)
+			}
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		targets int
+		wantErr bool
+	}{
+		{
+			name:    "valid targets",
+			content: `[{"url":"https://example.com/hook","secret":"s3cr3t"},{"url":"https://discord.com/api/webhooks/1/abc","events":["character.died"],"format":"discord"}]`,
+			targets: 2,
+		},
+		{
+			name:    "unknown event",
+			content: `[{"url":"https://example.com/hook","events":["character.exploded"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "relative url",
+			content: `[{"url":"/hook"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate url",
+			content: `[{"url":"https://example.com/hook"},{"url":"https://example.com/hook"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "webhooks.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			targets, err := ReadTargets(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error to be %t, got = %v", tt.wantErr, err)
+			}
+
+			if len(targets) != tt.targets {
+				t.Errorf("expected %d targets, got = %d", tt.targets, len(targets))
+			}
+		})
+	}
+}